@@ -0,0 +1,330 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aggregate computes count, sum, average, minimum and maximum of the document fields,
+// grouped by the field values or by time and numeric buckets.
+// Documents are not retained, only the accumulators of the groups are kept in memory.
+package aggregate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tigrisdata/tigris-cli/schema"
+)
+
+// Time bucket units.
+const (
+	UnitMinute = "minute"
+	UnitHour   = "hour"
+	UnitDay    = "day"
+	UnitWeek   = "week"
+	UnitMonth  = "month"
+	UnitYear   = "year"
+)
+
+const countColumn = "count"
+
+var (
+	ErrInvalidBucket = fmt.Errorf("bucket should be field:unit, where unit is minute, hour, day, week, month, " +
+		"year or the width of numeric bucket")
+	ErrNoAggregates    = fmt.Errorf("at least one of count, sum, avg, min or max is required")
+	ErrDuplicateColumn = fmt.Errorf("result column is specified more than once, " +
+		"the field can't be both grouped by and bucketed")
+)
+
+// Bucket groups the documents by the time interval or numeric range of the field.
+type Bucket struct {
+	Field string
+	// Unit is the time unit of the date-time field bucket
+	Unit string
+	// Width is the width of the numeric field bucket
+	Width float64
+}
+
+// ParseBucket parses the bucket in the field:unit form, like created_at:day or price:100.
+func ParseBucket(s string) (*Bucket, error) {
+	i := strings.LastIndex(s, ":")
+	if i <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBucket, s)
+	}
+
+	b := &Bucket{Field: s[:i]}
+
+	switch unit := strings.ToLower(s[i+1:]); unit {
+	case UnitMinute, UnitHour, UnitDay, UnitWeek, UnitMonth, UnitYear:
+		b.Unit = unit
+	default:
+		w, err := strconv.ParseFloat(unit, 64)
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidBucket, s)
+		}
+
+		b.Width = w
+	}
+
+	return b, nil
+}
+
+// key returns the start of the bucket containing the value, or nil if the value doesn't fit the bucket type.
+func (b *Bucket) key(v any) any {
+	if b.Width > 0 {
+		f, ok := schema.NumberValue(v)
+		if !ok {
+			return nil
+		}
+
+		return math.Floor(f/b.Width) * b.Width
+	}
+
+	s, _ := v.(string)
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil
+	}
+
+	switch b.Unit {
+	case UnitMinute:
+		t = t.Truncate(time.Minute)
+	case UnitHour:
+		t = t.Truncate(time.Hour)
+	case UnitDay:
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case UnitWeek:
+		// weeks start on Monday
+		t = time.Date(t.Year(), t.Month(), t.Day()-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case UnitMonth:
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case UnitYear:
+		t = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// Spec defines the groups and the aggregates.
+type Spec struct {
+	GroupBy []string
+	Buckets []*Bucket
+	Count   bool
+	Sum     []string
+	Avg     []string
+	Min     []string
+	Max     []string
+}
+
+// Columns returns the names of the result fields in the order of the group fields, followed by the aggregates.
+func (s *Spec) Columns() []string {
+	res := append([]string{}, s.GroupBy...)
+
+	for _, b := range s.Buckets {
+		res = append(res, b.Field)
+	}
+
+	if s.Count {
+		res = append(res, countColumn)
+	}
+
+	for _, a := range []struct {
+		name   string
+		fields []string
+	}{{"sum", s.Sum}, {"avg", s.Avg}, {"min", s.Min}, {"max", s.Max}} {
+		for _, f := range a.fields {
+			res = append(res, a.name+"("+f+")")
+		}
+	}
+
+	return res
+}
+
+type sum struct {
+	sum   float64
+	count int64
+}
+
+type group struct {
+	key   []any
+	count int64
+	sums  []sum
+	avgs  []sum
+	mins  []any
+	maxs  []any
+}
+
+// Aggregator accumulates the documents into the groups.
+type Aggregator struct {
+	spec   *Spec
+	groups map[string]*group
+}
+
+func New(spec *Spec) (*Aggregator, error) {
+	if !spec.Count && len(spec.Sum)+len(spec.Avg)+len(spec.Min)+len(spec.Max) == 0 {
+		return nil, ErrNoAggregates
+	}
+
+	seen := make(map[string]bool)
+
+	for _, c := range spec.Columns() {
+		if seen[c] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateColumn, c)
+		}
+
+		seen[c] = true
+	}
+
+	return &Aggregator{spec: spec, groups: make(map[string]*group)}, nil
+}
+
+func (a *Aggregator) group(doc map[string]any) (*group, error) {
+	key := make([]any, 0, len(a.spec.GroupBy)+len(a.spec.Buckets))
+
+	for _, f := range a.spec.GroupBy {
+		key = append(key, lookup(doc, f))
+	}
+
+	for _, b := range a.spec.Buckets {
+		key = append(key, b.key(lookup(doc, b.Field)))
+	}
+
+	k, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	g, ok := a.groups[string(k)]
+	if !ok {
+		g = &group{
+			key:  key,
+			sums: make([]sum, len(a.spec.Sum)),
+			avgs: make([]sum, len(a.spec.Avg)),
+			mins: make([]any, len(a.spec.Min)),
+			maxs: make([]any, len(a.spec.Max)),
+		}
+		a.groups[string(k)] = g
+	}
+
+	return g, nil
+}
+
+// Add accumulates the document.
+func (a *Aggregator) Add(doc json.RawMessage) error {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+
+	g, err := a.group(m)
+	if err != nil {
+		return err
+	}
+
+	g.count++
+
+	accumulate(g.sums, a.spec.Sum, m)
+	accumulate(g.avgs, a.spec.Avg, m)
+
+	for i, f := range a.spec.Min {
+		if v := lookup(m, f); v != nil && (g.mins[i] == nil || schema.CompareValues(v, g.mins[i]) < 0) {
+			g.mins[i] = v
+		}
+	}
+
+	for i, f := range a.spec.Max {
+		if v := lookup(m, f); v != nil && (g.maxs[i] == nil || schema.CompareValues(v, g.maxs[i]) > 0) {
+			g.maxs[i] = v
+		}
+	}
+
+	return nil
+}
+
+func accumulate(sums []sum, fields []string, doc map[string]any) {
+	for i, f := range fields {
+		if v, ok := schema.NumberValue(lookup(doc, f)); ok {
+			sums[i].sum += v
+			sums[i].count++
+		}
+	}
+}
+
+// Results returns the rows of the groups sorted by the group key.
+func (a *Aggregator) Results() []map[string]any {
+	groups := make([]*group, 0, len(a.groups))
+	for _, g := range a.groups {
+		groups = append(groups, g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		for k := range groups[i].key {
+			if c := schema.CompareValues(groups[i].key[k], groups[j].key[k]); c != 0 {
+				return c < 0
+			}
+		}
+
+		return false
+	})
+
+	cols := a.spec.Columns()
+	res := make([]map[string]any, 0, len(groups))
+
+	for _, g := range groups {
+		vals := append([]any{}, g.key...)
+
+		if a.spec.Count {
+			vals = append(vals, g.count)
+		}
+
+		for _, s := range g.sums {
+			vals = append(vals, s.sum)
+		}
+
+		for _, s := range g.avgs {
+			if s.count == 0 {
+				vals = append(vals, nil)
+			} else {
+				vals = append(vals, s.sum/float64(s.count))
+			}
+		}
+
+		vals = append(vals, g.mins...)
+		vals = append(vals, g.maxs...)
+
+		row := make(map[string]any, len(cols))
+		for i, c := range cols {
+			row[c] = vals[i]
+		}
+
+		res = append(res, row)
+	}
+
+	return res
+}
+
+// lookup returns the value of the field, nested fields are separated by dots.
+func lookup(doc map[string]any, path string) any {
+	v, _ := schema.LookupValue(doc, path)
+
+	return v
+}
@@ -0,0 +1,157 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBucket(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp *Bucket
+		err error
+	}{
+		{"created_at:day", &Bucket{Field: "created_at", Unit: UnitDay}, nil},
+		{"meta.updated:Month", &Bucket{Field: "meta.updated", Unit: UnitMonth}, nil},
+		{"price:2.5", &Bucket{Field: "price", Width: 2.5}, nil},
+		{"price:0", nil, ErrInvalidBucket},
+		{"created_at:fortnight", nil, ErrInvalidBucket},
+		{"created_at", nil, ErrInvalidBucket},
+		{":day", nil, ErrInvalidBucket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			b, err := ParseBucket(tt.s)
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.exp, b)
+		})
+	}
+}
+
+func TestBucketKey(t *testing.T) {
+	tests := []struct {
+		bucket string
+		value  any
+		exp    any
+	}{
+		{"t:minute", "2023-03-15T10:20:30.5Z", "2023-03-15T10:20:00Z"},
+		{"t:hour", "2023-03-15T10:20:30Z", "2023-03-15T10:00:00Z"},
+		{"t:day", "2023-03-15T10:20:30+02:00", "2023-03-15T00:00:00+02:00"},
+		{"t:week", "2023-03-15T10:20:30Z", "2023-03-13T00:00:00Z"},
+		{"t:week", "2023-03-19T10:20:30Z", "2023-03-13T00:00:00Z"},
+		{"t:month", "2023-03-15T10:20:30Z", "2023-03-01T00:00:00Z"},
+		{"t:year", "2023-03-15T10:20:30Z", "2023-01-01T00:00:00Z"},
+		{"t:day", "yesterday", nil},
+		{"t:day", json.Number("1"), nil},
+		{"p:100", json.Number("250"), float64(200)},
+		{"p:100", json.Number("-1"), float64(-100)},
+		{"p:100", "250", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bucket, func(t *testing.T) {
+			b, err := ParseBucket(tt.bucket)
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, b.key(tt.value))
+		})
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	docs := []string{
+		`{"status": "new", "total": 10, "created_at": "2023-03-15T10:00:00Z", "customer": {"name": "Bob"}}`,
+		`{"status": "done", "total": 5.5, "created_at": "2023-03-15T12:00:00Z", "customer": {"name": "Alice"}}`,
+		`{"status": "new", "total": 20, "created_at": "2023-03-16T10:00:00Z", "customer": {"name": "Alice"}}`,
+		`{"status": "new", "created_at": "2023-03-16T11:00:00Z"}`,
+		`{"total": 1, "created_at": "2023-03-16T12:00:00Z"}`,
+	}
+
+	tests := []struct {
+		name string
+		spec Spec
+		cols []string
+		exp  string
+	}{
+		{
+			"count by status",
+			Spec{GroupBy: []string{"status"}, Count: true},
+			[]string{"status", "count"},
+			`[{"status": null, "count": 1}, {"status": "done", "count": 1}, {"status": "new", "count": 3}]`,
+		},
+		{
+			"sum and avg by day",
+			Spec{Buckets: []*Bucket{{Field: "created_at", Unit: UnitDay}}, Sum: []string{"total"}, Avg: []string{"total"}},
+			[]string{"created_at", "sum(total)", "avg(total)"},
+			`[
+				{"created_at": "2023-03-15T00:00:00Z", "sum(total)": 15.5, "avg(total)": 7.75},
+				{"created_at": "2023-03-16T00:00:00Z", "sum(total)": 21, "avg(total)": 10.5}
+			]`,
+		},
+		{
+			"min and max of nested group",
+			Spec{GroupBy: []string{"customer.name"}, Min: []string{"total", "created_at"}, Max: []string{"total"}},
+			[]string{"customer.name", "min(total)", "min(created_at)", "max(total)"},
+			`[
+				{"customer.name": null, "min(total)": 1, "min(created_at)": "2023-03-16T11:00:00Z", "max(total)": 1},
+				{"customer.name": "Alice", "min(total)": 5.5, "min(created_at)": "2023-03-15T12:00:00Z", "max(total)": 20},
+				{"customer.name": "Bob", "min(total)": 10, "min(created_at)": "2023-03-15T10:00:00Z", "max(total)": 10}
+			]`,
+		},
+		{
+			"total without groups",
+			Spec{Count: true, Avg: []string{"missing"}},
+			[]string{"count", "avg(missing)"},
+			`[{"count": 5, "avg(missing)": null}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(&tt.spec)
+			require.NoError(t, err)
+
+			for _, d := range docs {
+				require.NoError(t, a.Add(json.RawMessage(d)))
+			}
+
+			assert.Equal(t, tt.cols, tt.spec.Columns())
+
+			res, err := json.Marshal(a.Results())
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.exp, string(res))
+		})
+	}
+}
+
+func TestAggregatorErrors(t *testing.T) {
+	_, err := New(&Spec{GroupBy: []string{"status"}})
+	require.ErrorIs(t, err, ErrNoAggregates)
+
+	_, err = New(&Spec{GroupBy: []string{"created"}, Buckets: []*Bucket{{Field: "created", Unit: UnitDay}}, Count: true})
+	require.ErrorIs(t, err, ErrDuplicateColumn)
+
+	_, err = New(&Spec{Sum: []string{"price", "price"}})
+	require.ErrorIs(t, err, ErrDuplicateColumn)
+
+	a, err := New(&Spec{Count: true})
+	require.NoError(t, err)
+	require.Error(t, a.Add(json.RawMessage(`[1]`)))
+}
@@ -103,7 +103,24 @@ func Get() driver.Driver {
 	return D
 }
 
-func GetDB() driver.Database {
+type txKey struct{}
+
+// WithTx returns the context which makes GetDB and Transact run the requests in the transaction.
+func WithTx(ctx context.Context, tx driver.Tx) context.Context {
+	if tx == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// GetDB returns the database of the current project,
+// or the transaction if the context carries one.
+func GetDB(ctx context.Context) driver.Database {
+	if tx, ok := ctx.Value(txKey{}).(driver.Tx); ok {
+		return tx
+	}
+
 	return Get().UseDatabase(config.GetProjectName())
 }
 
@@ -148,6 +165,11 @@ func Transact(bctx context.Context, db string, fn func(ctx context.Context, tx d
 	ctx, cancel := util.GetContext(bctx)
 	defer cancel()
 
+	// join the interactive transaction, which is committed by the shell
+	if tx, ok := bctx.Value(txKey{}).(driver.Tx); ok {
+		return fn(ctx, tx)
+	}
+
 	tx, err := Get().UseDatabase(db).BeginTx(ctx)
 	if err != nil {
 		return util.Error(err, "begin transaction")
@@ -163,3 +185,25 @@ func Transact(bctx context.Context, db string, fn func(ctx context.Context, tx d
 
 	return util.Error(err, "commit transaction")
 }
+
+// UseBranch reinitializes the client to work with the given database branch.
+// The returned function restores the previous branch.
+func UseBranch(branch string) (func() error, error) {
+	prev := config.DefaultConfig.Branch
+
+	restore := func() error {
+		config.DefaultConfig.Branch = prev
+
+		return util.Error(Init(&config.DefaultConfig), "restore branch %s", prev)
+	}
+
+	config.DefaultConfig.Branch = branch
+
+	if err := Init(&config.DefaultConfig); err != nil {
+		_ = restore()
+
+		return nil, util.Error(err, "use branch %s", branch)
+	}
+
+	return restore, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/aggregate"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var (
+	aggGroupBy []string
+	aggBuckets []string
+	aggCount   bool
+	aggSum     []string
+	aggAvg     []string
+	aggMin     []string
+	aggMax     []string
+)
+
+// newAggSpec builds the aggregation from the flags.
+// The spec is built on every invocation, because the shell runs the command repeatedly.
+func newAggSpec() (*aggregate.Spec, error) {
+	spec := &aggregate.Spec{
+		GroupBy: aggGroupBy,
+		Count:   aggCount,
+		Sum:     aggSum,
+		Avg:     aggAvg,
+		Min:     aggMin,
+		Max:     aggMax,
+	}
+
+	for _, s := range aggBuckets {
+		b, err := aggregate.ParseBucket(s)
+		if err != nil {
+			return nil, err
+		}
+
+		spec.Buckets = append(spec.Buckets, b)
+	}
+
+	return spec, nil
+}
+
+func aggregateCollection(ctx context.Context, coll string, filter string) error {
+	spec, err := newAggSpec()
+	util.Fatal(err, "parse bucket")
+
+	agg, err := aggregate.New(spec)
+	util.Fatal(err, "aggregate")
+
+	out, err := util.NewOutput(os.Stdout, util.OutputTable)
+	util.Fatal(err, "output format")
+
+	it, err := client.GetDB(ctx).Read(ctx, coll, driver.Filter(filter), driver.Projection(`{}`))
+	if err != nil {
+		return util.Error(err, "read documents failed")
+	}
+	defer it.Close()
+
+	var doc driver.Document
+
+	for it.Next(&doc) {
+		err = agg.Add(json.RawMessage(doc))
+		util.Fatal(err, "aggregate document")
+	}
+
+	if err = it.Err(); err != nil {
+		return util.Error(err, "read documents")
+	}
+
+	out.SetColumns(spec.Columns())
+
+	for _, row := range agg.Results() {
+		err = out.Write(row)
+		util.Fatal(err, "write result")
+	}
+
+	return util.Error(out.Flush(), "flush output")
+}
+
+var aggregateCmd = &cobra.Command{
+	Use:   "aggregate {collection} [filter]",
+	Short: "Aggregates documents of the collection",
+	Long: `Reads documents matching the filter and computes count, sum, average, minimum and maximum
+of the fields per group of documents.
+
+Documents are grouped by the values of --group-by fields and by --bucket intervals.
+Bucket is the field name and either time unit (minute, hour, day, week, month, year)
+of the date-time field or the width of the numeric field range, like price:100.
+Documents are streamed, only the aggregates of the groups are kept in memory.
+Results are printed as a table, unless --output is set.`,
+	Example: fmt.Sprintf(`
+  # Count orders per status
+  %[1]s aggregate --project=myproj orders --group-by status --count
+
+  # Sum and average of order totals per day
+  %[1]s aggregate --project=myproj orders --bucket created_at:day --sum order_total --avg order_total
+
+  # Largest order per customer in 2023, as JSON
+  %[1]s aggregate --project=myproj orders --where "created_at >= date '2023-01-01'" \
+    --group-by customer_id --max order_total --output json
+`, rootCmd.Root().Name()),
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		args, explain := whereFilter(args, 1)
+		if explain {
+			return
+		}
+
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			filter := `{}`
+			if len(args) > 1 {
+				filter = args[1]
+			}
+
+			return aggregateCollection(ctx, args[0], filter)
+		})
+	},
+}
+
+func init() {
+	aggregateCmd.Flags().StringSliceVar(&aggGroupBy, "group-by", nil,
+		"group documents by the values of the fields, nested fields are separated by dots")
+	aggregateCmd.Flags().StringArrayVar(&aggBuckets, "bucket", nil,
+		"group documents by the field interval: created_at:day or price:100. can be repeated")
+	aggregateCmd.Flags().BoolVar(&aggCount, "count", false, "count documents of the group")
+	aggregateCmd.Flags().StringSliceVar(&aggSum, "sum", nil, "sum of the numeric fields")
+	aggregateCmd.Flags().StringSliceVar(&aggAvg, "avg", nil, "average of the numeric fields")
+	aggregateCmd.Flags().StringSliceVar(&aggMin, "min", nil, "minimum of the fields")
+	aggregateCmd.Flags().StringSliceVar(&aggMax, "max", nil, "maximum of the fields")
+
+	addWhereFlags(aggregateCmd)
+	addProjectFlag(aggregateCmd)
+	rootCmd.AddCommand(aggregateCmd)
+}
@@ -48,7 +48,7 @@ var createBranchCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			_, err := client.GetDB().CreateBranch(ctx, args[0])
+			_, err := client.GetDB(ctx).CreateBranch(ctx, args[0])
 			if err != nil {
 				return util.Error(err, "create branch")
 			}
@@ -70,7 +70,7 @@ var deleteBranchCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			_, err := client.GetDB().DeleteBranch(ctx, args[0])
+			_, err := client.GetDB(ctx).DeleteBranch(ctx, args[0])
 			if err != nil {
 				return util.Error(err, "delete branch")
 			}
@@ -163,7 +163,7 @@ var checkoutBranchCmd = &cobra.Command{
 
 		if createBranch {
 			if !found {
-				_, err = client.GetDB().CreateBranch(ctx, args[0])
+				_, err = client.GetDB(ctx).CreateBranch(ctx, args[0])
 				util.Fatal(err, "create branch on checkout")
 
 				util.Infof("New branch created: %s", args[0])
@@ -201,10 +201,10 @@ var resetBranchCmd = &cobra.Command{
 			util.Fatal(ErrBranchNotFound, "reset branch")
 		}
 
-		_, err = client.GetDB().DeleteBranch(ctx, args[0])
+		_, err = client.GetDB(ctx).DeleteBranch(ctx, args[0])
 		util.Fatal(err, "delete branch on reset")
 
-		_, err = client.GetDB().CreateBranch(ctx, args[0])
+		_, err = client.GetDB(ctx).CreateBranch(ctx, args[0])
 		util.Fatal(err, "create branch on reset")
 	},
 }
@@ -29,7 +29,11 @@ import (
 	"github.com/tigrisdata/tigris-client-go/driver"
 )
 
-var ErrSchemaNameMissing = fmt.Errorf("schema name is missing")
+var (
+	checkSchema bool
+
+	ErrSchemaNameMissing = fmt.Errorf("schema name is missing")
+)
 
 func createCollection(ctx context.Context, tx driver.Tx, raw driver.Schema) error {
 	type Schema struct {
@@ -65,7 +69,7 @@ var describeCollectionCmd = &cobra.Command{
 	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			resp, err := client.GetDB().DescribeCollection(ctx, args[0],
+			resp, err := client.GetDB(ctx).DescribeCollection(ctx, args[0],
 				&driver.DescribeCollectionOptions{SchemaFormat: format})
 			if err != nil {
 				return util.Error(err, "describe collection")
@@ -77,7 +81,7 @@ var describeCollectionCmd = &cobra.Command{
 				Schema: resp.Schema,
 			}
 
-			err = util.PrettyJSON(tr)
+			err = util.PrintOutput(util.OutputJSON, tr)
 			util.Fatal(err, "describe collection marshal")
 
 			return nil
@@ -90,16 +94,12 @@ var listCollectionsCmd = &cobra.Command{
 	Short: "Lists project collections",
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			resp, err := client.GetDB().ListCollections(ctx)
+			resp, err := client.GetDB(ctx).ListCollections(ctx)
 			if err != nil {
 				return util.Error(err, "list collections")
 			}
 
-			for _, v := range resp {
-				util.Stdoutf("%s\n", v)
-			}
-
-			return nil
+			return util.Error(util.PrintList("collection", resp), "list collections")
 		})
 	},
 }
@@ -196,7 +196,7 @@ var dropCollectionCmd = &cobra.Command{
 }
 
 var alterCollectionCmd = &cobra.Command{
-	Use:   "collection {schema}",
+	Use:   "collection {schema}...|-",
 	Short: "Updates collection schema",
 	Long:  "Updates collection schema.",
 	Example: fmt.Sprintf(`
@@ -219,12 +219,18 @@ var alterCollectionCmd = &cobra.Command{
 	  "id"
 	]
   }'
+
+  # Check compatibility of the schema from the file without applying it
+  %[1]s alter collection --project=myproj --check </home/alice/users.json
 `, rootCmd.Root().Name()),
-	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			if checkSchema {
+				return checkSchemasInput(ctx, cmd, 0, args)
+			}
+
 			return client.Transact(ctx, config.GetProjectName(), func(ctx context.Context, tx driver.Tx) error {
-				return iterate.Input(ctx, cmd, 1, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
+				return iterate.Input(ctx, cmd, 0, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
 					for _, v := range docs {
 						if err := createCollection(ctx, tx, driver.Schema(v)); err != nil {
 							return err
@@ -245,6 +251,9 @@ func init() {
 	addProjectFlag(alterCollectionCmd)
 	addProjectFlag(describeCollectionCmd)
 
+	alterCollectionCmd.Flags().BoolVar(&checkSchema, "check", false,
+		"check compatibility of the schema with the existing collection without applying it")
+
 	describeCollectionCmd.Flags().StringVarP(&format, "format", "f", "",
 		"output schema in the requested format: go, typescript, java")
 
@@ -0,0 +1,68 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var countCmd = &cobra.Command{
+	Use:   "count {collection} {filter}",
+	Short: "Counts documents",
+	Long:  "Counts documents matching the filter. All documents are counted if filter is not provided.",
+	Example: fmt.Sprintf(`
+  # Count users older than 23
+  %[1]s count --project=myproj users '{"age": {"$gt": 23}}'
+
+  # Same count using filter expression
+  %[1]s count --project=myproj users --where "age > 23"
+`, rootCmd.Root().Name()),
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		args, explain := whereFilter(args, 1)
+		if explain {
+			return
+		}
+
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			filter := `{}`
+			if len(args) > 1 {
+				filter = args[1]
+			}
+
+			cnt, err := client.GetDB(ctx).Count(ctx, args[0], driver.Filter(filter))
+			if err != nil {
+				return util.Error(err, "count documents")
+			}
+
+			util.Stdoutf("%d\n", cnt)
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	addWhereFlags(countCmd)
+	addProjectFlag(countCmd)
+	rootCmd.AddCommand(countCmd)
+}
@@ -35,17 +35,26 @@ var deleteCmd = &cobra.Command{
 
   # Delete users where the value of id field is 1 or 3
   %[1]s delete --project=myproj users '{"$or": [{"id": 1}, {"id": 3}]}'
+
+  # Same delete using filter expression, print generated filter without deleting
+  %[1]s delete --project=myproj users --where "id in (1, 3)" --explain-filter
 `, rootCmd.Root().Name()),
-	Args: cobra.MinimumNArgs(2),
+	Args: whereArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
+		args, explain := whereFilter(args, 1)
+		if explain {
+			return
+		}
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			_, err := client.GetDB().Delete(ctx, args[0], driver.Filter(args[1]))
+			_, err := client.GetDB(ctx).Delete(ctx, args[0], driver.Filter(args[1]))
 			return util.Error(err, "delete documents")
 		})
 	},
 }
 
 func init() {
+	addWhereFlags(deleteCmd)
 	addProjectFlag(deleteCmd)
 	rootCmd.AddCommand(deleteCmd)
 }
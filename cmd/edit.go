@@ -0,0 +1,380 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	cschema "github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const defaultEditor = "vi"
+
+var (
+	editLimit int64
+
+	ErrTooManyDocuments = fmt.Errorf("too many documents match the filter, narrow the filter or increase --limit")
+	ErrKeyChanged       = fmt.Errorf("primary key of the edited document doesn't match any of the read documents")
+	ErrConcurrentChange = fmt.Errorf("document has been changed since it was read, edit aborted")
+)
+
+// editDoc is the document read for editing.
+type editDoc struct {
+	key      string
+	filter   driver.Filter
+	original any
+}
+
+func decodeDoc(doc []byte) (any, error) {
+	var v any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	err := dec.Decode(&v)
+
+	return v, err
+}
+
+// newEditDoc returns the document with the key, which identifies the edited document,
+// and the primary key filter, which reads it again to detect concurrent change.
+func newEditDoc(pk []string, doc json.RawMessage) (*editDoc, error) {
+	v, err := decodeDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	m, _ := v.(map[string]any)
+
+	vals := make([]any, 0, len(pk))
+	conds := make([]map[string]any, 0, len(pk))
+
+	for _, k := range pk {
+		// nested key fields are addressed by the dotted path both in the document and in the filter
+		kv, _ := schema.LookupValue(m, k)
+
+		vals = append(vals, kv)
+		conds = append(conds, map[string]any{k: kv})
+	}
+
+	key, err := json.Marshal(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter []byte
+
+	if len(conds) == 1 {
+		filter, err = json.Marshal(conds[0])
+	} else {
+		filter, err = json.Marshal(map[string]any{"$and": conds})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &editDoc{key: string(key), filter: filter, original: v}, nil
+}
+
+func readForEdit(ctx context.Context, coll string, filter string) ([]json.RawMessage, error) {
+	it, err := client.GetDB(ctx).Read(ctx, coll, driver.Filter(filter), driver.Projection(`{}`),
+		&driver.ReadOptions{Limit: editLimit + 1})
+	if err != nil {
+		return nil, util.Error(err, "read documents failed")
+	}
+	defer it.Close()
+
+	var (
+		doc  driver.Document
+		docs []json.RawMessage
+	)
+
+	for it.Next(&doc) {
+		docs = append(docs, json.RawMessage(doc))
+	}
+
+	if err = it.Err(); err != nil {
+		return nil, util.Error(err, "read documents")
+	}
+
+	if int64(len(docs)) > editLimit {
+		return nil, ErrTooManyDocuments
+	}
+
+	return docs, nil
+}
+
+// runEditor opens the documents as pretty JSON in $VISUAL or $EDITOR,
+// single document as an object and multiple documents as an array.
+// Returns the edited documents and the temporary file, which is kept to not lose the edits on error.
+func runEditor(docs []json.RawMessage) ([]json.RawMessage, string, error) {
+	var v any = docs
+	if len(docs) == 1 {
+		v = docs[0]
+	}
+
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.CreateTemp("", "tigris-edit-*.json")
+	if err != nil {
+		return nil, "", err
+	}
+
+	_, err = f.Write(append(content, '\n'))
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+
+	if err != nil {
+		return nil, f.Name(), err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	// editor can have arguments, like "code --wait"
+	args := strings.Fields(editor)
+
+	c := exec.Command(args[0], append(args[1:], f.Name())...) //nolint:gosec
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err = c.Run(); err != nil {
+		return nil, f.Name(), err
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return nil, f.Name(), err
+	}
+
+	edited = bytes.TrimSpace(edited)
+
+	if len(edited) > 0 && edited[0] == '[' {
+		var res []json.RawMessage
+		err = json.Unmarshal(edited, &res)
+
+		return res, f.Name(), err
+	}
+
+	return []json.RawMessage{edited}, f.Name(), nil
+}
+
+// changedDocs validates the edited documents and returns the ones which differ from the read documents.
+func changedDocs(sch *cschema.Schema, read map[string]*editDoc, edited []json.RawMessage,
+) ([]driver.Document, []*editDoc, error) {
+	var (
+		docs []driver.Document
+		orig []*editDoc
+	)
+
+	for _, doc := range edited {
+		e, err := newEditDoc(sch.PrimaryKey, doc)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r, ok := read[e.key]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrKeyChanged, e.key)
+		}
+
+		if reflect.DeepEqual(r.original, e.original) {
+			continue
+		}
+
+		if err = schema.Validate(sch, doc); err != nil {
+			return nil, nil, err
+		}
+
+		docs = append(docs, driver.Document(doc))
+		orig = append(orig, r)
+	}
+
+	return docs, orig, nil
+}
+
+// checkUnchanged reads the documents in the transaction and fails if they differ from the originally read ones.
+func checkUnchanged(ctx context.Context, tx driver.Tx, coll string, docs []*editDoc) error {
+	for _, d := range docs {
+		it, err := tx.Read(ctx, coll, d.filter, driver.Projection(`{}`))
+		if err != nil {
+			return util.Error(err, "read document")
+		}
+
+		var (
+			doc     driver.Document
+			current any
+		)
+
+		if it.Next(&doc) {
+			current, err = decodeDoc(doc)
+		}
+
+		if err1 := it.Err(); err == nil {
+			err = err1
+		}
+
+		it.Close()
+
+		if err != nil {
+			return util.Error(err, "read document")
+		}
+
+		if !reflect.DeepEqual(current, d.original) {
+			return fmt.Errorf("%w: %s", ErrConcurrentChange, d.key)
+		}
+	}
+
+	return nil
+}
+
+func editDocuments(ctx context.Context, bctx context.Context, coll string, filter string) error {
+	resp, err := client.GetDB(ctx).DescribeCollection(ctx, coll)
+	if err != nil {
+		return util.Error(err, "describe collection")
+	}
+
+	var sch cschema.Schema
+	if err = json.Unmarshal(resp.Schema, &sch); err != nil {
+		return util.Error(err, "unmarshal schema")
+	}
+
+	docs, err := readForEdit(ctx, coll, filter)
+	if err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		util.Infof("No documents match the filter")
+		return nil
+	}
+
+	read := make(map[string]*editDoc, len(docs))
+
+	for _, doc := range docs {
+		e, err := newEditDoc(sch.PrimaryKey, doc)
+		if err != nil {
+			return err
+		}
+
+		read[e.key] = e
+	}
+
+	edited, tmp, err := runEditor(docs)
+	if err != nil {
+		return editFailed(tmp, util.Error(err, "edit documents"))
+	}
+
+	changed, orig, err := changedDocs(&sch, read, edited)
+	if err != nil {
+		return editFailed(tmp, err)
+	}
+
+	if len(changed) == 0 {
+		_ = os.Remove(tmp)
+
+		util.Infof("No changes made")
+
+		return nil
+	}
+
+	// editing can take longer than the request timeout, so the transaction uses the command context
+	err = client.Transact(bctx, config.GetProjectName(), func(ctx context.Context, tx driver.Tx) error {
+		if err := checkUnchanged(ctx, tx, coll, orig); err != nil {
+			return err
+		}
+
+		_, err := tx.Replace(ctx, coll, changed)
+
+		return util.Error(err, "replace documents")
+	})
+	if err != nil {
+		return editFailed(tmp, err)
+	}
+
+	_ = os.Remove(tmp)
+
+	util.Infof("Updated %d document(s)", len(changed))
+
+	return nil
+}
+
+// editFailed tells where the edits are saved, so they are not lost.
+func editFailed(tmp string, err error) error {
+	if tmp != "" {
+		util.Stderrf("edited documents are saved in %s\n", tmp)
+	}
+
+	return err
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit {collection} {filter}",
+	Short: "Edits documents in the text editor",
+	Long: `Reads documents matching the filter and opens them in $VISUAL or $EDITOR as JSON.
+
+Changed documents are validated against the collection schema and replaced in a transaction.
+The edit is aborted if the documents have been changed by someone else since they were read.
+Primary key fields cannot be changed.`,
+	Example: fmt.Sprintf(`
+  # Edit the user with id 20
+  %[1]s edit --project=myproj users '{"id": 20}'
+
+  # Edit inactive users using filter expression
+  EDITOR="code --wait" %[1]s edit --project=myproj users --where "active = false" --limit 10
+`, rootCmd.Root().Name()),
+	Args: whereArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		args, explain := whereFilter(args, 1)
+		if explain {
+			return
+		}
+
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			return editDocuments(ctx, cmd.Context(), args[0], args[1])
+		})
+	},
+}
+
+func init() {
+	editCmd.Flags().Int64VarP(&editLimit, "limit", "l", 100, "maximum number of documents to edit")
+	addWhereFlags(editCmd)
+	addProjectFlag(editCmd)
+	rootCmd.AddCommand(editCmd)
+}
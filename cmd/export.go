@@ -0,0 +1,192 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/export"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/paginate"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var (
+	exportFormat  string
+	exportOut     string
+	exportSort    []string
+	exportTimeout int
+)
+
+// exportFileFormat returns the format requested by --format flag,
+// or detected from the --out file extension.
+func exportFileFormat() string {
+	if exportFormat != "" {
+		return exportFormat
+	}
+
+	switch ext := strings.TrimPrefix(filepath.Ext(exportOut), "."); ext {
+	case export.FormatCSV, export.FormatJSON, export.FormatParquet:
+		return ext
+	}
+
+	return export.FormatNDJSON
+}
+
+func exportCollection(ctx context.Context, w io.Writer, coll string, filter string, fields string) (int, error) {
+	resp, err := client.GetDB(ctx).DescribeCollection(ctx, coll)
+	if err != nil {
+		return 0, util.Error(err, "describe collection")
+	}
+
+	order, err := paginate.ParseSort(exportSort)
+	if err != nil {
+		return 0, err
+	}
+
+	ew, err := export.NewWriter(w, exportFileFormat(), resp.Schema, []byte(fields))
+	if err != nil {
+		return 0, util.Error(err, "export writer")
+	}
+
+	it, err := client.GetDB(ctx).Read(ctx, coll,
+		driver.Filter(filter),
+		driver.Projection(fields),
+		&driver.ReadOptions{Sort: order.JSON()},
+	)
+	if err != nil {
+		return 0, util.Error(err, "read documents")
+	}
+	defer it.Close()
+
+	var (
+		doc driver.Document
+		cnt int
+	)
+
+	for it.Next(&doc) {
+		if err = ew.Write(json.RawMessage(doc)); err != nil {
+			return cnt, util.Error(err, "write document")
+		}
+
+		cnt++
+	}
+
+	if err = it.Err(); err != nil {
+		return cnt, util.Error(err, "read documents")
+	}
+
+	return cnt, util.Error(ew.Close(), "finish export")
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export {collection} [filter] [fields]",
+	Short: "Exports documents of the collection to a file",
+	Long: `Exports documents matching the filter to CSV, JSON array, NDJSON or Parquet.
+
+CSV columns follow the order of the fields in the collection schema,
+nested objects are flattened to dotted column names, like "address.city",
+arrays are written as JSON and missing fields as null. CSV, JSON and NDJSON
+output can be loaded back by the import command.`,
+	Example: fmt.Sprintf(`
+  # Export the users collection to CSV file
+  %[1]s export --project=myproj users --out users.csv
+
+  # Export users older than 30, sorted by name, to stdout as JSON array
+  %[1]s export --project=myproj users '{"age": {"$gt": 30}}' --format json --sort '{"name": "$asc"}'
+
+  # Export only name and address fields to Parquet file
+  %[1]s export --project=myproj users '{}' '{"name": true, "address": true}' --out users.parquet
+
+  # Export and import back into another collection
+  %[1]s export --project=myproj users --format csv | %[1]s import --project=myproj users_copy
+`, rootCmd.Root().Name()),
+	Args: cobra.RangeArgs(1, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(_ context.Context) error {
+			filter, fields := `{}`, `{}`
+
+			if len(args) > 1 {
+				filter = args[1]
+			}
+
+			if len(args) > 2 {
+				fields = args[2]
+			}
+
+			err := iterate.CSVConfigure(CSVDelimiter, "", false, false)
+			util.Fatal(err, "csv configure")
+
+			f := os.Stdout
+
+			if exportOut != "" && exportOut != "-" {
+				f, err = os.Create(exportOut)
+				util.Fatal(err, "create output file")
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), time.Duration(exportTimeout)*time.Second)
+			defer cancel()
+
+			w := bufio.NewWriter(f)
+
+			cnt, err := exportCollection(ctx, w, args[0], filter, fields)
+			if err != nil {
+				return err
+			}
+
+			if err = w.Flush(); err != nil {
+				return util.Error(err, "flush output")
+			}
+
+			if f != os.Stdout {
+				if err = f.Close(); err != nil {
+					return util.Error(err, "close output file")
+				}
+
+				util.Infof("Exported %d documents to %s", cnt, exportOut)
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "",
+		"Output format: csv, json, ndjson or parquet. Detected from the output file extension if not set")
+	exportCmd.Flags().StringVar(&exportOut, "out", "",
+		"Output file. Standard output is used if not set")
+	exportCmd.Flags().StringArrayVar(&exportSort, "sort", nil,
+		`Sort order of the documents, can be repeated: --sort '{"name": "$asc"}'`)
+	exportCmd.Flags().StringVar(&CSVDelimiter, "csv-delimiter", "",
+		"CSV delimiter")
+	exportCmd.Flags().IntVarP(&exportTimeout, "timeout", "t", 3600,
+		"timeout specification in seconds")
+
+	addProjectFlag(exportCmd)
+	rootCmd.AddCommand(exportCmd)
+}
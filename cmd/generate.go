@@ -17,6 +17,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
@@ -24,6 +27,7 @@ import (
 	"github.com/tigrisdata/tigris-cli/login"
 	"github.com/tigrisdata/tigris-cli/scaffold"
 	"github.com/tigrisdata/tigris-cli/util"
+	cschema "github.com/tigrisdata/tigris-client-go/schema"
 )
 
 const sampleDBName = "sampledb"
@@ -31,6 +35,16 @@ const sampleDBName = "sampledb"
 var (
 	create bool
 	stdout bool
+
+	modelsLang      string
+	modelsPkg       string
+	modelsOutDir    string
+	modelsSchemas   []string
+	defaultModelPkg = map[string]string{
+		"go":   "model",
+		"ts":   "models",
+		"java": "collections",
+	}
 )
 
 var sampleSchemaCmd = &cobra.Command{
@@ -64,6 +78,88 @@ var sampleSchemaCmd = &cobra.Command{
 	},
 }
 
+// readModelSchemas reads schemas from the files, file names can be glob patterns.
+func readModelSchemas(files []string) ([]*cschema.Schema, error) {
+	colls := make(map[string]*cschema.Schema)
+
+	for _, pattern := range files {
+		names, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if names == nil {
+			names = []string{pattern} // let read fail with file not found
+		}
+
+		for _, name := range names {
+			m, err := readSchemaFile(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, name)
+			}
+
+			for k, v := range m {
+				colls[k] = v
+			}
+		}
+	}
+
+	return scaffold.SortedSchemas(colls), nil
+}
+
+var modelsCmd = &cobra.Command{
+	Use:   "models [schema file]...",
+	Short: "Generates language models from the schema files",
+	Long: `Generates Go structs, TypeScript, Java or Python classes and Rust structs
+from the collection schemas.
+Generation is done locally and doesn't require connection to the server.
+Files are written to the output directory, one file per collection,
+or printed to the standard output, if the directory is not set.`,
+	Example: fmt.Sprintf(`
+  # Generate Go models and print them to stdout
+  %[1]s generate models --lang go --schema './schemas/*.json'
+
+  # Generate TypeScript models into the ./src/models directory
+  %[1]s generate models --lang ts --output-directory ./src/models ./schemas/*.json
+`, rootCmd.Root().Name()),
+	Run: func(cmd *cobra.Command, args []string) {
+		lang := langMap[strings.ToLower(modelsLang)]
+		if lang == "" {
+			util.Fatal(scaffold.ErrUnsupportedFormat, "unsupported language: %s", modelsLang)
+		}
+
+		pkg := modelsPkg
+		if pkg == "" {
+			pkg = defaultModelPkg[lang]
+		}
+
+		schemas, err := readModelSchemas(append(modelsSchemas, args...))
+		util.Fatal(err, "read schemas")
+
+		if modelsOutDir != "" {
+			err = os.MkdirAll(modelsOutDir, 0o755)
+			util.Fatal(err, "create output directory: %s", modelsOutDir)
+		}
+
+		for _, sch := range schemas {
+			name, body, err := scaffold.ModelFile(lang, pkg, sch)
+			util.Fatal(err, "generate model: %s", sch.Name)
+
+			if modelsOutDir == "" {
+				util.Stdoutf("%s", body)
+				continue
+			}
+
+			name = filepath.Join(modelsOutDir, name)
+
+			err = os.WriteFile(name, []byte(body), 0o600)
+			util.Fatal(err, "write model file: %s", name)
+
+			util.Infof("Generated %s", name)
+		}
+	},
+}
+
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generating helper assets such as sample schema",
@@ -74,6 +170,14 @@ func init() {
 	sampleSchemaCmd.Flags().BoolVarP(&stdout, "stdout", "s", false, "dump sample schemas to stdout")
 	addProjectFlag(sampleSchemaCmd)
 
+	modelsCmd.Flags().StringVarP(&modelsLang, "lang", "l", "typescript",
+		"Language of the models. Possible values are: TypeScript, Golang, Java, Python, Rust")
+	modelsCmd.Flags().StringSliceVar(&modelsSchemas, "schema", nil, "Schema files or glob patterns")
+	modelsCmd.Flags().StringVarP(&modelsOutDir, "output-directory", "o", "",
+		"Directory where to write the model files. Models are printed to stdout if not set")
+	modelsCmd.Flags().StringVarP(&modelsPkg, "package-name", "n", "", "Package name of the generated models")
+
 	generateCmd.AddCommand(sampleSchemaCmd)
+	generateCmd.AddCommand(modelsCmd)
 	rootCmd.AddCommand(generateCmd)
 }
@@ -41,6 +41,7 @@ var (
 	InferenceDepth int32
 	PrimaryKey     []string
 	AutoGenerate   []string
+	AutoPrimaryKey bool
 
 	CleanUpNULLs = true
 
@@ -61,7 +62,7 @@ var (
 	FirstRecord = true
 )
 
-func evolveSchema(ctx context.Context, db string, coll string, docs []json.RawMessage) error {
+func evolveSchema(ctx context.Context, coll string, docs []json.RawMessage) error {
 	// Allow to reduce inference depth in the case of huge batches
 	id := len(docs)
 	if InferenceDepth > 0 {
@@ -71,20 +72,45 @@ func evolveSchema(ctx context.Context, db string, coll string, docs []json.RawMe
 	err := schema.Infer(&sch, coll, docs, PrimaryKey, AutoGenerate, id)
 	util.Fatal(err, "infer schema")
 
+	if AutoPrimaryKey && len(sch.PrimaryKey) == 0 {
+		applyPrimaryKeySuggestion(docs, id)
+	}
+
 	b, err := json.Marshal(sch)
 	util.Fatal(err, "marshal schema: %s", string(b))
 
-	err = client.Get().UseDatabase(db).CreateOrUpdateCollection(ctx, coll, b)
+	err = client.GetDB(ctx).CreateOrUpdateCollection(ctx, coll, b)
 
 	return util.Error(err, "create or update collection")
 }
 
+func applyPrimaryKeySuggestion(docs []json.RawMessage, depth int) {
+	if depth < len(docs) {
+		docs = docs[:depth]
+	}
+
+	candidates, err := schema.SuggestPrimaryKey(docs)
+	util.Fatal(err, "suggest primary key")
+
+	if len(candidates) == 0 {
+		log.Debug().Msg("no primary key candidates found")
+		return
+	}
+
+	candidates[0].Apply(&sch)
+
+	// Retain the key for the subsequent batches
+	PrimaryKey = sch.PrimaryKey
+
+	util.Infof("Using primary key: %s", candidates[0])
+}
+
 func writeInitRecord(ctx context.Context, coll string, docs []json.RawMessage) {
 	if !FirstRecord {
 		return
 	}
 
-	cnt, err := client.GetDB().Count(ctx, coll, driver.Filter("{}"))
+	cnt, err := client.GetDB(ctx).Count(ctx, coll, driver.Filter("{}"))
 	if err != nil {
 		var ep *driver.Error
 		if errors.As(err, &ep) && ep.Code == api.Code_NOT_FOUND {
@@ -128,7 +154,7 @@ func insertWithInference(ctx context.Context, coll string, docs []json.RawMessag
 
 	ptr := unsafe.Pointer(&docs)
 
-	_, err := client.GetDB().Insert(ctx, coll, *(*[]driver.Document)(ptr))
+	_, err := client.GetDB(ctx).Insert(ctx, coll, *(*[]driver.Document)(ptr))
 	if err == nil {
 		return nil // successfully inserted batch
 	}
@@ -141,12 +167,12 @@ func insertWithInference(ctx context.Context, coll string, docs []json.RawMessag
 		return util.Error(err, "import documents (initial)")
 	}
 
-	if err = evolveSchema(ctx, config.GetProjectName(), coll, docs); err != nil {
+	if err = evolveSchema(ctx, coll, docs); err != nil {
 		return err
 	}
 
 	// retry after schema update
-	_, err = client.GetDB().Insert(ctx, coll, *(*[]driver.Document)(ptr))
+	_, err = client.GetDB(ctx).Insert(ctx, coll, *(*[]driver.Document)(ptr))
 	if err == nil {
 		return nil
 	}
@@ -157,7 +183,7 @@ func insertWithInference(ctx context.Context, coll string, docs []json.RawMessag
 		}
 	}
 
-	_, err = client.GetDB().Insert(ctx, coll, *(*[]driver.Document)(ptr))
+	_, err = client.GetDB(ctx).Insert(ctx, coll, *(*[]driver.Document)(ptr))
 
 	log.Debug().Interface("docs", docs).Msg("import")
 
@@ -174,6 +200,7 @@ Automatically:
   * Detect the schema of the documents
   * Create collection with inferred schema
   * Evolve the schema as soon as it's backward compatible
+  * Detect primary key, when --auto-primary-key is set
 `,
 	Example: fmt.Sprintf(`
   %[1]s import --project=myproj users --primary-key=id \
@@ -185,7 +212,7 @@ Automatically:
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			resp, err := client.GetDB().DescribeCollection(ctx, args[0])
+			resp, err := client.GetDB(ctx).DescribeCollection(ctx, args[0])
 			if err == nil {
 				if !Append {
 					util.Fatal(ErrNoAppend, "describe collection")
@@ -216,9 +243,12 @@ func init() {
 	importCmd.Flags().Int32VarP(&InferenceDepth, "inference-depth", "d", 0,
 		"Number of records in the beginning of the stream to detect field types. It's equal to batch size if not set")
 	importCmd.Flags().StringSliceVar(&PrimaryKey, "primary-key", []string{},
-		"Comma separated list of field names which constitutes collection's primary key (only top level keys supported)")
+		"Comma separated list of field names which constitutes collection's primary key. "+
+			"Use dots for nested fields: meta.uuid")
 	importCmd.Flags().StringSliceVar(&AutoGenerate, "autogenerate", []string{},
-		"Comma separated list of autogenerated fields (only top level keys supported)")
+		"Comma separated list of autogenerated fields. Use dots for nested fields: meta.uuid")
+	importCmd.Flags().BoolVar(&AutoPrimaryKey, "auto-primary-key", false,
+		"Detect primary key from the documents, add autogenerated id field if no candidate found")
 	importCmd.Flags().BoolVar(&CleanUpNULLs, "cleanup-null-values", true,
 		"Remove NULL values and empty arrays from the documents before importing")
 
@@ -237,6 +267,10 @@ func init() {
 		"Try detect date time fields")
 	importCmd.Flags().BoolVar(&schema.DetectIntegers, "detect-integers", true,
 		"Try detect integer fields")
+	importCmd.Flags().BoolVar(&schema.DetectVectors, "detect-vectors", false,
+		"Try detect vector fields. Arrays of numbers of the same length are considered vectors")
+	importCmd.Flags().IntVar(&schema.VectorMinDimensions, "vector-min-dimensions", schema.VectorMinDimensions,
+		"Minimum number of array elements to be detected as vector")
 
 	addProjectFlag(importCmd)
 	rootCmd.AddCommand(importCmd)
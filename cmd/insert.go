@@ -56,7 +56,7 @@ var insertCmd = &cobra.Command{
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			return iterate.Input(ctx, cmd, 1, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
 				ptr := unsafe.Pointer(&docs)
-				_, err := client.GetDB().Insert(ctx, args[0], *(*[]driver.Document)(ptr))
+				_, err := client.GetDB(ctx).Insert(ctx, args[0], *(*[]driver.Document)(ptr))
 
 				return util.Error(err, "insert documents")
 			})
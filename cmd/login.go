@@ -15,8 +15,6 @@
 package cmd
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/config"
 	"github.com/tigrisdata/tigris-cli/login"
@@ -51,7 +49,7 @@ tigris login dev
 		}
 
 		if err := login.CmdLow(cmd.Context(), host); err != nil {
-			os.Exit(1) //nolint:revive
+			util.Exit(1)
 		}
 	},
 }
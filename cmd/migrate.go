@@ -0,0 +1,278 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/migrate"
+	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var (
+	migrationsDir    string
+	migrateTo        int64
+	migrateUpSteps   int
+	migrateDownSteps int
+)
+
+func isNotFound(err error) bool {
+	var ep *driver.Error
+
+	return errors.As(err, &ep) && ep.Code == api.Code_NOT_FOUND
+}
+
+// readAppliedMigrations returns applied migrations records.
+// The migrations collection is created, if it doesn't exist, when create is set,
+// otherwise no migrations are applied if the collection doesn't exist.
+func readAppliedMigrations(ctx context.Context, create bool) ([]*migrate.Record, error) {
+	if create {
+		err := client.GetDB(ctx).CreateOrUpdateCollection(ctx, migrate.Collection, migrate.CollectionSchema)
+		if err != nil {
+			return nil, util.Error(err, "create migrations collection")
+		}
+	}
+
+	it, err := client.GetDB(ctx).Read(ctx, migrate.Collection, driver.Filter(`{}`), driver.Projection(`{}`))
+	if err != nil {
+		if !create && isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, util.Error(err, "read applied migrations")
+	}
+	defer it.Close()
+
+	var (
+		res []*migrate.Record
+		doc driver.Document
+	)
+
+	for it.Next(&doc) {
+		var r migrate.Record
+		if err = json.Unmarshal(doc, &r); err != nil {
+			return nil, err
+		}
+
+		res = append(res, &r)
+	}
+
+	if err = it.Err(); err != nil && !create && isNotFound(err) {
+		return nil, nil
+	}
+
+	return res, util.Error(err, "read applied migrations")
+}
+
+func loadMigrations(ctx context.Context, create bool) ([]*migrate.Migration, []*migrate.Record, error) {
+	migrations, err := migrate.Load(migrationsDir)
+	if err != nil {
+		return nil, nil, util.Error(err, "load migrations")
+	}
+
+	applied, err := readAppliedMigrations(ctx, create)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return migrations, applied, nil
+}
+
+// applyMigration executes migration operations and records the result in the same transaction.
+func applyMigration(ctx context.Context, m *migrate.Migration, up bool) error {
+	return client.Transact(ctx, config.GetProjectName(), func(ctx context.Context, tx driver.Tx) error {
+		ops := m.Up
+		if !up {
+			ops = m.Down
+		}
+
+		for _, op := range ops {
+			if err := execTxOps(ctx, tx, op); err != nil {
+				return err
+			}
+		}
+
+		if !up {
+			_, err := tx.Delete(ctx, migrate.Collection, driver.Filter(fmt.Sprintf(`{"version":%d}`, m.Version)))
+
+			return util.Error(err, "delete migration record")
+		}
+
+		rec, err := json.Marshal(&migrate.Record{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Insert(ctx, migrate.Collection, []driver.Document{rec})
+
+		return util.Error(err, "insert migration record")
+	})
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Applies pending migrations",
+	Long: `Applies pending migrations in the order of versions.
+Every migration is applied in a separate transaction.`,
+	Example: fmt.Sprintf(`
+  # Apply all pending migrations
+  %[1]s migrate up --project=myproj
+
+  # Apply pending migrations up to and including version 3
+  %[1]s migrate up --project=myproj --to 3
+`, rootCmd.Root().Name()),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			migrations, applied, err := loadMigrations(ctx, true)
+			if err != nil {
+				return err
+			}
+
+			plan := migrate.PlanUp(migrations, applied, migrateTo, migrateUpSteps)
+			if len(plan) == 0 {
+				util.Infof("No pending migrations")
+				return nil
+			}
+
+			for _, m := range plan {
+				if err = applyMigration(ctx, m, true); err != nil {
+					return util.Error(err, "apply migration %d_%s", m.Version, m.Name)
+				}
+
+				util.Infof("Applied migration %d_%s", m.Version, m.Name)
+			}
+
+			return nil
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Reverts applied migrations",
+	Long: `Reverts applied migrations in the reverse order of versions.
+Reverts the last applied migration by default.`,
+	Example: fmt.Sprintf(`
+  # Revert the last applied migration
+  %[1]s migrate down --project=myproj
+
+  # Revert all migrations with versions greater than 1
+  %[1]s migrate down --project=myproj --to 1 --steps 0
+`, rootCmd.Root().Name()),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			// nothing was applied on the branches without migrations collection, so it's not created
+			migrations, applied, err := loadMigrations(ctx, false)
+			if err != nil {
+				return err
+			}
+
+			plan, err := migrate.PlanDown(migrations, applied, migrateTo, migrateDownSteps)
+			if err != nil {
+				return util.Error(err, "plan migrations")
+			}
+
+			if len(plan) == 0 {
+				util.Infof("No applied migrations, nothing to roll back")
+				return nil
+			}
+
+			for _, m := range plan {
+				if err = applyMigration(ctx, m, false); err != nil {
+					return util.Error(err, "revert migration %d_%s", m.Version, m.Name)
+				}
+
+				util.Infof("Reverted migration %d_%s", m.Version, m.Name)
+			}
+
+			return nil
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows applied and pending migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			// status is read-only and doesn't create migrations collection
+			migrations, applied, err := loadMigrations(ctx, false)
+			if err != nil {
+				return err
+			}
+
+			for _, s := range migrate.Statuses(migrations, applied) {
+				switch {
+				case s.Missing:
+					util.Stdoutf("%d_%s\tmissing\t%s\n", s.Version, s.Name, s.AppliedAt.Format(time.RFC3339))
+				case s.Applied:
+					util.Stdoutf("%d_%s\tapplied\t%s\n", s.Version, s.Name, s.AppliedAt.Format(time.RFC3339))
+				default:
+					util.Stdoutf("%d_%s\tpending\n", s.Version, s.Name)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Versioned schema migrations",
+	Long: `Applies and reverts versioned migrations from the local directory.
+Migration files are named {version}_{name}.json and contain "up" and "down"
+arrays of operations in the format of the "transact" command.
+Applied migrations are recorded in the "` + migrate.Collection + `" collection
+of the project branch.`,
+	Example: fmt.Sprintf(`
+  # ./migrations/1_create_users.json
+  {
+    "up": [ { "create_or_update_collection": { "collection": "users", "schema": {
+      "title": "users", "properties": { "id": { "type": "integer" } }, "primary_key": ["id"] } } } ],
+    "down": [ { "drop_collection": { "collection": "users" } } ]
+  }
+
+  %[1]s migrate up --project=myproj --branch=staging
+`, rootCmd.Root().Name()),
+}
+
+func init() {
+	for _, c := range []*cobra.Command{migrateUpCmd, migrateDownCmd, migrateStatusCmd} {
+		c.Flags().StringVarP(&migrationsDir, "directory", "d", "./migrations", "directory with migration files")
+		addProjectFlag(c)
+		migrateCmd.AddCommand(c)
+	}
+
+	migrateUpCmd.Flags().Int64Var(&migrateTo, "to", 0, "apply migrations up to and including this version")
+	migrateUpCmd.Flags().IntVar(&migrateUpSteps, "steps", 0, "maximum number of migrations to apply")
+	migrateDownCmd.Flags().Int64Var(&migrateTo, "to", 0, "revert migrations with versions greater than this version")
+	migrateDownCmd.Flags().IntVar(&migrateDownSteps, "steps", 1, "maximum number of migrations to revert, 0 - no limit")
+
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -145,7 +145,7 @@ var pingCmd = &cobra.Command{
 		}
 
 		_, _ = fmt.Fprintf(os.Stderr, "FAILED\n")
-		os.Exit(1) //nolint:revive
+		util.Exit(1)
 	},
 }
 
@@ -50,11 +50,7 @@ var listProjectsCmd = &cobra.Command{
 				return util.Error(err, "list projects")
 			}
 
-			for _, v := range resp {
-				util.Stdoutf("%s\n", v)
-			}
-
-			return nil
+			return util.Error(util.PrintList("project", resp), "list projects")
 		})
 	},
 }
@@ -78,11 +74,22 @@ var describeDatabaseCmd = &cobra.Command{
 				return util.Error(err, "describe collection failed")
 			}
 
-			if schemaOnly {
+			switch {
+			case schemaOnly && (util.OutputFormat == "" || format != ""):
 				for _, v := range resp.Collections {
 					util.Stdoutf("%s\n", string(v.Schema))
 				}
-			} else {
+			case schemaOnly:
+				out, err := util.NewOutput(os.Stdout, util.OutputNDJSON)
+				util.Fatal(err, "output format")
+
+				for _, v := range resp.Collections {
+					err = out.Write(json.RawMessage(v.Schema))
+					util.Fatal(err, "write schema")
+				}
+
+				return util.Error(out.Flush(), "flush output")
+			default:
 				tr := DescribeDatabaseResponse{
 					Metadata: resp.Metadata,
 				}
@@ -95,10 +102,8 @@ var describeDatabaseCmd = &cobra.Command{
 					})
 				}
 
-				b, err := json.Marshal(tr)
+				err = util.PrintOutput(util.OutputNDJSON, tr)
 				util.Fatal(err, "describe database")
-
-				util.Stdoutf("%s\n", string(b))
 			}
 
 			return nil
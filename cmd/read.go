@@ -16,20 +16,94 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
 	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/paginate"
 	"github.com/tigrisdata/tigris-cli/util"
 	"github.com/tigrisdata/tigris-client-go/driver"
 )
 
 var (
-	limit int64
-	skip  int64
+	limit      int64
+	skip       int64
+	readSort   []string
+	readAfter  string
+	pageToken  string
+	readFollow bool
+
+	ErrFollowFlags = fmt.Errorf("--follow cannot be used with fields, --limit, --skip, --sort, --after or --page-token")
 )
 
+// collectionKey returns the primary key of the collection.
+func collectionKey(ctx context.Context, coll string) ([]string, error) {
+	resp, err := client.GetDB(ctx).DescribeCollection(ctx, coll)
+	if err != nil {
+		return nil, err
+	}
+
+	var sch struct {
+		PrimaryKey []string `json:"primary_key"`
+	}
+
+	if err = json.Unmarshal(resp.Schema, &sch); err != nil {
+		return nil, err
+	}
+
+	return sch.PrimaryKey, nil
+}
+
+// readPage returns the sort order of the read request and the filter
+// amended to continue after --after key or --page-token.
+// The primary key is appended to the sort order when reading by pages to make the order stable.
+// Plain reads, without --sort, --after or --page-token, don't need the primary key.
+func readPage(ctx context.Context, coll string, filter string) (paginate.Order, string, error) {
+	order, err := paginate.ParseSort(readSort)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(readSort) == 0 && readAfter == "" && pageToken == "" {
+		return order, filter, nil
+	}
+
+	pk, err := collectionKey(ctx, coll)
+	if err != nil {
+		return nil, "", util.Error(err, "describe collection")
+	}
+
+	order = order.WithKey(pk)
+
+	var tok *paginate.Token
+
+	switch {
+	case pageToken != "" && len(readSort) == 0:
+		// continue in the order of the token
+		tok, err = paginate.DecodeToken(pageToken, nil)
+	case pageToken != "":
+		tok, err = paginate.DecodeToken(pageToken, order)
+	case readAfter != "":
+		tok, err = paginate.ParseAfter(order, readAfter)
+	default:
+		return order, filter, nil
+	}
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := tok.Filter([]byte(filter))
+	if err != nil {
+		return nil, "", util.Error(err, "page filter")
+	}
+
+	return tok.Sort, string(f), nil
+}
+
 var readCmd = &cobra.Command{
 	Use:   "read {collection} {filter} {fields}",
 	Short: "Reads and outputs documents",
@@ -58,9 +132,53 @@ all the fields of the documents are selected.`,
   #  {"id": 20, "name": "Jania McGrory"}
   #  {"id": 21, "name": "Bunny Instone"}
   %[1]s read --project=myproj users
+
+  # Read users older than 23 living in SF, using filter expression
+  %[1]s read --project=myproj users --where "age > 23 and city = 'SF'"
+
+  # Read all documents in the user collection as a table
+  # The output would be
+  #  id  name
+  #  2   Alice Wong
+  #  4   Jigar Joshi
+  %[1]s read --project=myproj --output=table users
+
+  # Read the newest users by pages of 100 documents
+  # Token of the next page is printed to stderr after the documents
+  #  next page token: eyJzb3J0Ijpb...
+  %[1]s read --project=myproj users --sort '{"created_at": "$desc"}' --limit 100
+  %[1]s read --project=myproj users --limit 100 --page-token eyJzb3J0Ijpb...
+
+  # Read users with id greater than 20 in the primary key order
+  %[1]s read --project=myproj users --limit 100 --after 20
+
+  # Read all orders and keep printing new and changed ones every 5 seconds
+  %[1]s read --project=myproj orders --follow --interval 5s
 `, rootCmd.Root().Name()),
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		args, explain := whereFilter(args, 1)
+		if explain {
+			return
+		}
+
+		if readFollow {
+			if len(args) > 2 || limit != 0 || skip != 0 || len(readSort) > 0 || readAfter != "" || pageToken != "" {
+				util.Fatal(ErrFollowFlags, "read follow")
+			}
+
+			login.Ensure(cmd.Context(), func(_ context.Context) error {
+				filter := `{}`
+				if len(args) > 1 {
+					filter = args[1]
+				}
+
+				return followCollection(cmd.Context(), args[0], filter, -1)
+			})
+
+			return
+		}
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			filter, fields := `{}`, `{}`
 
@@ -72,34 +190,79 @@ all the fields of the documents are selected.`,
 				fields = args[2]
 			}
 
-			it, err := client.GetDB().Read(ctx, args[0],
+			order, filter, err := readPage(ctx, args[0], filter)
+			if err != nil {
+				return err
+			}
+
+			proj, err := order.Projection([]byte(fields))
+			if err != nil {
+				return util.Error(err, "fields")
+			}
+
+			it, err := client.GetDB(ctx).Read(ctx, args[0],
 				driver.Filter(filter),
-				driver.Projection(fields),
-				&driver.ReadOptions{Limit: limit, Skip: skip},
+				driver.Projection(proj),
+				&driver.ReadOptions{Limit: limit, Skip: skip, Sort: order.JSON()},
 			)
 			if err != nil {
 				return util.Error(err, "read documents failed")
 			}
 			defer it.Close()
 
-			var doc driver.Document
+			out, err := util.NewOutput(os.Stdout, util.OutputNDJSON)
+			util.Fatal(err, "output format")
+
+			var (
+				doc  driver.Document
+				last json.RawMessage
+				cnt  int64
+			)
+
 			for it.Next(&doc) {
-				// Document came through GRPC may have \n at the end already
-				if doc[len(doc)-1] == 0x0A {
-					util.Stdoutf("%s", string(doc))
-				} else {
-					util.Stdoutf("%s\n", string(doc))
+				err = out.Write(json.RawMessage(doc))
+				util.Fatal(err, "write document")
+
+				last = json.RawMessage(doc)
+				cnt++
+			}
+
+			if err = it.Err(); err != nil {
+				return err
+			}
+
+			if err = out.Flush(); err != nil {
+				return util.Error(err, "flush output")
+			}
+
+			// full page of the ordered read means there can be more documents
+			if limit > 0 && cnt == limit && len(order) > 0 {
+				next, err := paginate.Next(order, last)
+				if err != nil {
+					return util.Error(err, "page token")
 				}
+
+				util.Stderrf("next page token: %s\n", next.Encode())
 			}
 
-			return it.Err()
+			return nil
 		})
 	},
 }
 
 func init() {
+	addWhereFlags(readCmd)
 	addProjectFlag(readCmd)
 	readCmd.Flags().Int64VarP(&limit, "limit", "l", 0, "limit number of returned results")
 	readCmd.Flags().Int64VarP(&skip, "skip", "s", 0, "skip this many results in the beginning of the result set")
+	readCmd.Flags().StringArrayVar(&readSort, "sort", nil,
+		`sort order of the documents, can be repeated: --sort '{"created_at": "$desc"}'`)
+	readCmd.Flags().StringVar(&readAfter, "after", "",
+		"read documents after this sort key. JSON array of values if sorted by multiple fields")
+	readCmd.Flags().StringVar(&pageToken, "page-token", "",
+		"continue reading from the page token printed by the previous read with --limit and --sort or --after")
+	readCmd.Flags().BoolVarP(&readFollow, "follow", "f", false,
+		"after reading the documents, keep polling for new and changed documents")
+	addFollowFlags(readCmd)
 	rootCmd.AddCommand(readCmd)
 }
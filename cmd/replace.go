@@ -59,7 +59,7 @@ var replaceCmd = &cobra.Command{
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			return iterate.Input(ctx, cmd, 1, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
 				ptr := unsafe.Pointer(&docs)
-				_, err := client.GetDB().Replace(ctx, args[0], *(*[]driver.Document)(ptr))
+				_, err := client.GetDB(ctx).Replace(ctx, args[0], *(*[]driver.Document)(ptr))
 
 				return util.Error(err, "replace documents failed")
 			})
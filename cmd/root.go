@@ -43,6 +43,8 @@ func Execute() {
 func init() {
 	rootCmd.Flags().BoolVarP(&util.Quiet, "quiet", "q", false,
 		"Suppress informational messages")
+	rootCmd.PersistentFlags().StringVar(&util.OutputFormat, "output", "",
+		"Output format: json, ndjson, yaml, table, csv or go-template={template}")
 
 	rootCmd.AddCommand(search.RootCmd)
 	rootCmd.AddCommand(dbCmd)
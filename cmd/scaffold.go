@@ -49,6 +49,10 @@ var (
 		"typescript": "ts",
 		"go":         "go",
 		"java":       "java",
+		"py":         "python",
+		"python":     "python",
+		"rs":         "rust",
+		"rust":       "rust",
 	}
 
 	ErrUnknownExample = fmt.Errorf("unknown example name")
@@ -126,7 +130,7 @@ func scaffoldFramework(ctx context.Context, templatesPath string, examplesPath s
 	util.Infof("Language '%s'", language)
 	util.Infof("Output directory '%s'", filepath.Join(outDir, pName))
 
-	colls, err := getCollections(ctx, pName, language+",json")
+	colls, err := getCollections(ctx, pName, scaffold.SchemaFormat(language))
 	if err != nil {
 		return err
 	}
@@ -232,7 +236,7 @@ func addScaffoldProjectFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&schemaTemplate, "schema-template", "s", "",
 		"Database schema template to use")
 	cmd.Flags().StringVarP(&language, "language", "l", "typescript",
-		"Language to Scaffold the project in. Possible values are: TypeScript, Golang, Java")
+		"Language to Scaffold the project in. Possible values are: TypeScript, Golang, Java, Python, Rust")
 	cmd.Flags().StringVarP(&framework, "framework", "f", "",
 		"Framework used for scaffolding")
 
@@ -0,0 +1,593 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/convert"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/migrate"
+	"github.com/tigrisdata/tigris-cli/scaffold"
+	"github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	cschema "github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const (
+	collectionSourcePrefix = "collection:"
+	branchSourcePrefix     = "branch:"
+
+	branchCollectionSeparator = ":"
+)
+
+var (
+	convertFrom   string
+	convertTo     string
+	convertStrict bool
+
+	ErrBreakingChanges = fmt.Errorf("breaking schema changes detected")
+	ErrLintIssues      = fmt.Errorf("schema lint issues found")
+	ErrLossyConversion = fmt.Errorf("some schema constructs cannot be represented in the target format")
+)
+
+func unmarshalSchemas(res map[string]*cschema.Schema, raw []byte) error {
+	var list []json.RawMessage
+
+	if len(raw) > 0 && raw[0] == '[' {
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return err
+		}
+	} else {
+		list = append(list, raw)
+	}
+
+	for _, v := range list {
+		var sch cschema.Schema
+
+		if err := json.Unmarshal(v, &sch); err != nil {
+			return err
+		}
+
+		if sch.Name == "" {
+			return ErrSchemaNameMissing
+		}
+
+		res[sch.Name] = &sch
+	}
+
+	return nil
+}
+
+// readSchemaFile reads collection schemas from the file.
+// The file can contain a single schema, stream or array of schemas.
+// Standard input is read if the file name is "-".
+func readSchemaFile(name string) (map[string]*cschema.Schema, error) {
+	var r io.Reader = os.Stdin
+
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		r = f
+	}
+
+	list, err := schema.ReadSchemas(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*cschema.Schema, len(list))
+
+	for _, sch := range list {
+		if sch.Name == "" {
+			return nil, ErrSchemaNameMissing
+		}
+
+		res[sch.Name] = sch
+	}
+
+	return res, nil
+}
+
+// describeSchemas returns schema of the collection or schemas of all the collections if coll is empty.
+// Collections managed by the CLI are not included in all the collections.
+func describeSchemas(ctx context.Context, coll string) (map[string]*cschema.Schema, error) {
+	res := make(map[string]*cschema.Schema)
+
+	if coll != "" {
+		resp, err := client.GetDB(ctx).DescribeCollection(ctx, coll)
+		if err != nil {
+			return nil, util.Error(err, "describe collection")
+		}
+
+		return res, unmarshalSchemas(res, resp.Schema)
+	}
+
+	resp, err := client.Get().DescribeDatabase(ctx, config.GetProjectName())
+	if err != nil {
+		return nil, util.Error(err, "describe database")
+	}
+
+	for _, v := range resp.Collections {
+		if migrate.IsInternal(v.Collection) {
+			continue
+		}
+
+		if err = unmarshalSchemas(res, v.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// loadSchemas loads collection schemas from the source, which is one of:
+//   - local file name
+//   - collection:{collection}
+//   - branch:{branch}, which loads all the collections of the branch
+//   - branch:{branch}:{collection}
+//
+// Branch names can contain slashes, so the collection is separated by the colon.
+func loadSchemas(ctx context.Context, src string) (_ map[string]*cschema.Schema, err error) {
+	log.Debug().Str("source", src).Msg("loading schemas")
+
+	switch {
+	case strings.HasPrefix(src, collectionSourcePrefix):
+		return describeSchemas(ctx, strings.TrimPrefix(src, collectionSourcePrefix))
+	case strings.HasPrefix(src, branchSourcePrefix):
+		branch := strings.TrimPrefix(src, branchSourcePrefix)
+
+		branch, coll, _ := strings.Cut(branch, branchCollectionSeparator)
+
+		restore, err := client.UseBranch(branch)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			if rerr := restore(); err == nil {
+				err = rerr
+			}
+		}()
+
+		return describeSchemas(ctx, coll)
+	}
+
+	return readSchemaFile(src)
+}
+
+func isServerSource(src string) bool {
+	return strings.HasPrefix(src, collectionSourcePrefix) || strings.HasPrefix(src, branchSourcePrefix)
+}
+
+// withSchemaSources runs fn with the server connection only if some of the sources are read from the server.
+// Local files and standard input are read without logging in and without the request timeout.
+func withSchemaSources(cctx context.Context, srcs []string, msg string, fn func(ctx context.Context) error) {
+	for _, src := range srcs {
+		if isServerSource(src) {
+			login.Ensure(cctx, fn)
+
+			return
+		}
+	}
+
+	util.Fatal(fn(cctx), msg)
+}
+
+// diffSchemas compares single schemas directly, even if collection names are different.
+func diffSchemas(oldColls map[string]*cschema.Schema, newColls map[string]*cschema.Schema) []*schema.Change {
+	if len(oldColls) == 1 && len(newColls) == 1 {
+		for _, o := range oldColls {
+			for _, n := range newColls {
+				return schema.Diff(o, n)
+			}
+		}
+	}
+
+	return schema.DiffCollections(oldColls, newColls)
+}
+
+func printChanges(changes []*schema.Change) {
+	if util.OutputFormat != "" {
+		err := writeOutputList(changes)
+		util.Fatal(err, "write changes")
+
+		return
+	}
+
+	if len(changes) == 0 {
+		util.Infof("No schema changes")
+		return
+	}
+
+	var coll string
+
+	for _, c := range changes {
+		if c.Collection != coll {
+			coll = c.Collection
+			util.Stdoutf("%s:\n", coll)
+		}
+
+		util.Stdoutf("  [%s] %s\n", c.Severity, c)
+	}
+}
+
+var schemaDiffCmd = &cobra.Command{
+	Use:   "diff {old} {new}",
+	Short: "Shows difference between schemas",
+	Long: `Shows field level difference between collection schemas.
+Each side of the comparison can be:
+  * local file with schema, stream or array of schemas, or "-" for standard input
+  * collection:{collection} - collection schema in the current branch
+  * branch:{branch} - schemas of all collections in the branch
+  * branch:{branch}:{collection} - collection schema in the branch
+
+Exits with non-zero code if there are breaking changes.`,
+	Example: fmt.Sprintf(`
+  # Compare local schema file with the collection schema
+  %[1]s schema diff --project=myproj collection:users ./schemas/users.json
+
+  # Compare collection in the feature branch with the main branch
+  %[1]s schema diff --project=myproj branch:main:users branch:feature/new-users:users
+
+  # Compare all collections of two branches
+  %[1]s schema diff --project=myproj branch:main branch:feature
+`, rootCmd.Root().Name()),
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		withSchemaSources(cmd.Context(), args, "schema diff", func(ctx context.Context) error {
+			oldColls, err := loadSchemas(ctx, args[0])
+			if err != nil {
+				return util.Error(err, "load schemas: %s", args[0])
+			}
+
+			newColls, err := loadSchemas(ctx, args[1])
+			if err != nil {
+				return util.Error(err, "load schemas: %s", args[1])
+			}
+
+			changes := diffSchemas(oldColls, newColls)
+
+			printChanges(changes)
+
+			if schema.HasBreaking(changes) {
+				return ErrBreakingChanges
+			}
+
+			return nil
+		})
+	},
+}
+
+// checkSchemas compares schemas from the input with the live schemas of the collections.
+func checkSchemas(ctx context.Context, docs []json.RawMessage) ([]*schema.Change, error) {
+	var changes []*schema.Change
+
+	for _, v := range docs {
+		newColls := make(map[string]*cschema.Schema)
+
+		if err := unmarshalSchemas(newColls, v); err != nil {
+			return nil, util.Error(err, "unmarshal schema")
+		}
+
+		for name, n := range newColls {
+			liveColls, err := describeSchemas(ctx, name)
+			if err != nil {
+				var ep *driver.Error
+				if !errors.As(err, &ep) || ep.Code != api.Code_NOT_FOUND {
+					return nil, err
+				}
+
+				liveColls = map[string]*cschema.Schema{}
+			}
+
+			changes = append(changes, schema.DiffCollections(liveColls, map[string]*cschema.Schema{name: n})...)
+		}
+	}
+
+	return changes, nil
+}
+
+// checkSchemasInput checks schemas from the input and fails on breaking changes.
+func checkSchemasInput(ctx context.Context, cmd *cobra.Command, docsPosition int, args []string) error {
+	return iterate.Input(ctx, cmd, docsPosition, args,
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			changes, err := checkSchemas(ctx, docs)
+			if err != nil {
+				return err
+			}
+
+			printChanges(changes)
+
+			if schema.HasBreaking(changes) {
+				return ErrBreakingChanges
+			}
+
+			return nil
+		})
+}
+
+var schemaCheckCmd = &cobra.Command{
+	Use:   "check {schema}...|-",
+	Short: "Checks compatibility of the schema with the existing collection",
+	Long: `Compares provided schemas with the schemas of the existing collections.
+Every change is classified as:
+  * safe - can be applied without affecting existing documents
+  * requires-backfill - existing documents need to be updated or reindexed
+  * breaking - incompatible with existing documents
+
+Exits with non-zero code if there are breaking changes.`,
+	Example: fmt.Sprintf(`
+  # Check schema from the file
+  %[1]s schema check --project=myproj </home/alice/users.json
+
+  # Check schema before applying it
+  %[1]s alter collection --project=myproj --check </home/alice/users.json
+`, rootCmd.Root().Name()),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			return checkSchemasInput(ctx, cmd, 0, args)
+		})
+	},
+}
+
+// writeOutputList writes the list in the format of the --output flag.
+func writeOutputList[T any](list []T) error {
+	o, err := util.NewOutput(os.Stdout, util.OutputJSON)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range list {
+		if err = o.Write(v); err != nil {
+			return err
+		}
+	}
+
+	return o.Flush()
+}
+
+func printLintIssues(issues []*schema.Issue) {
+	if util.OutputFormat != "" {
+		err := writeOutputList(issues)
+		util.Fatal(err, "write lint issues")
+
+		return
+	}
+
+	if len(issues) == 0 {
+		util.Infof("No lint issues")
+		return
+	}
+
+	var coll string
+
+	for _, i := range issues {
+		if i.Collection != coll {
+			coll = i.Collection
+			util.Stdoutf("%s:\n", coll)
+		}
+
+		util.Stdoutf("  [%s] %s\n", i.Rule, i)
+	}
+}
+
+// lintSchemas loads schemas from all the sources, or all the collections of the project
+// if no sources provided, and checks them against the rules from the config.
+func lintSchemas(ctx context.Context, srcs []string) ([]*schema.Issue, error) {
+	if len(srcs) == 0 {
+		colls, err := describeSchemas(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return schema.Lint(colls, &config.DefaultConfig.Lint)
+	}
+
+	colls := make(map[string]*cschema.Schema)
+
+	for _, src := range srcs {
+		res, err := loadSchemas(ctx, src)
+		if err != nil {
+			return nil, util.Error(err, "load schemas: %s", src)
+		}
+
+		for k, v := range res {
+			colls[k] = v
+		}
+	}
+
+	return schema.Lint(colls, &config.DefaultConfig.Lint)
+}
+
+var schemaLintCmd = &cobra.Command{
+	Use:   "lint [{schema}...]",
+	Short: "Checks schemas against the configured rules",
+	Long: `Checks collection schemas against the lint rules.
+Schemas can be provided in the same formats as in the "schema diff" command.
+All the collections of the project are checked if no schemas provided.
+
+Rules are configured in the "lint" section of the config file:
+  lint:
+    naming: snake_case          # or camelCase
+    descriptions: true          # require descriptions of collections and fields
+    max_depth: 3                # maximum nesting depth of the fields
+    filter_fields: [user.email] # fields used in filters, should be indexed
+    sort_fields: [created_at]   # fields used for sorting, should be sortable
+    disabled: [no-nested-arrays]
+
+The "bounded-primary-key" and "no-nested-arrays" rules are enabled by default.
+
+Exits with non-zero code if there are issues.`,
+	Example: fmt.Sprintf(`
+  # Lint all the collections of the project
+  %[1]s schema lint --project=myproj
+
+  # Lint local schema files in CI
+  %[1]s schema lint --output json ./schemas/users.json ./schemas/orders.json
+`, rootCmd.Root().Name()),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			issues, err := lintSchemas(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			printLintIssues(issues)
+
+			if len(issues) > 0 {
+				return ErrLintIssues
+			}
+
+			return nil
+		})
+	},
+}
+
+// readConvertInput reads schemas from the source in the format of the --from flag.
+// Native schemas can also be read from the collections, the same way as in the schema diff command.
+func readConvertInput(ctx context.Context, src string) ([]*cschema.Schema, []*convert.Note, error) {
+	if strings.EqualFold(convertFrom, convert.FormatTigris) {
+		colls, err := loadSchemas(ctx, src)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return scaffold.SortedSchemas(colls), nil, nil
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if src == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(src)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return convert.Import(convertFrom, data)
+}
+
+var schemaConvertCmd = &cobra.Command{
+	Use:   "convert {schema}|-",
+	Short: "Converts schemas to and from other schema languages",
+	Long: `Converts collection schemas between Tigris JSON schema and
+Avro, Protobuf, OpenAPI components and SQL DDL (PostgreSQL dialect).
+
+Types, formats (uuid, date-time, byte, int32 and int64) and the primary key are mapped
+between the formats. Constructs which cannot be represented in the target format
+are reported as warnings. Use --strict to exit with non-zero code in this case.
+
+Tigris schemas can be read from the collections, using collection:{collection}
+and branch:{branch} sources, as in the "schema diff" command.`,
+	Example: fmt.Sprintf(`
+  # Convert Avro schema to Tigris schema
+  %[1]s schema convert --from avro --to tigris ./users.avsc
+
+  # Convert collection schemas of the project to OpenAPI components
+  %[1]s schema convert --project=myproj --to openapi branch:main
+
+  # Convert Tigris schema to SQL DDL, failing on lossy conversion
+  %[1]s schema convert --to sql --strict </home/alice/users.json
+`, rootCmd.Root().Name()),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		src := "-"
+		if len(args) > 0 {
+			src = args[0]
+		}
+
+		// only Tigris schemas can be read from the server
+		var srcs []string
+		if strings.EqualFold(convertFrom, convert.FormatTigris) {
+			srcs = args
+		}
+
+		withSchemaSources(cmd.Context(), srcs, "schema convert", func(ctx context.Context) error {
+			schemas, notes, err := readConvertInput(ctx, src)
+			if err != nil {
+				return util.Error(err, "read schemas: %s", src)
+			}
+
+			out, exportNotes, err := convert.Export(convertTo, schemas)
+			if err != nil {
+				return util.Error(err, "convert schemas")
+			}
+
+			util.Stdoutf("%s", out)
+
+			notes = append(notes, exportNotes...)
+			for _, n := range notes {
+				util.Stderrf("warning: %s\n", n)
+			}
+
+			if convertStrict && len(notes) > 0 {
+				return ErrLossyConversion
+			}
+
+			return nil
+		})
+	},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Collection schema related commands",
+}
+
+func init() {
+
+	addProjectFlag(schemaDiffCmd)
+	addProjectFlag(schemaCheckCmd)
+	addProjectFlag(schemaLintCmd)
+
+	schemaConvertCmd.Flags().StringVarP(&convertFrom, "from", "f", convert.FormatTigris,
+		"input schema format: tigris, avro, protobuf, openapi, sql")
+	schemaConvertCmd.Flags().StringVarP(&convertTo, "to", "t", convert.FormatTigris,
+		"output schema format: tigris, avro, protobuf, openapi, sql")
+	schemaConvertCmd.Flags().BoolVar(&convertStrict, "strict", false,
+		"exit with non-zero code if some constructs cannot be represented")
+	addProjectFlag(schemaConvertCmd)
+
+	schemaCmd.AddCommand(schemaDiffCmd)
+	schemaCmd.AddCommand(schemaCheckCmd)
+	schemaCmd.AddCommand(schemaLintCmd)
+	schemaCmd.AddCommand(schemaConvertCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
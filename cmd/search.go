@@ -18,10 +18,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
 	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/render"
+	"github.com/tigrisdata/tigris-cli/searchutil"
 	"github.com/tigrisdata/tigris-cli/util"
 	"github.com/tigrisdata/tigris-client-go/driver"
 	"github.com/tigrisdata/tigris-client-go/search"
@@ -37,12 +40,26 @@ var (
 	excludeFields []string
 	page          int32
 	pageSize      int32
+
+	searchAllPages   bool
+	searchMaxResults int64
+	searchHitsOnly   bool
+	searchMetaOut    string
+
+	searchVectors    []string
+	searchVectorFile string
+	searchNeighbors  int32
 )
 
 var dbSearchCmd = &cobra.Command{
 	Use:   "search {collection}",
 	Short: "Searches a collection for documents matching the query",
-	Long:  "Executes a search query against collection and returns the search results.",
+	Long: `Executes a search query against collection and returns the search results.
+
+When the output is a terminal, facets are shown as histograms and hits as a table
+of the score and the search and included fields, with the query terms highlighted.
+Set NO_COLOR environment variable to disable the highlighting.
+Output to a pipe or a file stays JSON.`,
 	//nolint:golint,lll
 	Example: fmt.Sprintf(`
 # Default search without any parameters will return all documents
@@ -69,50 +86,182 @@ var dbSearchCmd = &cobra.Command{
 # Paginate the results, with 15 per page
 %[1]s %[2]s -q "Alice" -f "firstName,lastName" --filter '{"age": {"$gt": 23}}' --facet '{"currentCity": {"size": 10}}' --sort '{"age": "$asc"}' -x "phoneNumber,address" -p 1 -c 15
 
+# Retrieve up to 1000 hits from all the pages as NDJSON, facets and metadata to meta.json
+%[1]s %[2]s -q "Alice" --all-pages --max-results 1000 --hits-only --meta-out meta.json
+
+# Find 10 nearest neighbors of the vector in "embedding" field among the users older than 23
+%[1]s %[2]s --vector 'embedding=[0.12, -0.3, 0.8]' --neighbors 10 --where "age > 23"
+
+# Same with the vector read from the file, retrieving the second page of 10 neighbors
+%[1]s %[2]s --vector-file embedding=vector.json --neighbors 10 --page 2
+
 # Find users with last name exactly matching "Wong"
 %[1]s %[2]s --filter '{"lastName": "Wong"}'
+
+# Same filter using filter expression
+%[1]s %[2]s --where "lastName = 'Wong'"
 `, rootCmd.Root().Name(), "search --project=myproj users"),
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			var sortArr driver.SortOrder
-			if len(sort) > 0 {
-				for _, v := range sort {
-					sortArr = append(sortArr, json.RawMessage(v))
-				}
-			}
-			request := &driver.SearchRequest{
-				Q:             query,
+		if whereExpr != "" {
+			filter = parseWhere()
+		}
+
+		if explainWhere(filter) {
+			return
+		}
+
+		login.Ensure(cmd.Context(), func(_ context.Context) error {
+			opts := &searchutil.Options{
+				Query:         query,
 				SearchFields:  searchFields,
-				Filter:        driver.Filter(filter),
-				Facet:         driver.Facet(facet),
-				Sort:          sortArr,
+				Filter:        filter,
+				Facet:         facet,
+				Sort:          sort,
 				IncludeFields: includeFields,
 				ExcludeFields: excludeFields,
 				Page:          page,
 				PageSize:      pageSize,
+				Vectors:       searchVectors,
+				VectorFile:    searchVectorFile,
+				Neighbors:     searchNeighbors,
 			}
 
-			it, err := client.GetDB().Search(ctx, args[0], request)
+			request, err := opts.Request()
 			if err != nil {
-				return util.Error(err, "search failed")
+				return util.Error(err, "search request")
 			}
 
-			var resp driver.SearchResponse
-			for it.Next(&resp) {
-				r := &search.Result[any]{}
-				err := r.From(resp)
-				util.Fatal(err, "search result conversion")
+			// pages use own timeouts
+			return searchCollection(cmd.Context(), args[0], request)
+		})
+	},
+}
+
+// searchPages runs the search request and, when --all-pages is set, the requests of the following pages,
+// until the hits are exhausted or fn returns false.
+func searchPages(ctx context.Context, coll string, req *driver.SearchRequest,
+	fn func(resp driver.SearchResponse, r *search.Result[any]) bool,
+) error {
+	return searchutil.Pages(req, searchAllPages, func(req *driver.SearchRequest) (bool, error) {
+		return searchPage(ctx, coll, req, fn)
+	})
+}
+
+// searchPage returns true if there are more pages after the requested one.
+func searchPage(ctx context.Context, coll string, req *driver.SearchRequest,
+	fn func(resp driver.SearchResponse, r *search.Result[any]) bool,
+) (bool, error) {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	it, err := client.GetDB(ctx).Search(ctx, coll, req)
+	if err != nil {
+		return false, util.Error(err, "search failed")
+	}
+	defer it.Close()
+
+	var (
+		resp driver.SearchResponse
+		more bool
+	)
+
+	for it.Next(&resp) {
+		r := &search.Result[any]{}
+		err := r.From(resp)
+		util.Fatal(err, "search result conversion")
+
+		if !fn(resp, r) {
+			return false, nil
+		}
+
+		more = searchutil.More(req.Page, r)
+	}
 
-				resultJSON, err := json.MarshalIndent(r, "", " ")
-				util.Fatal(err, "result marshalling")
+	return more, util.Error(it.Err(), "search result iteration")
+}
+
+// searchCollection writes the search results.
+// The hits are written as the documents with --hits-only, or table and CSV output,
+// while the facets and the metadata are written to stderr or --meta-out file.
+// Otherwise, the whole search result is written for each page, or rendered for the terminal.
+func searchCollection(ctx context.Context, coll string, req *driver.SearchRequest) error {
+	hitsOnly := searchHitsOnly || util.OutputFormat == util.OutputTable || util.OutputFormat == util.OutputCSV
+
+	var (
+		out *util.Output
+		err error
+	)
+
+	switch {
+	case hitsOnly:
+		out, err = util.NewOutput(os.Stdout, util.OutputNDJSON)
+	case util.OutputFormat != "":
+		out, err = util.NewOutput(os.Stdout, util.OutputJSON)
+	}
+
+	util.Fatal(err, "output format")
+
+	var cnt int64
 
-				util.Stdoutf("%s\n", resultJSON)
+	tty := util.IsTTY(os.Stdout)
+	render.Color = os.Getenv("NO_COLOR") == ""
+
+	err = searchPages(ctx, coll, req, func(resp driver.SearchResponse, r *search.Result[any]) bool {
+		if cnt == 0 && (searchMetaOut != "" || searchHitsOnly) {
+			writeSearchMeta(r)
+		}
+
+		if searchMaxResults > 0 && cnt+int64(len(r.Hits)) > searchMaxResults {
+			r.Hits = r.Hits[:searchMaxResults-cnt]
+		}
+
+		first := cnt == 0
+		cnt += int64(len(r.Hits))
+
+		switch {
+		case hitsOnly:
+			for _, h := range r.Hits {
+				err := out.Write(h.Document)
+				util.Fatal(err, "write document")
 			}
+		case out != nil:
+			err := out.Write(r)
+			util.Fatal(err, "write search result")
+		case tty:
+			searchutil.Render(os.Stdout, req, resp.Hits, r, first)
+		default:
+			resultJSON, err := json.MarshalIndent(r, "", " ")
+			util.Fatal(err, "result marshalling")
 
-			return util.Error(it.Err(), "search result iteration")
-		})
-	},
+			util.Stdoutf("%s\n", resultJSON)
+		}
+
+		return searchMaxResults == 0 || cnt < searchMaxResults
+	})
+
+	if err != nil || out == nil {
+		return err
+	}
+
+	return util.Error(out.Flush(), "flush output")
+}
+
+// writeSearchMeta writes the facets and the metadata of the search result to stderr or --meta-out file.
+func writeSearchMeta(r *search.Result[any]) {
+	meta, err := json.Marshal(struct {
+		Facets map[string]search.Facet
+		Meta   search.Meta
+	}{r.Facets, r.Meta})
+	util.Fatal(err, "marshal search metadata")
+
+	if searchMetaOut == "" {
+		util.Stderrf("%s\n", meta)
+		return
+	}
+
+	err = os.WriteFile(searchMetaOut, append(meta, '\n'), 0o600)
+	util.Fatal(err, "write search metadata")
 }
 
 func init() {
@@ -131,6 +280,24 @@ func init() {
 	dbSearchCmd.Flags().Int32VarP(&page, "page", "g", 1, "page of results to retrieve")
 	dbSearchCmd.Flags().Int32VarP(&pageSize, "pageSize", "c", 20, "count of results to be returned per page")
 
+	dbSearchCmd.Flags().BoolVar(&searchAllPages, "all-pages", false,
+		"retrieve all the pages of results, starting from --page")
+	dbSearchCmd.Flags().Int64Var(&searchMaxResults, "max-results", 0,
+		"maximum number of hits to retrieve, 0 means no limit")
+	dbSearchCmd.Flags().BoolVar(&searchHitsOnly, "hits-only", false,
+		"output hit documents as NDJSON, the facets and metadata are written to stderr or --meta-out file")
+	dbSearchCmd.Flags().StringVar(&searchMetaOut, "meta-out", "",
+		"write the facets and metadata of the results to the file")
+
+	dbSearchCmd.Flags().StringArrayVar(&searchVectors, "vector", nil,
+		"vector to find the nearest neighbors of, in the form field=[number, ...]. can be repeated")
+	dbSearchCmd.Flags().StringVar(&searchVectorFile, "vector-file", "",
+		"read the vector from the file. field=path for the JSON array of numbers, "+
+			"or path for the JSON object of the fields and arrays. - reads stdin")
+	dbSearchCmd.Flags().Int32VarP(&searchNeighbors, "neighbors", "k", 0,
+		"number of the nearest neighbors to return per page, overrides --pageSize")
+
+	addWhereFlags(dbSearchCmd)
 	addProjectFlag(dbSearchCmd)
 	dbCmd.AddCommand(dbSearchCmd)
 }
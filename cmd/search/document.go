@@ -0,0 +1,181 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/searchutil"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var (
+	deleteIDs    []string
+	deleteFilter string
+
+	ErrDeleteArgs = fmt.Errorf("either --ids or --filter is required")
+)
+
+// writeDocuments streams the documents from the arguments or stdin to the index in batches.
+// The documents are updated or, if op is "replace", created or replaced.
+func writeDocuments(cmd *cobra.Command, args []string, op string) {
+	login.Ensure(cmd.Context(), func(ctx context.Context) error {
+		return iterate.Input(ctx, cmd, 1, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			ptr := unsafe.Pointer(&docs)
+
+			write := client.GetSearch().Update
+			if op == "replace" {
+				write = client.GetSearch().CreateOrReplace
+			}
+
+			statuses, err := write(ctx, args[0], *(*[]driver.Document)(ptr))
+			if err != nil {
+				return util.Error(err, "%s documents failed", op)
+			}
+
+			return util.Error(searchutil.StatusError(statuses), "%s documents", op)
+		})
+	})
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get {index} {id}...",
+	Short: "Gets documents by the ids",
+	Long:  "Gets documents of the index by the ids. Ids which are not found are skipped.",
+	Example: fmt.Sprintf(`
+  # Get the documents with ids 1 and 2
+  %[1]s get --project=myproj users 1 2
+`, "tigris search"),
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			hits, err := client.GetSearch().Get(ctx, args[0], args[1:])
+			if err != nil {
+				return util.Error(err, "get documents")
+			}
+
+			out, err := util.NewOutput(os.Stdout, util.OutputNDJSON)
+			util.Fatal(err, "output format")
+
+			for _, h := range hits {
+				if h == nil || len(h.GetData()) == 0 {
+					continue
+				}
+
+				err = out.Write(json.RawMessage(h.GetData()))
+				util.Fatal(err, "write document")
+			}
+
+			return util.Error(out.Flush(), "flush output")
+		})
+	},
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update {index} {document}...|-",
+	Short: "Updates document(s)",
+	Long: `Updates existing documents of the index. The documents are matched by the id field.
+Only the fields present in the documents are changed.`,
+	Example: fmt.Sprintf(`
+  # Update name of the document with id 1
+  %[1]s update --project=myproj users '{"id": "1", "name": "Alice Wong"}'
+
+  # Update documents streamed from the file
+  %[1]s update --project=myproj users - <users.json
+`, "tigris search"),
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		writeDocuments(cmd, args, "update")
+	},
+}
+
+var replaceCmd = &cobra.Command{
+	Use:     "replace {index} {document}...|-",
+	Aliases: []string{"create_or_replace"},
+	Short:   "Creates or replaces document(s)",
+	Long:    "Creates new documents or replaces existing documents of the index with the same id.",
+	Example: fmt.Sprintf(`
+  # Create or replace the documents
+  %[1]s replace --project=myproj users '[{"id": "1", "name": "Alice"}, {"id": "2", "name": "Bob"}]'
+
+  # Replace documents streamed from the file
+  %[1]s replace --project=myproj users - <users.json
+`, "tigris search"),
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		writeDocuments(cmd, args, "replace")
+	},
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete {index}",
+	Short: "Deletes document(s)",
+	Long:  "Deletes documents of the index by the ids or by the filter.",
+	Example: fmt.Sprintf(`
+  # Delete documents with ids 1 and 2
+  %[1]s delete --project=myproj users --ids 1,2
+
+  # Delete documents matching the filter
+  %[1]s delete --project=myproj users --filter '{"status": "inactive"}'
+`, "tigris search"),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if (len(deleteIDs) == 0) == (deleteFilter == "") {
+			util.Fatal(ErrDeleteArgs, "delete documents")
+		}
+
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			if deleteFilter != "" {
+				n, err := client.GetSearch().DeleteByQuery(ctx, args[0], driver.Filter(deleteFilter))
+				if err != nil {
+					return util.Error(err, "delete documents")
+				}
+
+				util.Infof("deleted %d documents", n)
+
+				return nil
+			}
+
+			statuses, err := client.GetSearch().Delete(ctx, args[0], deleteIDs)
+			if err != nil {
+				return util.Error(err, "delete documents")
+			}
+
+			return util.Error(searchutil.StatusError(statuses), "delete documents")
+		})
+	},
+}
+
+func init() {
+	updateCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+	replaceCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+
+	deleteCmd.Flags().StringSliceVar(&deleteIDs, "ids", nil, "comma separated ids of the documents to delete")
+	deleteCmd.Flags().StringVar(&deleteFilter, "filter", "", "delete the documents matching the filter")
+
+	for _, c := range []*cobra.Command{getCmd, updateCmd, replaceCmd, deleteCmd} {
+		addProjectFlag(c)
+		RootCmd.AddCommand(c)
+	}
+}
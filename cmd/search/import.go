@@ -161,7 +161,7 @@ func init() {
 	importCmd.Flags().Int32VarP(&InferenceDepth, "inference-depth", "d", 0,
 		"Number of records in the beginning of the stream to detect field types. It's equal to batch size if not set")
 	importCmd.Flags().StringSliceVar(&AutoGenerate, "autogenerate", []string{},
-		"Comma separated list of autogenerated fields (only top level keys supported)")
+		"Comma separated list of autogenerated fields. Use dots for nested fields: meta.uuid")
 	importCmd.Flags().BoolVar(&CleanUpNULLs, "cleanup-null-values", true,
 		"Remove NULL values and empty arrays from the documents before importing")
 	importCmd.Flags().BoolVar(&UpdateSchema, "update-schema", false,
@@ -180,6 +180,10 @@ func init() {
 		"Try to detect date time fields")
 	importCmd.Flags().BoolVar(&schema.DetectIntegers, "detect-integers", true,
 		"Try to detect integer fields")
+	importCmd.Flags().BoolVar(&schema.DetectVectors, "detect-vectors", false,
+		"Try to detect vector fields. Arrays of numbers of the same length are considered vectors")
+	importCmd.Flags().IntVar(&schema.VectorMinDimensions, "vector-min-dimensions", schema.VectorMinDimensions,
+		"Minimum number of array elements to be detected as vector")
 
 	importCmd.Flags().StringVar(&CSVDelimiter, "csv-delimiter", "",
 		"CSV delimiter")
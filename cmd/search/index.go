@@ -73,7 +73,7 @@ var describeIndexCmd = &cobra.Command{
 				Schema: resp.Schema,
 			}
 
-			err = util.PrettyJSON(tr)
+			err = util.PrintOutput(util.OutputJSON, tr)
 			util.Fatal(err, "describe index marshal")
 
 			return nil
@@ -91,11 +91,12 @@ var listIndexesCmd = &cobra.Command{
 				return util.Error(err, "list indexes")
 			}
 
+			names := make([]string, 0, len(resp))
 			for _, v := range resp {
-				util.Stdoutf("%s\n", v)
+				names = append(names, v.Name)
 			}
 
-			return nil
+			return util.Error(util.PrintList("index", names), "list indexes")
 		})
 	},
 }
@@ -0,0 +1,203 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/render"
+	"github.com/tigrisdata/tigris-cli/searchutil"
+	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	"github.com/tigrisdata/tigris-client-go/search"
+)
+
+var (
+	queryString   string
+	searchFields  []string
+	queryFilter   string
+	queryFacet    string
+	querySort     []string
+	includeFields []string
+	excludeFields []string
+	queryPage     int32
+	queryPageSize int32
+	allPages      bool
+
+	vectors    []string
+	vectorFile string
+	neighbors  int32
+)
+
+// queryIndexPage passes the page of the search results to fn and returns true if there are more pages after it.
+func queryIndexPage(ctx context.Context, index string, req *driver.SearchRequest,
+	fn func(hits []*api.SearchHit, r *search.Result[any]),
+) (bool, error) {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	it, err := client.GetSearch().Search(ctx, index, req)
+	if err != nil {
+		return false, util.Error(err, "search failed")
+	}
+	defer it.Close()
+
+	var (
+		resp driver.SearchIndexResponse
+		more bool
+	)
+
+	for it.Next(&resp) {
+		r := &search.Result[any]{}
+		err := r.FromIndexResponse(resp)
+		util.Fatal(err, "search result conversion")
+
+		fn(resp.GetHits(), r)
+
+		more = searchutil.More(req.Page, r)
+	}
+
+	return more, util.Error(it.Err(), "search result iteration")
+}
+
+// queryIndex writes the search results.
+// The results are rendered for the terminal, otherwise written in --output format:
+// the hits for table and CSV output and the whole search result of each page for the others.
+func queryIndex(ctx context.Context, index string, req *driver.SearchRequest) error {
+	tty := util.IsTTY(os.Stdout) && util.OutputFormat == ""
+	hitsOnly := util.OutputFormat == util.OutputTable || util.OutputFormat == util.OutputCSV
+
+	var (
+		out *util.Output
+		err error
+	)
+
+	if !tty {
+		out, err = util.NewOutput(os.Stdout, util.OutputJSON)
+		util.Fatal(err, "output format")
+	}
+
+	render.Color = os.Getenv("NO_COLOR") == ""
+	first := true
+
+	err = searchutil.Pages(req, allPages, func(req *driver.SearchRequest) (bool, error) {
+		return queryIndexPage(ctx, index, req, func(hits []*api.SearchHit, r *search.Result[any]) {
+			switch {
+			case out == nil:
+				searchutil.Render(os.Stdout, req, hits, r, first)
+			case hitsOnly:
+				for _, h := range r.Hits {
+					err := out.Write(h.Document)
+					util.Fatal(err, "write document")
+				}
+			default:
+				err := out.Write(r)
+				util.Fatal(err, "write search result")
+			}
+
+			first = false
+		})
+	})
+
+	if err != nil || out == nil {
+		return err
+	}
+
+	return util.Error(out.Flush(), "flush output")
+}
+
+var queryCmd = &cobra.Command{
+	Use:   "query {index}",
+	Short: "Searches an index for documents matching the query",
+	Long: `Executes a search query against the index and returns the search results.
+
+When the output is a terminal, facets are shown as histograms and hits as a table,
+with the query terms highlighted. Set NO_COLOR environment variable to disable the highlighting.
+Otherwise, the search results are written in --output format, JSON by default.
+Table and CSV output contain the hits only.`,
+	Example: fmt.Sprintf(`
+  # Search for a text "Alice" either in "firstName" or "lastName" fields
+  %[1]s query --project=myproj users -q "Alice" -f "firstName,lastName"
+
+  # Find 10 nearest neighbors of the vector in "embedding" field among the users older than 23
+  %[1]s query --project=myproj users --vector 'embedding=[0.12, -0.3, 0.8]' -k 10 --filter '{"age": {"$gt": 23}}'
+
+  # Same with the vector read from stdin, retrieving all the pages of 10 neighbors
+  cat vector.json | %[1]s query --project=myproj users --vector-file embedding=- -k 10 --all-pages
+`, "tigris search"),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(_ context.Context) error {
+			opts := &searchutil.Options{
+				Query:         queryString,
+				SearchFields:  searchFields,
+				Filter:        queryFilter,
+				Facet:         queryFacet,
+				Sort:          querySort,
+				IncludeFields: includeFields,
+				ExcludeFields: excludeFields,
+				Page:          queryPage,
+				PageSize:      queryPageSize,
+				Vectors:       vectors,
+				VectorFile:    vectorFile,
+				Neighbors:     neighbors,
+			}
+
+			req, err := opts.Request()
+			if err != nil {
+				return util.Error(err, "search request")
+			}
+
+			// pages use own timeouts
+			return queryIndex(cmd.Context(), args[0], req)
+		})
+	},
+}
+
+func init() {
+	queryCmd.Flags().SortFlags = false
+
+	queryCmd.Flags().StringVarP(&queryString, "query", "q", "", "query string for searching across text fields")
+	queryCmd.Flags().StringSliceVarP(&searchFields, "searchFields", "f", []string{},
+		"comma separated value of fields to project search query against")
+	queryCmd.Flags().StringVar(&queryFilter, "filter", "{}", "further refine the search results using filters")
+	queryCmd.Flags().StringVar(&queryFacet, "facet", "{}", "retrieve aggregate ")
+	queryCmd.Flags().StringSliceVar(&querySort, "sort", nil, "order to sort the results")
+	queryCmd.Flags().StringSliceVarP(&includeFields, "includeFields", "i", []string{},
+		"comma separated value of document fields to include in results")
+	queryCmd.Flags().StringSliceVarP(&excludeFields, "excludeFields", "x", []string{},
+		"comma separated value of document fields to exclude in results")
+	queryCmd.Flags().Int32VarP(&queryPage, "page", "g", 1, "page of results to retrieve")
+	queryCmd.Flags().Int32VarP(&queryPageSize, "pageSize", "c", 20, "count of results to be returned per page")
+	queryCmd.Flags().BoolVar(&allPages, "all-pages", false,
+		"retrieve all the pages of results, starting from --page")
+
+	queryCmd.Flags().StringArrayVar(&vectors, "vector", nil,
+		"vector to find the nearest neighbors of, in the form field=[number, ...]. can be repeated")
+	queryCmd.Flags().StringVar(&vectorFile, "vector-file", "",
+		"read the vector from the file. field=path for the JSON array of numbers, "+
+			"or path for the JSON object of the fields and arrays. - reads stdin")
+	queryCmd.Flags().Int32VarP(&neighbors, "neighbors", "k", 0,
+		"number of the nearest neighbors to return per page, overrides --pageSize")
+
+	addProjectFlag(queryCmd)
+	RootCmd.AddCommand(queryCmd)
+}
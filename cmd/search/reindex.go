@@ -0,0 +1,435 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/reindex"
+	"github.com/tigrisdata/tigris-cli/searchutil"
+	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	cschema "github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const reindexTempSuffix = "_reindex"
+
+var (
+	fromCollection string
+	reindexIndex   string
+	reindexFields  []string
+	reindexSwap    bool
+)
+
+// indexConverter derives the index schema and the document converter from the collection schema.
+func indexConverter(ctx context.Context, coll string, index string, fields []string,
+) (*cschema.Schema, *reindex.Converter, error) {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	resp, err := client.GetDB(ctx).DescribeCollection(ctx, coll)
+	if err != nil {
+		return nil, nil, util.Error(err, "describe collection")
+	}
+
+	var sch cschema.Schema
+	if err = json.Unmarshal(resp.Schema, &sch); err != nil {
+		return nil, nil, util.Error(err, "unmarshal collection schema")
+	}
+
+	isch, conv, err := reindex.New(&sch, index, fields)
+
+	return isch, conv, util.Error(err, "derive index schema")
+}
+
+// readConverted streams all the documents of the collection converted to the index documents
+// in batches to fn.
+func readConverted(ctx context.Context, coll string, conv *reindex.Converter,
+	fn func(docs []driver.Document, ids []string) error,
+) error {
+	it, err := client.GetDB(ctx).Read(ctx, coll, driver.Filter(`{}`), driver.Projection(`{}`))
+	if err != nil {
+		return util.Error(err, "read collection")
+	}
+	defer it.Close()
+
+	var (
+		doc  driver.Document
+		docs []driver.Document
+		ids  []string
+	)
+
+	for it.Next(&doc) {
+		idoc, id, err := conv.Convert(json.RawMessage(doc))
+		if err != nil {
+			return util.Error(err, "convert document %s", string(doc))
+		}
+
+		docs = append(docs, driver.Document(idoc))
+		ids = append(ids, id)
+
+		if int32(len(docs)) >= iterate.BatchSize {
+			if err = fn(docs, ids); err != nil {
+				return err
+			}
+
+			docs, ids = nil, nil
+		}
+	}
+
+	if err = it.Err(); err != nil {
+		return util.Error(err, "read collection")
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	return fn(docs, ids)
+}
+
+func replaceIndexDocuments(ctx context.Context, index string, docs []driver.Document) error {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	statuses, err := client.GetSearch().CreateOrReplace(ctx, index, docs)
+	if err != nil {
+		return util.Error(err, "load documents")
+	}
+
+	return util.Error(searchutil.StatusError(statuses), "load documents")
+}
+
+// createIndexSchema creates or updates the index with the schema.
+func createIndexSchema(ctx context.Context, index string, sch *cschema.Schema) error {
+	sch.Name = index
+
+	raw, err := json.Marshal(sch)
+	if err != nil {
+		return util.Error(err, "marshal index schema")
+	}
+
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	return createIndex(ctx, raw)
+}
+
+// loadIndex creates or updates the index and loads all the collection documents to it.
+func loadIndex(ctx context.Context, coll string, index string, sch *cschema.Schema, conv *reindex.Converter) error {
+	if err := createIndexSchema(ctx, index, sch); err != nil {
+		return err
+	}
+
+	var cnt int
+
+	err := readConverted(ctx, coll, conv, func(docs []driver.Document, _ []string) error {
+		cnt += len(docs)
+
+		return replaceIndexDocuments(ctx, index, docs)
+	})
+	if err != nil {
+		return err
+	}
+
+	util.Infof("loaded %d documents into index %s", cnt, index)
+
+	return nil
+}
+
+// indexScanRequest returns the request of the first page of all the index documents in the id order.
+func indexScanRequest(fields []string) *driver.SearchRequest {
+	return &driver.SearchRequest{
+		Filter:        driver.Filter(`{}`),
+		Sort:          driver.SortOrder{json.RawMessage(`{"` + reindex.IDField + `": "$asc"}`)},
+		IncludeFields: fields,
+		Page:          1,
+		PageSize:      iterate.BatchSize,
+	}
+}
+
+// indexPage returns the documents of the index page and whether there are more pages.
+func indexPage(ctx context.Context, index string, req *driver.SearchRequest) ([]driver.Document, bool, error) {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	it, err := client.GetSearch().Search(ctx, index, req)
+	if err != nil {
+		return nil, false, util.Error(err, "search index %s", index)
+	}
+	defer it.Close()
+
+	var (
+		resp driver.SearchIndexResponse
+		docs []driver.Document
+		more bool
+	)
+
+	for it.Next(&resp) {
+		for _, h := range resp.GetHits() {
+			docs = append(docs, h.GetData())
+		}
+
+		more = len(resp.GetHits()) > 0 && req.Page < resp.GetMeta().GetTotalPages()
+	}
+
+	return docs, more, util.Error(it.Err(), "search index %s", index)
+}
+
+// copyIndex copies all the documents of the src index into the dst index.
+func copyIndex(ctx context.Context, src string, dst string) error {
+	var cnt int
+
+	err := searchutil.Pages(indexScanRequest(nil), true, func(req *driver.SearchRequest) (bool, error) {
+		docs, more, err := indexPage(ctx, src, req)
+		if err != nil || len(docs) == 0 {
+			return false, err
+		}
+
+		cnt += len(docs)
+
+		return more, replaceIndexDocuments(ctx, dst, docs)
+	})
+	if err != nil {
+		return err
+	}
+
+	util.Infof("copied %d documents from index %s into index %s", cnt, src, dst)
+
+	return nil
+}
+
+// indexIDs returns the ids of the documents which exist in the index.
+func indexIDs(ctx context.Context, index string, ids []string) (map[string]bool, error) {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	hits, err := client.GetSearch().Get(ctx, index, ids)
+	if err != nil {
+		return nil, util.Error(err, "get index documents")
+	}
+
+	res := make(map[string]bool, len(hits))
+
+	for _, h := range hits {
+		if h == nil || len(h.GetData()) == 0 {
+			continue
+		}
+
+		id, err := reindex.DocID(h.GetData())
+		if err != nil {
+			return nil, util.Error(err, "index document id")
+		}
+
+		res[id] = true
+	}
+
+	return res, nil
+}
+
+// deleteIndexDocuments deletes the documents from the index in batches.
+func deleteIndexDocuments(ctx context.Context, index string, ids []string) error {
+	for len(ids) > 0 {
+		n := int(iterate.BatchSize)
+		if n > len(ids) {
+			n = len(ids)
+		}
+
+		tctx, cancel := util.GetContext(ctx)
+		statuses, err := client.GetSearch().Delete(tctx, index, ids[:n])
+
+		cancel()
+
+		if err != nil {
+			return util.Error(err, "delete index documents")
+		}
+
+		if err = searchutil.StatusError(statuses); err != nil {
+			return util.Error(err, "delete index documents")
+		}
+
+		ids = ids[n:]
+	}
+
+	return nil
+}
+
+// pruneIndex deletes the documents of the index which are not in the src index.
+// They are deleted after all the pages are scanned, so the pages don't shift.
+func pruneIndex(ctx context.Context, index string, src string) error {
+	var extra []string
+
+	err := searchutil.Pages(indexScanRequest([]string{reindex.IDField}), true,
+		func(req *driver.SearchRequest) (bool, error) {
+			docs, more, err := indexPage(ctx, index, req)
+			if err != nil || len(docs) == 0 {
+				return false, err
+			}
+
+			ids := make([]string, 0, len(docs))
+
+			for _, d := range docs {
+				id, err := reindex.DocID(json.RawMessage(d))
+				if err != nil {
+					return false, util.Error(err, "index document id")
+				}
+
+				ids = append(ids, id)
+			}
+
+			found, err := indexIDs(ctx, src, ids)
+			if err != nil {
+				return false, err
+			}
+
+			for _, id := range ids {
+				if !found[id] {
+					extra = append(extra, id)
+				}
+			}
+
+			return more, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	if err = deleteIndexDocuments(ctx, index, extra); err != nil {
+		return err
+	}
+
+	util.Infof("deleted %d documents which are not in index %s from index %s", len(extra), src, index)
+
+	return nil
+}
+
+func dropIndex(ctx context.Context, index string) error {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	err := client.GetSearch().DeleteIndex(ctx, index)
+
+	//nolint:golint,errorlint
+	if ep, ok := err.(*driver.Error); ok && ep.Code == api.Code_NOT_FOUND {
+		return nil
+	}
+
+	return util.Error(err, "delete index %s", index)
+}
+
+// reindexCollection loads the collection into the index.
+// With swap the collection is loaded into the temporary index first,
+// and only after the successful load the index is updated from the temporary index,
+// so it gets exactly the documents which were loaded.
+// The index is never dropped, but as the indexes can't be renamed, the update is not atomic.
+func reindexCollection(ctx context.Context, coll string, index string) error {
+	sch, conv, err := indexConverter(ctx, coll, index, reindexFields)
+	if err != nil {
+		return err
+	}
+
+	if !reindexSwap {
+		return loadIndex(ctx, coll, index, sch, conv)
+	}
+
+	tmp := index + reindexTempSuffix
+
+	if err = dropIndex(ctx, tmp); err != nil {
+		return err
+	}
+
+	if err = loadIndex(ctx, coll, tmp, sch, conv); err != nil {
+		_ = dropIndex(ctx, tmp)
+
+		return fmt.Errorf("%w, index %s is left unchanged", err, index)
+	}
+
+	if err = createIndexSchema(ctx, index, sch); err != nil {
+		return fmt.Errorf("%w, complete index is kept in %s", err, tmp)
+	}
+
+	if err = copyIndex(ctx, tmp, index); err != nil {
+		return fmt.Errorf("%w, complete index is kept in %s", err, tmp)
+	}
+
+	if err = pruneIndex(ctx, index, tmp); err != nil {
+		return fmt.Errorf("%w, complete index is kept in %s", err, tmp)
+	}
+
+	return dropIndex(ctx, tmp)
+}
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Builds or rebuilds search index from a collection",
+	Long: `Derives the index schema from the collection schema and loads all the documents
+of the collection into the index.
+
+The id of the index documents is the primary key of the collection documents,
+or JSON array of the values for the composite primary key.
+
+Without --swap, the documents are created or replaced in the existing index,
+the documents which are no longer in the collection are kept in the index.
+
+With --swap, the collection is loaded into the temporary index first, which
+protects the index from the failed loads. Only after the successful load,
+the documents of the temporary index are copied into the index, the documents
+which are not in the temporary index are deleted from the index,
+and the temporary index is removed.
+The index is never deleted, but the swap is NOT atomic: indexes can't be renamed,
+so searches may return a mix of old and new documents while the index is updated.`,
+	Example: fmt.Sprintf(`
+  # Build or update the index from the collection
+  %[1]s reindex --project=myproj --from-collection users --index users_idx
+
+  # Rebuild the index with the name and bio fields only, after the successful load
+  %[1]s reindex --project=myproj --from-collection users --index users_idx --fields name,bio --swap
+`, "tigris search"),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		index := reindexIndex
+		if index == "" {
+			index = fromCollection
+		}
+
+		login.Ensure(cmd.Context(), func(_ context.Context) error {
+			// reading the whole collection uses no timeout, requests use own timeouts
+			return reindexCollection(cmd.Context(), fromCollection, index)
+		})
+	},
+}
+
+func init() {
+	reindexCmd.Flags().StringVar(&fromCollection, "from-collection", "", "collection to load the documents from")
+	reindexCmd.Flags().StringVar(&reindexIndex, "index", "", "index to load into. collection name if not set")
+	reindexCmd.Flags().StringSliceVar(&reindexFields, "fields", nil,
+		"comma separated fields to index, in addition to the primary key. all the fields if not set")
+	reindexCmd.Flags().BoolVar(&reindexSwap, "swap", false,
+		"load into the temporary index first and update the index from it after the successful load. "+
+			"not atomic, the index has a mix of old and new documents while updating")
+	reindexCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+
+	_ = reindexCmd.MarkFlagRequired("from-collection")
+
+	addProjectFlag(reindexCmd)
+	RootCmd.AddCommand(reindexCmd)
+}
@@ -0,0 +1,312 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/reindex"
+	"github.com/tigrisdata/tigris-cli/searchutil"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+const (
+	statusMissing = "missing"
+	statusStale   = "stale"
+	statusExtra   = "extra"
+)
+
+var (
+	verifyCollection string
+	verifyIndex      string
+	verifyFields     []string
+	verifyRepair     bool
+
+	ErrIndexDrift = fmt.Errorf("index differs from the collection")
+)
+
+// verifier compares the collection documents with the index documents and repairs the differences.
+// Both sides are streamed in batches: the collection documents are looked up in the index by the ids,
+// and the index documents, scanned in the id order, are looked up in the collection by the primary keys.
+type verifier struct {
+	coll     string
+	index    string
+	conv     *reindex.Converter
+	out      *util.Output
+	verified int
+	counts   map[string]int
+}
+
+func (v *verifier) report(status string, id string) {
+	v.counts[status]++
+
+	err := v.out.Write(map[string]any{"id": id, "status": status})
+	util.Fatal(err, "write report")
+}
+
+func (v *verifier) total() int {
+	return v.counts[statusMissing] + v.counts[statusStale] + v.counts[statusExtra]
+}
+
+// indexHashes returns the content hashes of the index documents by the ids.
+func (v *verifier) indexHashes(ctx context.Context, ids []string) (map[string]string, error) {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	hits, err := client.GetSearch().Get(ctx, v.index, ids)
+	if err != nil {
+		return nil, util.Error(err, "get index documents")
+	}
+
+	res := make(map[string]string, len(hits))
+
+	for _, h := range hits {
+		if h == nil || len(h.GetData()) == 0 {
+			continue
+		}
+
+		id, err := reindex.DocID(h.GetData())
+		if err != nil {
+			return nil, util.Error(err, "index document id")
+		}
+
+		if res[id], err = reindex.Hash(h.GetData()); err != nil {
+			return nil, util.Error(err, "hash index document")
+		}
+	}
+
+	return res, nil
+}
+
+// verifyBatch reports the collection documents which are missing or stale in the index.
+func (v *verifier) verifyBatch(ctx context.Context, docs []driver.Document, ids []string) error {
+	hashes, err := v.indexHashes(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var repair []driver.Document
+
+	v.verified += len(ids)
+
+	for i, id := range ids {
+		h, err := reindex.Hash(json.RawMessage(docs[i]))
+		if err != nil {
+			return util.Error(err, "hash document")
+		}
+
+		ih, ok := hashes[id]
+
+		switch {
+		case !ok:
+			v.report(statusMissing, id)
+		case ih != h:
+			v.report(statusStale, id)
+		default:
+			continue
+		}
+
+		repair = append(repair, docs[i])
+	}
+
+	if !verifyRepair || len(repair) == 0 {
+		return nil
+	}
+
+	return replaceIndexDocuments(ctx, v.index, repair)
+}
+
+// collectionIDs returns the index ids of the collection documents having the primary keys of the index documents.
+func (v *verifier) collectionIDs(ctx context.Context, docs []driver.Document) (map[string]bool, error) {
+	raw := make([]json.RawMessage, 0, len(docs))
+	for _, d := range docs {
+		raw = append(raw, json.RawMessage(d))
+	}
+
+	filter, err := v.conv.KeyFilter(raw)
+	if err != nil {
+		return nil, util.Error(err, "primary key filter")
+	}
+
+	proj := make(map[string]bool)
+	for _, k := range v.conv.PrimaryKey() {
+		proj[k] = true
+	}
+
+	projJSON, err := json.Marshal(proj)
+	if err != nil {
+		return nil, util.Error(err, "primary key projection")
+	}
+
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	it, err := client.GetDB(ctx).Read(ctx, v.coll, driver.Filter(filter), driver.Projection(projJSON))
+	if err != nil {
+		return nil, util.Error(err, "read collection")
+	}
+	defer it.Close()
+
+	var doc driver.Document
+
+	res := make(map[string]bool, len(docs))
+
+	for it.Next(&doc) {
+		_, id, err := v.conv.Convert(json.RawMessage(doc))
+		if err != nil {
+			return nil, util.Error(err, "convert document %s", string(doc))
+		}
+
+		res[id] = true
+	}
+
+	return res, util.Error(it.Err(), "read collection")
+}
+
+// verifyExtra reports the index documents which are not in the collection.
+// They are deleted after all the pages are scanned, so the pages don't shift.
+func (v *verifier) verifyExtra(ctx context.Context) error {
+	var extra []string
+
+	fields := append([]string{reindex.IDField}, v.conv.PrimaryKey()...)
+
+	err := searchutil.Pages(indexScanRequest(fields), true, func(req *driver.SearchRequest) (bool, error) {
+		docs, more, err := indexPage(ctx, v.index, req)
+		if err != nil || len(docs) == 0 {
+			return false, err
+		}
+
+		ids, err := v.collectionIDs(ctx, docs)
+		if err != nil {
+			return false, err
+		}
+
+		for _, d := range docs {
+			id, err := reindex.DocID(json.RawMessage(d))
+			if err != nil {
+				return false, util.Error(err, "index document id")
+			}
+
+			if !ids[id] {
+				v.report(statusExtra, id)
+				extra = append(extra, id)
+			}
+		}
+
+		return more, nil
+	})
+	if err != nil || !verifyRepair {
+		return err
+	}
+
+	return deleteIndexDocuments(ctx, v.index, extra)
+}
+
+// verifyCollectionIndex compares the collection and the index and writes the report of the differences.
+func verifyCollectionIndex(ctx context.Context, coll string, index string) error {
+	_, conv, err := indexConverter(ctx, coll, index, verifyFields)
+	if err != nil {
+		return err
+	}
+
+	out, err := util.NewOutput(os.Stdout, util.OutputTable)
+	util.Fatal(err, "output format")
+
+	v := &verifier{coll: coll, index: index, conv: conv, out: out, counts: make(map[string]int)}
+
+	if err = readConverted(ctx, coll, conv, func(docs []driver.Document, ids []string) error {
+		return v.verifyBatch(ctx, docs, ids)
+	}); err != nil {
+		return err
+	}
+
+	if err = v.verifyExtra(ctx); err != nil {
+		return err
+	}
+
+	if err = out.Flush(); err != nil {
+		return util.Error(err, "flush output")
+	}
+
+	util.Stderrf("%d documents verified, %d missing, %d stale, %d extra\n", v.verified,
+		v.counts[statusMissing], v.counts[statusStale], v.counts[statusExtra])
+
+	switch {
+	case v.total() == 0:
+		return nil
+	case verifyRepair:
+		util.Stderrf("%d differences repaired\n", v.total())
+
+		return nil
+	default:
+		return ErrIndexDrift
+	}
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verifies search index is consistent with a collection",
+	Long: `Compares the primary keys and the content hashes of all the documents of the collection
+with the documents of the index, built by "reindex" command.
+
+Reports the documents which are missing in the index, which are stale in the index,
+and extra documents of the index, which are not in the collection.
+Exits with non-zero status if the differences are found and not repaired.
+
+With --repair, the missing and stale documents are replaced in the index
+and the extra documents are deleted from the index.`,
+	Example: fmt.Sprintf(`
+  # Report the differences between the collection and the index
+  %[1]s verify --project=myproj --collection users --index users_idx
+
+  # Fix the differences of the index built with the name and bio fields only
+  %[1]s verify --project=myproj --collection users --index users_idx --fields name,bio --repair
+`, "tigris search"),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		index := verifyIndex
+		if index == "" {
+			index = verifyCollection
+		}
+
+		login.Ensure(cmd.Context(), func(_ context.Context) error {
+			// reading the whole collection uses no timeout, requests use own timeouts
+			return verifyCollectionIndex(cmd.Context(), verifyCollection, index)
+		})
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyCollection, "collection", "", "collection to verify the index against")
+	verifyCmd.Flags().StringVar(&verifyIndex, "index", "", "index to verify. collection name if not set")
+	verifyCmd.Flags().StringSliceVar(&verifyFields, "fields", nil,
+		"comma separated fields the index is built with by reindex --fields. all the fields if not set")
+	verifyCmd.Flags().BoolVar(&verifyRepair, "repair", false,
+		"replace missing and stale documents and delete extra documents of the index")
+	verifyCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+
+	_ = verifyCmd.MarkFlagRequired("collection")
+
+	addProjectFlag(verifyCmd)
+	RootCmd.AddCommand(verifyCmd)
+}
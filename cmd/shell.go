@@ -0,0 +1,477 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/shell"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	"golang.org/x/term"
+)
+
+var (
+	ErrTxActive   = fmt.Errorf("transaction is in progress, commit or rollback it first")
+	ErrNoTx       = fmt.Errorf("no transaction in progress")
+	ErrShellUsage = fmt.Errorf("usage: use project {name} | use branch {name}")
+	ErrTxTarget   = fmt.Errorf("--project and --branch can't be changed in the transaction")
+)
+
+var shellBuiltins = []string{"begin", "commit", "rollback", "use", "exit", "quit"}
+
+// shellExit is the panic value of util.Exit called by the failed command in the shell.
+type shellExit int
+
+type shellState struct {
+	project string
+	branch  string
+	tx      driver.Tx
+
+	term    *term.Terminal
+	scanner *bufio.Scanner
+
+	// words are the collection and field names for completion, fetched on the first use
+	words []string
+}
+
+func (s *shellState) prompt() string {
+	p := s.project
+	if s.branch != "" {
+		p += "@" + s.branch
+	}
+
+	if s.tx != nil {
+		p += " (tx)"
+	}
+
+	return p + "> "
+}
+
+// readLine reads the line from the terminal, with line editing and history, or from non-interactive input.
+func (s *shellState) readLine(prompt string) (string, error) {
+	if s.term == nil {
+		if !s.scanner.Scan() {
+			if err := s.scanner.Err(); err != nil {
+				return "", err
+			}
+
+			return "", io.EOF
+		}
+
+		return s.scanner.Text(), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	st, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = term.Restore(fd, st) }()
+
+	if w, h, err := term.GetSize(fd); err == nil {
+		_ = s.term.SetSize(w, h)
+	}
+
+	s.term.SetPrompt(prompt)
+
+	return s.term.ReadLine()
+}
+
+// readStatement reads the lines until the statement is complete,
+// so JSON documents can span multiple lines.
+func (s *shellState) readStatement() (string, error) {
+	stmt, err := s.readLine(s.prompt())
+	if err != nil {
+		return "", err
+	}
+
+	for !shell.Complete(stmt) {
+		line, err := s.readLine("... ")
+		if err != nil {
+			return "", err
+		}
+
+		stmt += "\n" + line
+	}
+
+	return stmt, nil
+}
+
+func (s *shellState) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	commands := append([]string{}, shellBuiltins...)
+	for _, c := range rootCmd.Commands() {
+		commands = append(commands, c.Name())
+		commands = append(commands, c.Aliases...)
+	}
+
+	newLine, newPos, matches := shell.Completion(line, pos, commands, s.completionWords())
+	if len(matches) > 1 && newPos == pos {
+		_, _ = fmt.Fprintf(s.term, "%s\n", strings.Join(matches, "  "))
+	}
+
+	return newLine, newPos, true
+}
+
+// completionWords returns collection and field names of the project, described once per project and branch.
+func (s *shellState) completionWords() []string {
+	if s.words != nil {
+		return s.words
+	}
+
+	ctx, cancel := util.GetContext(context.Background())
+	defer cancel()
+
+	resp, err := client.Get().DescribeDatabase(ctx, s.project)
+	if err != nil {
+		log.Err(err).Msg("describe database for completion")
+		return nil
+	}
+
+	set := make(map[string]bool)
+
+	for _, c := range resp.Collections {
+		set[c.Collection] = true
+
+		fields, err := shell.Fields(c.Schema)
+		if err != nil {
+			log.Err(err).Str("collection", c.Collection).Msg("parse schema for completion")
+			continue
+		}
+
+		for _, f := range fields {
+			set[f] = true
+		}
+	}
+
+	s.words = make([]string, 0, len(set))
+	for w := range set {
+		s.words = append(s.words, w)
+	}
+
+	return s.words
+}
+
+// builtin executes shell's own commands. Returns false if args is not a builtin.
+func (s *shellState) builtin(ctx context.Context, args []string) (bool, error) {
+	switch args[0] {
+	case "begin":
+		if s.tx != nil {
+			return true, ErrTxActive
+		}
+
+		// the transaction outlives the command, so it's bound to the shell's context
+		tx, err := client.GetDB(ctx).BeginTx(ctx)
+		if err != nil {
+			return true, util.Error(err, "begin transaction")
+		}
+
+		s.tx = tx
+	case "commit", "rollback":
+		if s.tx == nil {
+			return true, ErrNoTx
+		}
+
+		tctx, cancel := util.GetContext(ctx)
+		defer cancel()
+
+		var err error
+		if args[0] == "commit" {
+			err = s.tx.Commit(tctx)
+		} else {
+			err = s.tx.Rollback(tctx)
+		}
+
+		// the transaction is finished even if commit has failed
+		s.tx = nil
+
+		return true, util.Error(err, "%s transaction", args[0])
+	case "use":
+		return true, s.use(args)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// use switches the project or the branch.
+func (s *shellState) use(args []string) error {
+	if len(args) != 3 || (args[1] != "project" && args[1] != "branch") {
+		return ErrShellUsage
+	}
+
+	if s.tx != nil {
+		return ErrTxActive
+	}
+
+	if args[1] == "project" {
+		s.project = args[2]
+	} else {
+		s.branch = args[2]
+	}
+
+	config.Project = s.project
+
+	s.words = nil
+
+	return connect(s.project, s.branch)
+}
+
+// connect reinitializes the client, because the branch is the parameter of the connection.
+func connect(project string, branch string) error {
+	config.DefaultConfig.Project = project
+	config.DefaultConfig.Branch = branch
+
+	return client.Init(&config.DefaultConfig)
+}
+
+// commandTarget returns the project and the branch of the command,
+// which can be overridden by its --project and --branch flags.
+func (s *shellState) commandTarget(args []string) (string, string) {
+	project, branch := s.project, s.branch
+
+	c, flags, err := rootCmd.Find(args)
+	if err != nil {
+		return project, branch
+	}
+
+	// invalid flags are reported when the command is executed
+	if err = c.ParseFlags(flags); err != nil {
+		return project, branch
+	}
+
+	if f := c.Flags().Lookup("project"); f != nil && f.Changed {
+		project = f.Value.String()
+	}
+
+	if f := c.Flags().Lookup("branch"); f != nil && f.Changed {
+		branch = f.Value.String()
+	}
+
+	return project, branch
+}
+
+// useTarget connects to the branch of the command, if it's different from the shell's branch.
+// Returns the function which switches back to the shell's branch.
+func (s *shellState) useTarget(project string, branch string) (func(), error) {
+	if project == s.project && branch == s.branch {
+		return func() {}, nil
+	}
+
+	// the transaction belongs to the shell's project and branch
+	if s.tx != nil {
+		return nil, ErrTxTarget
+	}
+
+	if branch == s.branch {
+		return func() {}, nil
+	}
+
+	if err := connect(project, branch); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if err := connect(s.project, s.branch); err != nil {
+			util.PrintError(err)
+		}
+	}, nil
+}
+
+// execute runs the CLI command in the shell's project and branch.
+// Failed commands call util.Exit, which is turned into the panic recovered here.
+func (s *shellState) execute(ctx context.Context, args []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(shellExit); !ok {
+				panic(r)
+			}
+		}
+	}()
+
+	project, branch := s.commandTarget(args)
+
+	resetFlags(rootCmd)
+
+	restore, err := s.useTarget(project, branch)
+	if err != nil {
+		util.PrintError(err)
+		return
+	}
+	defer restore()
+
+	config.DefaultConfig.Project = s.project
+	config.DefaultConfig.Branch = s.branch
+	config.Project = ""
+
+	rootCmd.SetArgs(args)
+
+	_ = rootCmd.ExecuteContext(ctx)
+}
+
+// resetFlags restores the default values of the flags set by the previous command.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			var def []string
+			if d := strings.Trim(f.DefValue, "[]"); d != "" {
+				def = strings.Split(d, ",")
+			}
+
+			_ = v.Replace(def)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+
+		f.Changed = false
+	}
+
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+func (s *shellState) run(ctx context.Context) error {
+	for {
+		stmt, err := s.readStatement()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		args, err := shell.Split(stmt)
+		if err != nil {
+			util.PrintError(err)
+			continue
+		}
+
+		if len(args) == 0 {
+			continue
+		}
+
+		switch args[0] {
+		case "exit", "quit":
+			return nil
+		case "shell":
+			util.Stderrf("already in the shell\n")
+			continue
+		}
+
+		ok, err := s.builtin(ctx, args)
+		if err != nil {
+			util.PrintError(err)
+		}
+
+		if !ok {
+			s.execute(client.WithTx(ctx, s.tx), args)
+		}
+	}
+}
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Starts interactive shell",
+	Long: `Starts interactive shell, which runs CLI commands over the single connection.
+
+Commands are typed without "tigris" prefix. JSON documents and filters can be typed
+unquoted and span multiple lines. Tab completes command, collection and field names.
+
+Shell commands:
+  use project {name}  switch the project
+  use branch {name}   switch the database branch
+  begin               start interactive transaction
+  commit              commit the transaction
+  rollback            rollback the transaction
+  exit, quit          leave the shell, same as Ctrl-D`,
+	Example: fmt.Sprintf(`
+  %[1]s shell --project=myproj
+  myproj> begin
+  myproj (tx)> insert users {"id": 1, "name": "Alice"}
+  myproj (tx)> update users {"id": 1} {
+  ...   "$set": {"name": "Bob"}
+  ... }
+  myproj (tx)> commit
+  myproj> read users --where "name = 'Bob'"
+`, rootCmd.Root().Name()),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s := &shellState{
+			project: config.GetProjectName(),
+			branch:  config.DefaultConfig.Branch,
+		}
+
+		if util.IsTTY(os.Stdin) {
+			s.term = term.NewTerminal(struct {
+				io.Reader
+				io.Writer
+			}{os.Stdin, os.Stdout}, "")
+			s.term.AutoCompleteCallback = s.complete
+		} else {
+			s.scanner = bufio.NewScanner(os.Stdin)
+		}
+
+		exit := util.Exit
+		util.Exit = func(code int) { panic(shellExit(code)) }
+
+		err := s.run(cmd.Context())
+
+		// errors below exit the process, not only the command
+		util.Exit = exit
+
+		if s.tx != nil {
+			util.Stderrf("rolling back the transaction\n")
+
+			ctx, cancel := util.GetContext(cmd.Context())
+			defer cancel()
+
+			_ = s.tx.Rollback(ctx)
+		}
+
+		util.Fatal(err, "shell input")
+	},
+}
+
+func init() {
+	addProjectFlag(shellCmd)
+	rootCmd.AddCommand(shellCmd)
+}
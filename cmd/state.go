@@ -0,0 +1,300 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/migrate"
+	"github.com/tigrisdata/tigris-cli/state"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+const userIndexSource = "user"
+
+var (
+	prune      bool
+	forceApply bool
+)
+
+// liveProject reads current state of the project resources.
+// Only user managed collections and search indexes are included.
+func liveProject(ctx context.Context) (*state.Project, error) {
+	p := state.NewProject()
+
+	resp, err := client.Get().DescribeDatabase(ctx, config.GetProjectName())
+	if err != nil {
+		return nil, util.Error(err, "describe project")
+	}
+
+	for _, v := range resp.Collections {
+		if !migrate.IsInternal(v.Collection) {
+			p.Collections[v.Collection] = v.Schema
+		}
+	}
+
+	for _, v := range resp.Branches {
+		p.Branches[v] = true
+	}
+
+	indexes, err := client.GetSearch().ListIndexes(ctx, &driver.IndexSource{Type: userIndexSource})
+	if err != nil {
+		return nil, util.Error(err, "list indexes")
+	}
+
+	for _, v := range indexes {
+		p.Indexes[v.Name] = v.Schema
+	}
+
+	keys, err := client.Get().ListAppKeys(ctx, config.GetProjectName())
+	if err != nil {
+		return nil, util.Error(err, "list app keys")
+	}
+
+	for _, v := range keys {
+		p.AppKeys[v.Name] = &state.AppKey{ID: v.Id, Name: v.Name, Description: v.Description}
+	}
+
+	return p, nil
+}
+
+func planProject(ctx context.Context, args []string) ([]*state.Change, error) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	desired, err := state.Load(dir)
+	if err != nil {
+		return nil, util.Error(err, "load project state")
+	}
+
+	live, err := liveProject(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := state.Plan(desired, live, prune, config.DefaultConfig.ClientID)
+	if err != nil {
+		return nil, util.Error(err, "plan")
+	}
+
+	return changes, nil
+}
+
+func printPlan(changes []*state.Change) {
+	if util.OutputFormat != "" {
+		err := writeOutputList(changes)
+		util.Fatal(err, "write plan")
+
+		return
+	}
+
+	if len(changes) == 0 {
+		util.Infof("No changes. Project is up to date")
+		return
+	}
+
+	counts := make(map[state.Action]int)
+
+	for _, c := range changes {
+		util.Stdoutf("%s\n", c)
+
+		for _, d := range c.Details {
+			util.Stdoutf("    [%s] %s\n", d.Severity, d)
+		}
+
+		counts[c.Action]++
+	}
+
+	util.Infof("Plan: %d to create, %d to update, %d to delete",
+		counts[state.Create], counts[state.Update], counts[state.Delete])
+}
+
+func applyBranchChange(ctx context.Context, c *state.Change) error {
+	if c.Action == state.Delete {
+		_, err := client.GetDB(ctx).DeleteBranch(ctx, c.Name)
+
+		return err
+	}
+
+	_, err := client.GetDB(ctx).CreateBranch(ctx, c.Name)
+
+	return err
+}
+
+func applyAppKeyChange(ctx context.Context, c *state.Change) error {
+	switch c.Action {
+	case state.Create:
+		app, err := client.Get().CreateAppKey(ctx, config.GetProjectName(), c.Name, c.AppKey.Description)
+		if err != nil {
+			return err
+		}
+
+		// The secret is only available at the time of creation
+		err = util.PrettyJSON(app)
+		util.Fatal(err, "create app_key")
+	case state.Update:
+		_, err := client.Get().UpdateAppKey(ctx, config.GetProjectName(), c.AppKey.ID, c.Name, c.AppKey.Description)
+		if err != nil {
+			return err
+		}
+	case state.Delete:
+		return client.Get().DeleteAppKey(ctx, config.GetProjectName(), c.AppKey.ID)
+	}
+
+	return nil
+}
+
+func applyChange(ctx context.Context, c *state.Change) error {
+	switch c.Kind {
+	case state.KindBranch:
+		return applyBranchChange(ctx, c)
+	case state.KindCollection:
+		if c.Action == state.Delete {
+			return client.GetDB(ctx).DropCollection(ctx, c.Name)
+		}
+
+		return client.GetDB(ctx).CreateOrUpdateCollection(ctx, c.Name, driver.Schema(c.Schema))
+	case state.KindIndex:
+		if c.Action == state.Delete {
+			return client.GetSearch().DeleteIndex(ctx, c.Name)
+		}
+
+		return client.GetSearch().CreateOrUpdateIndex(ctx, c.Name, driver.Schema(c.Schema))
+	case state.KindAppKey:
+		return applyAppKeyChange(ctx, c)
+	}
+
+	return nil
+}
+
+func confirmPrune(changes []*state.Change) bool {
+	if forceApply {
+		return true
+	}
+
+	for _, c := range changes {
+		if c.Action == state.Delete {
+			var userInput string
+
+			util.Stdoutf("Undeclared resources will be deleted. Are you sure? (y/n)")
+			_, err := fmt.Scanln(&userInput)
+			util.Fatal(err, "apply")
+
+			return userInput == "y" || userInput == "Y"
+		}
+	}
+
+	return true
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan [directory]",
+	Short: "Shows changes required to bring the project to the declared state",
+	Long: `Compares resources declared in the YAML or JSON files in the directory
+with the live project and shows the changes, which would be made by "apply".
+The following resources can be declared:
+  * collections - collection schemas, created in the current branch
+  * indexes - search index schemas
+  * branches - database branches
+  * app_keys - application keys, matched by name
+
+Resources which are not declared are deleted only when --prune is set.`,
+	Example: fmt.Sprintf(`
+  # ./tigris/project.yaml
+  collections:
+    - title: users
+      properties:
+        id: { type: integer }
+        name: { type: string }
+      primary_key: [ id ]
+  branches: [ staging ]
+  app_keys:
+    - name: ci
+      description: Key used by CI
+
+  %[1]s plan --project=myproj ./tigris
+`, rootCmd.Root().Name()),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			changes, err := planProject(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			printPlan(changes)
+
+			return nil
+		})
+	},
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply [directory]",
+	Short: "Brings the project to the declared state",
+	Long: `Applies changes shown by the "plan" command.
+Resources which are not declared are deleted only when --prune is set.`,
+	Example: fmt.Sprintf(`
+  # Apply and delete resources which are not declared without user prompt
+  %[1]s apply --project=myproj --prune --force ./tigris
+`, rootCmd.Root().Name()),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			changes, err := planProject(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			printPlan(changes)
+
+			if !confirmPrune(changes) {
+				return nil
+			}
+
+			for _, c := range changes {
+				if err = applyChange(ctx, c); err != nil {
+					return util.Error(err, "%s %s %s", c.Action, c.Kind, c.Name)
+				}
+			}
+
+			if len(changes) > 0 {
+				util.Infof("Applied %d changes", len(changes))
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	planCmd.Flags().BoolVar(&prune, "prune", false, "delete resources which are not declared")
+	applyCmd.Flags().BoolVar(&prune, "prune", false, "delete resources which are not declared")
+	applyCmd.Flags().BoolVarP(&forceApply, "force", "f", false, "skip user prompt before deleting resources")
+
+	addProjectFlag(planCmd)
+	addProjectFlag(applyCmd)
+
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(applyCmd)
+}
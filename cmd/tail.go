@@ -0,0 +1,212 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-cli/watch"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	cschema "github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var (
+	followField    string
+	followInterval time.Duration
+	tailLines      int64
+)
+
+func addFollowFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&followField, "follow-field", "",
+		"monotonically increasing field to poll by. "+
+			"updatedAt, createdAt or autogenerated integer primary key field is used if not set")
+	cmd.Flags().DurationVar(&followInterval, "interval", time.Second, "polling interval")
+}
+
+func newFollower(ctx context.Context, coll string) (*watch.Follower, error) {
+	resp, err := client.GetDB(ctx).DescribeCollection(ctx, coll)
+	if err != nil {
+		return nil, util.Error(err, "describe collection")
+	}
+
+	var sch cschema.Schema
+	if err = json.Unmarshal(resp.Schema, &sch); err != nil {
+		return nil, util.Error(err, "unmarshal schema")
+	}
+
+	field := followField
+	if field == "" {
+		if field, err = watch.DetectField(&sch); err != nil {
+			return nil, err
+		}
+	}
+
+	pk := sch.PrimaryKey
+	if len(pk) == 0 {
+		pk = []string{"id"}
+	}
+
+	return watch.New(field, pk), nil
+}
+
+// pollDocuments reads the documents after the last seen position of the follower
+// and writes the new and changed ones.
+// The last limit documents are read when the limit is set, in the reverse order.
+// The documents are only recorded by the follower if out is nil.
+func pollDocuments(ctx context.Context, f *watch.Follower, coll string, filter string, limit int64,
+	out *util.Output,
+) error {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	it, err := client.GetDB(ctx).Read(ctx, coll, driver.Filter(f.Filter(filter)), driver.Projection(`{}`),
+		&driver.ReadOptions{Limit: limit, Sort: f.Sort(limit > 0)})
+	if err != nil {
+		return util.Error(err, "read documents failed")
+	}
+	defer it.Close()
+
+	var (
+		doc  driver.Document
+		docs []json.RawMessage
+	)
+
+	for it.Next(&doc) {
+		if limit == 0 {
+			if err = followWrite(f, json.RawMessage(doc), out); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		docs = append(docs, json.RawMessage(doc))
+	}
+
+	if err = it.Err(); err != nil {
+		return util.Error(err, "read documents")
+	}
+
+	for i := len(docs) - 1; i >= 0; i-- {
+		if err = followWrite(f, docs[i], out); err != nil {
+			return err
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return util.Error(out.Flush(), "flush output")
+}
+
+func followWrite(f *watch.Follower, doc json.RawMessage, out *util.Output) error {
+	changed, err := f.Add(doc)
+	if err != nil || !changed || out == nil {
+		return err
+	}
+
+	return out.Write(doc)
+}
+
+// followCollection prints the documents matching the filter and then polls for new and changed documents.
+// All the documents are printed first if lines is negative, otherwise the last lines documents.
+func followCollection(ctx context.Context, coll string, filter string, lines int64) error {
+	dctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	f, err := newFollower(dctx, coll)
+	if err != nil {
+		return err
+	}
+
+	out, err := util.NewOutput(os.Stdout, util.OutputNDJSON)
+	util.Fatal(err, "output format")
+
+	switch {
+	case lines < 0:
+		err = pollDocuments(ctx, f, coll, filter, 0, out)
+	case lines == 0:
+		// only the position of the last document is needed
+		err = pollDocuments(ctx, f, coll, filter, 1, nil)
+	default:
+		err = pollDocuments(ctx, f, coll, filter, lines, out)
+	}
+
+	for err == nil {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(followInterval):
+		}
+
+		err = pollDocuments(ctx, f, coll, filter, 0, out)
+	}
+
+	return err
+}
+
+var tailCmd = &cobra.Command{
+	Use:   "tail {collection} [filter]",
+	Short: "Prints new and changed documents of the collection",
+	Long: `Prints the last documents of the collection and then polls for new and changed documents.
+
+The collection is polled by the monotonically increasing field, like the updatedAt field,
+which is detected from the schema or set by --follow-field. Changed documents are printed
+only if the change increases the field.`,
+	Example: fmt.Sprintf(`
+  # Print last 10 orders and follow new and changed ones
+  %[1]s tail --project=myproj orders
+
+  # Follow new failed orders every 5 seconds
+  %[1]s tail --project=myproj orders --where "status = 'failed'" --lines 0 --interval 5s
+
+  # Follow by the custom field
+  %[1]s tail --project=myproj events --follow-field ts
+`, rootCmd.Root().Name()),
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		args, explain := whereFilter(args, 1)
+		if explain {
+			return
+		}
+
+		login.Ensure(cmd.Context(), func(_ context.Context) error {
+			filter := `{}`
+			if len(args) > 1 {
+				filter = args[1]
+			}
+
+			// polls use own timeouts
+			return followCollection(cmd.Context(), args[0], filter, tailLines)
+		})
+	},
+}
+
+func init() {
+	tailCmd.Flags().Int64VarP(&tailLines, "lines", "n", 10, "number of the last documents to print first")
+	addFollowFlags(tailCmd)
+	addWhereFlags(tailCmd)
+	addProjectFlag(tailCmd)
+	rootCmd.AddCommand(tailCmd)
+}
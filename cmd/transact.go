@@ -26,6 +26,7 @@ import (
 	"github.com/tigrisdata/tigris-cli/iterate"
 	login "github.com/tigrisdata/tigris-cli/login"
 	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-cli/where"
 	"github.com/tigrisdata/tigris-client-go/driver"
 )
 
@@ -48,6 +49,8 @@ type Op struct {
 	Filter     json.RawMessage   `json:"filter"`
 	Fields     json.RawMessage   `json:"fields"`
 	Schema     json.RawMessage   `json:"schema"`
+	// Where is the filter expression, alternative to the Filter
+	Where string `json:"where"`
 }
 
 type TxOp struct {
@@ -137,17 +140,80 @@ func execTxOp(ctx context.Context, tx driver.Tx, tp string, op *Op) error {
 		return nil
 	}
 
+	if op.Where != "" {
+		f, err := where.Parse(op.Where)
+		if err != nil {
+			return util.Error(err, "transact operation where")
+		}
+
+		op.Filter = f
+	}
+
 	err := execTxOpLow(ctx, tx, tp, op)
 
 	return util.Error(err, "transact operation failed")
 }
 
+// execTxOps executes operations of the single transact command input document.
+func execTxOps(ctx context.Context, tx driver.Tx, iop json.RawMessage) error {
+	var op TxOp
+
+	err := json.Unmarshal(iop, &op)
+	util.Fatal(err, "begin transaction")
+
+	if op.Operation != "" {
+		if err = execTxOp(ctx, tx, op.Operation, &op.Op); err != nil {
+			return util.Error(err, "execute tx "+op.Operation)
+		}
+	}
+
+	if err = execTxOp(ctx, tx, InsertOrReplace, op.InsertOrReplace); err != nil {
+		return util.Error(err, "execute tx InsertOrReplace")
+	}
+
+	if err = execTxOp(ctx, tx, Replace, op.Replace); err != nil {
+		return util.Error(err, "execute tx Replace")
+	}
+
+	if err = execTxOp(ctx, tx, Insert, op.Insert); err != nil {
+		return util.Error(err, "execute tx Insert")
+	}
+
+	if err = execTxOp(ctx, tx, Read, op.Read); err != nil {
+		return util.Error(err, "execute tx Read")
+	}
+
+	if err = execTxOp(ctx, tx, Update, op.Update); err != nil {
+		return util.Error(err, "execute tx Update")
+	}
+
+	if err = execTxOp(ctx, tx, Delete, op.Delete); err != nil {
+		return util.Error(err, "execute tx Delete")
+	}
+
+	if err = execTxOp(ctx, tx, CreateOrUpdateCollection, op.CreateOrUpdateCollection); err != nil {
+		return util.Error(err, "execute tx CreateOrUpdateCollection")
+	}
+
+	if err = execTxOp(ctx, tx, DropCollection, op.DropCollection); err != nil {
+		return util.Error(err, "execute tx DropCollection")
+	}
+
+	if err = execTxOp(ctx, tx, ListCollections, op.ListCollections); err != nil {
+		return util.Error(err, "execute tx ListCollections")
+	}
+
+	return nil
+}
+
 var transactCmd = &cobra.Command{
 	Use:     "transact {operation}...|-",
 	Aliases: []string{"tx"},
 	Short:   "Executes a set of operations in a transaction",
 	Long: `Executes a set of operations in a transaction.
-All the read, write and schema operations are supported.`,
+All the read, write and schema operations are supported.
+Read, update and delete operations accept filter expression in the "where" field,
+like "id = 1 and status in ('new', 'paid')", instead of JSON "filter".`,
 	Example: fmt.Sprintf(`
   # Perform a transaction that inserts and updates in three collections
   %[1]s tigris transact myproj \
@@ -167,7 +233,7 @@ All the read, write and schema operations are supported.`,
     },
     {
       "update": {
-        "collection": "products", "fields": {"$set": {"quantity": 6357}}, "filter": {"id": 1}
+        "collection": "products", "fields": {"$set": {"quantity": 6357}}, "where": "id = 1"
       }
     }
   ]'
@@ -177,51 +243,8 @@ All the read, write and schema operations are supported.`,
 			return client.Transact(ctx, config.GetProjectName(), func(ctx context.Context, tx driver.Tx) error {
 				return iterate.Input(ctx, cmd, 1, args, func(ctx context.Context, args []string, ops []json.RawMessage) error {
 					for _, iop := range ops {
-						var op TxOp
-
-						err := json.Unmarshal(iop, &op)
-						util.Fatal(err, "begin transaction")
-
-						if op.Operation != "" {
-							if err = execTxOp(ctx, tx, op.Operation, &op.Op); err != nil {
-								return util.Error(err, "execute tx "+op.Operation)
-							}
-						}
-
-						if err = execTxOp(ctx, tx, InsertOrReplace, op.InsertOrReplace); err != nil {
-							return util.Error(err, "execute tx InsertOrReplace")
-						}
-
-						if err = execTxOp(ctx, tx, Replace, op.Replace); err != nil {
-							return util.Error(err, "execute tx Replace")
-						}
-
-						if err = execTxOp(ctx, tx, Insert, op.Insert); err != nil {
-							return util.Error(err, "execute tx Insert")
-						}
-
-						if err = execTxOp(ctx, tx, Read, op.Read); err != nil {
-							return util.Error(err, "execute tx Read")
-						}
-
-						if err = execTxOp(ctx, tx, Update, op.Update); err != nil {
-							return util.Error(err, "execute tx Update")
-						}
-
-						if err = execTxOp(ctx, tx, Delete, op.Delete); err != nil {
-							return util.Error(err, "execute tx Delete")
-						}
-
-						if err = execTxOp(ctx, tx, CreateOrUpdateCollection, op.CreateOrUpdateCollection); err != nil {
-							return util.Error(err, "execute tx CreateOrUpdateCollection")
-						}
-
-						if err = execTxOp(ctx, tx, DropCollection, op.DropCollection); err != nil {
-							return util.Error(err, "execute tx DropCollection")
-						}
-
-						if err = execTxOp(ctx, tx, ListCollections, op.ListCollections); err != nil {
-							return util.Error(err, "execute tx ListCollections")
+						if err := execTxOps(ctx, tx, iop); err != nil {
+							return err
 						}
 					}
 
@@ -32,11 +32,19 @@ var updateCmd = &cobra.Command{
 	Example: fmt.Sprintf(`
   # Update the field "name" of user where the value of the id field is 2
   %[1]s update --project=myproj users '{"id": 19}' '{"$set": {"name": "Updated New User"}}'
+
+  # Same update using filter expression
+  %[1]s update --project=myproj users --where "id = 19" '{"$set": {"name": "Updated New User"}}'
 `, rootCmd.Root().Name()),
-	Args: cobra.MinimumNArgs(3),
+	Args: whereArgs(3),
 	Run: func(cmd *cobra.Command, args []string) {
+		args, explain := whereFilter(args, 1)
+		if explain {
+			return
+		}
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			_, err := client.GetDB().
+			_, err := client.GetDB(ctx).
 				Update(ctx, args[0], driver.Filter(args[1]), driver.Update(args[2]))
 
 			return util.Error(err, "update documents failed")
@@ -45,6 +53,7 @@ var updateCmd = &cobra.Command{
 }
 
 func init() {
+	addWhereFlags(updateCmd)
 	addProjectFlag(updateCmd)
 	rootCmd.AddCommand(updateCmd)
 }
@@ -0,0 +1,84 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-cli/where"
+)
+
+var (
+	whereExpr     string
+	explainFilter bool
+)
+
+func addWhereFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&whereExpr, "where", "",
+		`filter expression, replaces JSON filter argument: --where "age > 23 and (city = 'SF' or vip = true)"`)
+	cmd.Flags().BoolVar(&explainFilter, "explain-filter", false,
+		"print the filter JSON generated from --where expression and exit")
+}
+
+// whereArgs requires at least n arguments, including JSON filter, which is omitted when --where is set.
+func whereArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if whereExpr != "" {
+			return cobra.MinimumNArgs(n-1)(cmd, args)
+		}
+
+		return cobra.MinimumNArgs(n)(cmd, args)
+	}
+}
+
+// parseWhere returns the filter JSON of the --where expression.
+func parseWhere() string {
+	f, err := where.Parse(whereExpr)
+	util.Fatal(err, "parse --where expression")
+
+	return string(f)
+}
+
+// explainWhere prints the filter when --explain-filter is set and returns true,
+// meaning that the command should exit without executing the request.
+func explainWhere(filter string) bool {
+	if explainFilter {
+		util.Stdoutf("%s\n", filter)
+	}
+
+	return explainFilter
+}
+
+// whereFilter inserts the filter generated from --where expression into the arguments
+// at the position of JSON filter argument. Returns true if the command should exit after --explain-filter.
+func whereFilter(args []string, pos int) ([]string, bool) {
+	if whereExpr == "" {
+		filter := "{}"
+		if len(args) > pos {
+			filter = args[pos]
+		}
+
+		return args, explainWhere(filter)
+	}
+
+	filter := parseWhere()
+
+	res := make([]string, 0, len(args)+1)
+	res = append(res, args[:pos]...)
+	res = append(res, filter)
+	res = append(res, args[pos:]...)
+
+	return res, explainWhere(filter)
+}
@@ -43,6 +43,18 @@ type Log struct {
 	Level string `json:"level" yaml:"level,omitempty"`
 }
 
+// Lint configures the rules of the schema linter.
+// Rules with zero values are not checked, except no-nested-arrays and bounded-primary-key,
+// which are enabled by default and can be turned off by adding them to the disabled list.
+type Lint struct {
+	Naming       string   `json:"naming"        yaml:"naming,omitempty"`       // snake_case or camelCase
+	Descriptions bool     `json:"descriptions"  yaml:"descriptions,omitempty"` // require descriptions
+	MaxDepth     int      `json:"max_depth"     mapstructure:"max_depth"     yaml:"max_depth,omitempty"`
+	FilterFields []string `json:"filter_fields" mapstructure:"filter_fields" yaml:"filter_fields,omitempty"`
+	SortFields   []string `json:"sort_fields"   mapstructure:"sort_fields"   yaml:"sort_fields,omitempty"`
+	Disabled     []string `json:"disabled"      yaml:"disabled,omitempty"` // names of the rules to skip
+}
+
 type Config struct {
 	ClientID     string `json:"client_id"     mapstructure:"client_id"     yaml:"client_id,omitempty"`
 	ClientSecret string `json:"client_secret" mapstructure:"client_secret" yaml:"client_secret,omitempty"`
@@ -54,6 +66,7 @@ type Config struct {
 	DataDir      string `json:"data_dir"      yaml:"data_dir,omitempty"`
 
 	Log          Log           `json:"log"            yaml:"log,omitempty"`
+	Lint         Lint          `json:"lint"           yaml:"lint,omitempty"`
 	Timeout      time.Duration `json:"timeout"        yaml:"timeout,omitempty"`
 	UseTLS       bool          `json:"use_tls"        mapstructure:"use_tls"        yaml:"use_tls,omitempty"`
 	SkipLocalTLS bool          `json:"skip_local_tls" mapstructure:"skip_local_tls" yaml:"skip_local_tls,omitempty"`
@@ -0,0 +1,520 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// avroPrimaryKey is the custom attribute of the Avro record with the primary key of the collection.
+const avroPrimaryKey = "primary_key"
+
+var ErrAvroTopLevel = fmt.Errorf("top level Avro types must be records")
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Doc     string          `json:"doc,omitempty"`
+	Type    any             `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type avroRecord struct {
+	Type       string       `json:"type"`
+	Name       string       `json:"name"`
+	Doc        string       `json:"doc,omitempty"`
+	Fields     []*avroField `json:"fields"`
+	PrimaryKey []string     `json:"primary_key,omitempty"`
+}
+
+type avroArray struct {
+	Type  string `json:"type"`
+	Items any    `json:"items"`
+}
+
+type avroLogical struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+}
+
+// avroConverter converts collections to Avro records and back.
+// Nullable unions are used for the fields which are not required.
+type avroConverter struct{}
+
+type avroExporter struct {
+	coll  string
+	notes *notes
+}
+
+func (e *avroExporter) fieldType(path string, f *schema.Field) any {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		if f.Format == tschema.FormatInt32 {
+			return "int"
+		}
+
+		return "long"
+	case tschema.TypeNumber:
+		return "double"
+	case tschema.TypeBoolean:
+		return "boolean"
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatUUID:
+			return &avroLogical{Type: "string", LogicalType: "uuid"}
+		case tschema.FormatDateTime:
+			return &avroLogical{Type: "long", LogicalType: "timestamp-millis"}
+		case tschema.FormatByte:
+			return "bytes"
+		case "":
+		default:
+			e.notes.add(e.coll, path, "format %s is not supported", f.Format)
+		}
+
+		return "string"
+	case tschema.TypeObject:
+		if len(f.Fields) == 0 {
+			e.notes.add(e.coll, path, "object without properties is represented as string")
+			return "string"
+		}
+
+		return e.record(typeName(path), path, f.Fields, nil, f.Required)
+	case tschema.TypeArray:
+		if f.Format == tschema.FormatVector {
+			e.notes.add(e.coll, path, "vector is represented as array of doubles")
+			return &avroArray{Type: "array", Items: "double"}
+		}
+
+		if f.Items == nil {
+			e.notes.add(e.coll, path, "array without items type is not supported")
+			return nil
+		}
+
+		items := e.fieldType(path+"[]", f.Items)
+		if items == nil {
+			return nil
+		}
+
+		return &avroArray{Type: "array", Items: items}
+	}
+
+	e.notes.add(e.coll, path, "type %s is not supported", f.Type.First())
+
+	return nil
+}
+
+func (e *avroExporter) field(path string, name string, f *schema.Field, required bool) (*avroField, error) {
+	typ := e.fieldType(path, f)
+	if typ == nil {
+		return nil, nil
+	}
+
+	e.notes.dropped(e.coll, path, f, "default", "description")
+
+	var def json.RawMessage
+
+	if isDefaultFunc(f.Default) {
+		e.notes.add(e.coll, path, "default function %v is not supported", f.Default)
+	} else if f.Default != nil && f.Format != tschema.FormatDateTime {
+		var err error
+		if def, err = json.Marshal(f.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	af := &avroField{Name: name, Doc: f.Desc}
+
+	switch {
+	case required:
+		af.Type, af.Default = typ, def
+	case def != nil:
+		// Default value must match the first type of the union
+		af.Type, af.Default = []any{typ, "null"}, def
+	default:
+		af.Type, af.Default = []any{"null", typ}, json.RawMessage("null")
+	}
+
+	return af, nil
+}
+
+func (e *avroExporter) record(name string, prefix string, fields map[string]*schema.Field, pk []string,
+	required []string,
+) *avroRecord {
+	rec := &avroRecord{Type: "record", Name: name, Fields: []*avroField{}}
+
+	for _, n := range tschema.SortedFields(fields, pk) {
+		path := tschema.JoinPath(prefix, n)
+
+		af, err := e.field(path, n, fields[n], tschema.IndexOf(pk, n) >= 0 || tschema.IndexOf(required, n) >= 0)
+		if err != nil {
+			e.notes.add(e.coll, path, "default value is not supported: %s", err)
+			continue
+		}
+
+		if af != nil {
+			rec.Fields = append(rec.Fields, af)
+		}
+	}
+
+	return rec
+}
+
+func (*avroConverter) Export(schemas []*schema.Schema) ([]byte, notes, error) {
+	var n notes
+
+	res := make([]*avroRecord, 0, len(schemas))
+
+	for _, sch := range schemas {
+		e := &avroExporter{coll: sch.Name, notes: &n}
+
+		rec := e.record(sch.Name, "", sch.Fields, sch.PrimaryKey, sch.Required)
+		rec.Doc = sch.Desc
+		rec.PrimaryKey = sch.PrimaryKey
+
+		res = append(res, rec)
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+
+	if len(res) == 1 {
+		b, err = json.MarshalIndent(res[0], "", "  ")
+	} else {
+		b, err = json.MarshalIndent(res, "", "  ")
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(b, '\n'), n, nil
+}
+
+type avroImporter struct {
+	coll     string
+	notes    notes
+	named    map[string]map[string]any // named types by short and full name
+	visiting map[string]bool
+}
+
+func (imp *avroImporter) lookup(name string) map[string]any {
+	if def, ok := imp.named[name]; ok {
+		return def
+	}
+
+	return imp.named[name[strings.LastIndex(name, ".")+1:]]
+}
+
+// register collects named types and the names of the types referenced from other types.
+func (imp *avroImporter) register(t any, referenced map[string]bool) {
+	switch v := t.(type) {
+	case string:
+		referenced[v[strings.LastIndex(v, ".")+1:]] = true
+	case []any:
+		for _, u := range v {
+			imp.register(u, referenced)
+		}
+	case map[string]any:
+		if name, ok := v["name"].(string); ok {
+			imp.named[name] = v
+
+			if ns, ok := v["namespace"].(string); ok {
+				imp.named[ns+"."+name] = v
+			}
+		}
+
+		if fields, ok := v["fields"].([]any); ok {
+			for _, f := range fields {
+				if fm, ok := f.(map[string]any); ok {
+					imp.register(fm["type"], referenced)
+				}
+			}
+		}
+
+		// String type is the kind of the complex type, like record or array
+		if _, ok := v["type"].(string); !ok {
+			imp.register(v["type"], referenced)
+		}
+
+		imp.register(v["items"], referenced)
+		imp.register(v["values"], referenced)
+	}
+}
+
+func (imp *avroImporter) namedType(path string, name string) *schema.Field {
+	switch name {
+	case "boolean":
+		return newField(tschema.TypeBoolean, "")
+	case "int":
+		return newField(tschema.TypeInteger, tschema.FormatInt32)
+	case "long":
+		return newField(tschema.TypeInteger, "")
+	case "float", "double":
+		return newField(tschema.TypeNumber, "")
+	case "bytes":
+		return newField(tschema.TypeString, tschema.FormatByte)
+	case "string":
+		return newField(tschema.TypeString, "")
+	case "null":
+		imp.notes.add(imp.coll, path, "null type is not supported")
+		return nil
+	}
+
+	def := imp.lookup(name)
+	if def == nil {
+		imp.notes.add(imp.coll, path, "unknown type %s", name)
+		return nil
+	}
+
+	return imp.complexType(path, def)
+}
+
+func (imp *avroImporter) logicalType(path string, typ string, logical string) *schema.Field {
+	switch logical {
+	case "uuid":
+		return newField(tschema.TypeString, tschema.FormatUUID)
+	case "timestamp-millis", "timestamp-micros", "local-timestamp-millis", "local-timestamp-micros":
+		return newField(tschema.TypeString, tschema.FormatDateTime)
+	case "date":
+		imp.notes.add(imp.coll, path, "date is converted to date-time")
+		return newField(tschema.TypeString, tschema.FormatDateTime)
+	case "decimal":
+		imp.notes.add(imp.coll, path, "decimal precision is not preserved")
+		return newField(tschema.TypeNumber, "")
+	}
+
+	imp.notes.add(imp.coll, path, "logical type %s is not supported", logical)
+
+	return imp.namedType(path, typ)
+}
+
+func (imp *avroImporter) complexType(path string, m map[string]any) *schema.Field {
+	typ, ok := m["type"].(string)
+	if !ok {
+		f, _ := imp.fieldType(path, m["type"])
+		return f
+	}
+
+	if logical, ok := m["logicalType"].(string); ok {
+		return imp.logicalType(path, typ, logical)
+	}
+
+	switch typ {
+	case "record":
+		name, _ := m["name"].(string)
+		if imp.visiting[name] {
+			imp.notes.add(imp.coll, path, "recursive type %s is not supported", name)
+			return nil
+		}
+
+		imp.visiting[name] = true
+		defer delete(imp.visiting, name)
+
+		fields, _ := m["fields"].([]any)
+
+		f := newField(tschema.TypeObject, "")
+		f.Fields, f.Required = imp.fields(path, fields)
+
+		return f
+	case "enum":
+		imp.notes.add(imp.coll, path, "enum symbols are not preserved")
+		return newField(tschema.TypeString, "")
+	case "fixed":
+		return newField(tschema.TypeString, tschema.FormatByte)
+	case "array":
+		items, _ := imp.fieldType(path+"[]", m["items"])
+		if items == nil {
+			return nil
+		}
+
+		f := newField(tschema.TypeArray, "")
+		f.Items = items
+
+		return f
+	case "map":
+		imp.notes.add(imp.coll, path, "map is represented as object without properties")
+		return newField(tschema.TypeObject, "")
+	}
+
+	return imp.namedType(path, typ)
+}
+
+// fieldType converts Avro type to the field. Returns true if the type is nullable union.
+func (imp *avroImporter) fieldType(path string, t any) (*schema.Field, bool) {
+	switch v := t.(type) {
+	case string:
+		return imp.namedType(path, v), false
+	case []any:
+		var (
+			types    []any
+			nullable bool
+		)
+
+		for _, u := range v {
+			if u == "null" {
+				nullable = true
+			} else {
+				types = append(types, u)
+			}
+		}
+
+		if len(types) != 1 {
+			imp.notes.add(imp.coll, path, "union types are not supported")
+			return nil, false
+		}
+
+		f, _ := imp.fieldType(path, types[0])
+
+		return f, nullable
+	case map[string]any:
+		return imp.complexType(path, v), false
+	}
+
+	imp.notes.add(imp.coll, path, "invalid type %v", t)
+
+	return nil, false
+}
+
+// fields converts record fields. Returns the fields and the list of not nullable fields.
+func (imp *avroImporter) fields(prefix string, list []any) (map[string]*schema.Field, []string) {
+	var required []string
+
+	res := make(map[string]*schema.Field)
+
+	for _, v := range list {
+		fm, _ := v.(map[string]any)
+		name, _ := fm["name"].(string)
+		path := tschema.JoinPath(prefix, name)
+
+		f, nullable := imp.fieldType(path, fm["type"])
+		if f == nil {
+			continue
+		}
+
+		f.Desc, _ = fm["doc"].(string)
+
+		if def := fm["default"]; def != nil {
+			if f.Format == tschema.FormatDateTime {
+				imp.notes.add(imp.coll, path, "default timestamp value is not supported")
+			} else {
+				f.Default = def
+			}
+		}
+
+		if !nullable {
+			required = append(required, name)
+		}
+
+		res[name] = f
+	}
+
+	return res, required
+}
+
+func (imp *avroImporter) collection(def map[string]any) *schema.Schema {
+	sch := &schema.Schema{}
+
+	sch.Name, _ = def["name"].(string)
+	sch.Desc, _ = def["doc"].(string)
+
+	imp.coll = sch.Name
+
+	pk, _ := def[avroPrimaryKey].([]any)
+	for _, v := range pk {
+		if s, ok := v.(string); ok {
+			sch.PrimaryKey = append(sch.PrimaryKey, s)
+		}
+	}
+
+	imp.visiting[sch.Name] = true
+
+	fields, _ := def["fields"].([]any)
+
+	var required []string
+
+	sch.Fields, required = imp.fields("", fields)
+
+	// Primary key fields are required implicitly
+	for _, v := range required {
+		if tschema.IndexOf(sch.PrimaryKey, v) < 0 {
+			sch.Required = append(sch.Required, v)
+		}
+	}
+
+	delete(imp.visiting, sch.Name)
+
+	return sch
+}
+
+// Import accepts a record, an array of records or a stream of records.
+// Records which have primary_key attribute, or, if there are no such records,
+// records which are not referenced by other records, become collections.
+func (*avroConverter) Import(data []byte) ([]*schema.Schema, notes, error) {
+	var defs []any
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	for dec.More() {
+		var v any
+
+		if err := dec.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+
+		if l, ok := v.([]any); ok {
+			defs = append(defs, l...)
+		} else {
+			defs = append(defs, v)
+		}
+	}
+
+	imp := &avroImporter{named: make(map[string]map[string]any), visiting: make(map[string]bool)}
+
+	var names []string
+
+	marked := make(map[string]bool)
+	referenced := make(map[string]bool)
+
+	for _, d := range defs {
+		m, ok := d.(map[string]any)
+		if !ok || m["type"] != "record" {
+			return nil, nil, ErrAvroTopLevel
+		}
+
+		name, _ := m["name"].(string)
+		names = append(names, name)
+
+		if _, ok := m[avroPrimaryKey]; ok {
+			marked[name] = true
+		}
+
+		imp.register(m, referenced)
+	}
+
+	res := make([]*schema.Schema, 0, len(names))
+
+	for _, name := range collectionNames(names, marked, referenced) {
+		res = append(res, imp.collection(imp.named[name]))
+	}
+
+	return res, imp.notes, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package convert converts Tigris collection schemas to and from
+// Avro, Protobuf, OpenAPI and SQL DDL.
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// Schema formats.
+const (
+	FormatTigris   = "tigris"
+	FormatAvro     = "avro"
+	FormatProtobuf = "protobuf"
+	FormatOpenAPI  = "openapi"
+	FormatSQL      = "sql"
+)
+
+// Default value functions supported by Tigris.
+const (
+	defaultNow  = "now()"
+	defaultUUID = "uuid()"
+)
+
+var (
+	ErrUnknownFormat = fmt.Errorf("unknown schema format. supported are: avro, openapi, protobuf, sql, tigris")
+	ErrNoSchemas     = fmt.Errorf("no collection schemas found in the input")
+)
+
+// Note describes the construct which cannot be represented in the target format and is
+// either approximated or dropped during the conversion.
+type Note struct {
+	Collection string `json:"collection"`
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (n *Note) String() string {
+	if n.Field == "" {
+		return n.Collection + ": " + n.Message
+	}
+
+	return n.Collection + "." + n.Field + ": " + n.Message
+}
+
+type notes []*Note
+
+func (n *notes) add(coll string, field string, format string, args ...any) {
+	*n = append(*n, &Note{Collection: coll, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// dropped reports Tigris specific attributes of the field, which are not in the supported list.
+func (n *notes) dropped(coll string, path string, f *schema.Field, supported ...string) {
+	set := map[string]bool{
+		"autoGenerate": f.AutoGenerate,
+		"createdAt":    f.CreatedAt,
+		"default":      f.Default != nil,
+		"description":  f.Desc != "",
+		"dimensions":   f.Dimensions > 0,
+		"facet":        f.Facet,
+		"index":        f.Index,
+		"maxItems":     f.MaxItems > 0,
+		"maxLength":    f.MaxLength > 0,
+		"searchIndex":  f.SearchIndex,
+		"sort":         f.Sort,
+		"updatedAt":    f.UpdatedAt,
+	}
+
+	for _, v := range supported {
+		delete(set, v)
+	}
+
+	var res []string
+
+	for k, v := range set {
+		if v {
+			res = append(res, k)
+		}
+	}
+
+	if len(res) > 0 {
+		sort.Strings(res)
+		n.add(coll, path, "attributes are not supported: %s", strings.Join(res, ", "))
+	}
+}
+
+type converter interface {
+	// Import parses collection schemas from the document in the format
+	Import(data []byte) ([]*schema.Schema, notes, error)
+	// Export renders collection schemas in the format
+	Export(schemas []*schema.Schema) ([]byte, notes, error)
+}
+
+var converters = map[string]converter{
+	FormatTigris:   &tigrisConverter{},
+	FormatAvro:     &avroConverter{},
+	FormatProtobuf: &protoConverter{},
+	"proto":        &protoConverter{},
+	FormatOpenAPI:  &openAPIConverter{},
+	FormatSQL:      &sqlConverter{},
+}
+
+func getConverter(format string) (converter, error) {
+	c, ok := converters[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+
+	return c, nil
+}
+
+// Import parses collection schemas from the document in the given format.
+func Import(format string, data []byte) ([]*schema.Schema, []*Note, error) {
+	c, err := getConverter(format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schemas, n, err := c.Import(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(schemas) == 0 {
+		return nil, nil, ErrNoSchemas
+	}
+
+	return schemas, n, nil
+}
+
+// Export renders collection schemas in the given format.
+func Export(format string, schemas []*schema.Schema) ([]byte, []*Note, error) {
+	c, err := getConverter(format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.Export(schemas)
+}
+
+// Convert converts schemas from one format to another.
+// Returns notes about the constructs which cannot be represented in either of the formats.
+func Convert(from string, to string, data []byte) ([]byte, []*Note, error) {
+	schemas, in, err := Import(from, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, out, err := Export(to, schemas)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, append(in, out...), nil
+}
+
+func newField(typ string, format string) *schema.Field {
+	return &schema.Field{Type: schema.NewMultiType(typ), Format: format}
+}
+
+// typeName returns the name of the generated type of the nested object.
+func typeName(path string) string {
+	return strcase.ToCamel(strings.NewReplacer(".", "_", "[]", "_item").Replace(path))
+}
+
+// isDefaultFunc returns true if the default value is the function evaluated by the server, like now().
+func isDefaultFunc(v any) bool {
+	s, ok := v.(string)
+
+	return ok && strings.HasSuffix(s, "()")
+}
+
+// collectionNames returns the names of the definitions which become collections.
+// If some of the definitions are explicitly marked as collections only those are returned,
+// otherwise the definitions which are not referenced by other definitions are returned.
+func collectionNames(names []string, marked map[string]bool, referenced map[string]bool) []string {
+	var res []string
+
+	for _, n := range names {
+		if (len(marked) > 0 && marked[n]) || (len(marked) == 0 && !referenced[n]) {
+			res = append(res, n)
+		}
+	}
+
+	return res
+}
+
+// tigrisConverter reads and writes native Tigris schemas.
+type tigrisConverter struct{}
+
+// Import accepts a schema, an array of schemas or a stream of schemas.
+func (*tigrisConverter) Import(data []byte) ([]*schema.Schema, notes, error) {
+	res, err := tschema.ReadSchemas(bytes.NewReader(data))
+
+	return res, nil, err
+}
+
+func (*tigrisConverter) Export(schemas []*schema.Schema) ([]byte, notes, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	if len(schemas) == 1 {
+		b, err = json.MarshalIndent(schemas[0], "", "  ")
+	} else {
+		b, err = json.MarshalIndent(schemas, "", "  ")
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(b, '\n'), nil, nil
+}
@@ -0,0 +1,335 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//nolint:funlen
+package convert
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = `{
+  "title": "users",
+  "properties": {
+    "id": { "type": "string", "format": "uuid", "autoGenerate": true },
+    "name": { "type": "string", "maxLength": 64, "description": "Full name" },
+    "age": { "type": "integer", "format": "int32" },
+    "created": { "type": "string", "format": "date-time" },
+    "photo": { "type": "string", "format": "byte" },
+    "address": { "type": "object", "properties": { "city": { "type": "string" } } },
+    "tags": { "type": "array", "items": { "type": "string" } }
+  },
+  "primary_key": ["id"]
+}`
+
+func TestExport(t *testing.T) {
+	cases := []struct {
+		format string
+		exp    string
+		notes  []string
+	}{
+		{
+			FormatProtobuf, `syntax = "proto3";
+
+import "google/protobuf/timestamp.proto";
+
+// tigris.collection: users
+// tigris.primary_key: id
+message User {
+  message Address {
+    string city = 1;
+  }
+  string id = 1;
+  Address address = 2;
+  int32 age = 3;
+  google.protobuf.Timestamp created = 4;
+  // Full name
+  string name = 5;
+  bytes photo = 6;
+  repeated string tags = 7;
+}
+`,
+			[]string{
+				"users.id: format uuid is represented as string",
+				"users.id: attributes are not supported: autoGenerate",
+				"users.name: attributes are not supported: maxLength",
+			},
+		},
+		{
+			FormatSQL, `CREATE TABLE users (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    address JSONB,
+    age INTEGER,
+    created TIMESTAMPTZ,
+    name VARCHAR(64),
+    photo BYTEA,
+    tags TEXT[],
+    PRIMARY KEY (id)
+);
+COMMENT ON COLUMN users.name IS 'Full name';
+`,
+			[]string{"users.address: nested object is stored as JSONB"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			out, notes, err := Convert(FormatTigris, c.format, []byte(testSchema))
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, string(out))
+
+			var actual []string
+			for _, n := range notes {
+				actual = append(actual, n.String())
+			}
+
+			assert.Equal(t, c.notes, actual)
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := []struct {
+		format string
+		exp    string
+	}{
+		{
+			FormatAvro, `{ "title": "users", "primary_key": ["id"], "properties": {
+				"id": { "type": "string", "format": "uuid" },
+				"name": { "type": "string", "description": "Full name" },
+				"age": { "type": "integer", "format": "int32" },
+				"created": { "type": "string", "format": "date-time" },
+				"photo": { "type": "string", "format": "byte" },
+				"address": { "type": "object", "properties": { "city": { "type": "string" } } },
+				"tags": { "type": "array", "items": { "type": "string" } }
+			} }`,
+		},
+		{
+			FormatOpenAPI, `{ "title": "users", "primary_key": ["id"], "properties": {
+				"id": { "type": "string", "format": "uuid" },
+				"name": { "type": "string", "maxLength": 64, "description": "Full name" },
+				"age": { "type": "integer", "format": "int32" },
+				"created": { "type": "string", "format": "date-time" },
+				"photo": { "type": "string", "format": "byte" },
+				"address": { "type": "object", "properties": { "city": { "type": "string" } } },
+				"tags": { "type": "array", "items": { "type": "string" } }
+			} }`,
+		},
+		{
+			FormatProtobuf, `{ "title": "users", "primary_key": ["id"], "properties": {
+				"id": { "type": "string" },
+				"name": { "type": "string", "description": "Full name" },
+				"age": { "type": "integer", "format": "int32" },
+				"created": { "type": "string", "format": "date-time" },
+				"photo": { "type": "string", "format": "byte" },
+				"address": { "type": "object", "properties": { "city": { "type": "string" } } },
+				"tags": { "type": "array", "items": { "type": "string" } }
+			} }`,
+		},
+		{
+			FormatSQL, `{ "title": "users", "primary_key": ["id"], "properties": {
+				"id": { "type": "string", "format": "uuid", "default": "uuid()" },
+				"name": { "type": "string", "maxLength": 64, "description": "Full name" },
+				"age": { "type": "integer", "format": "int32" },
+				"created": { "type": "string", "format": "date-time" },
+				"photo": { "type": "string", "format": "byte" },
+				"address": { "type": "object" },
+				"tags": { "type": "array", "items": { "type": "string" } }
+			} }`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			out, _, err := Convert(FormatTigris, c.format, []byte(testSchema))
+			require.NoError(t, err)
+
+			back, _, err := Convert(c.format, FormatTigris, out)
+			require.NoError(t, err)
+			assert.JSONEq(t, c.exp, string(back))
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	cases := []struct {
+		name   string
+		format string
+		input  string
+		exp    string
+		notes  []string
+	}{
+		{
+			"avro", FormatAvro, `{
+				"type": "record", "name": "orders", "namespace": "com.example",
+				"fields": [
+					{ "name": "id", "type": "long" },
+					{ "name": "status", "type": { "type": "enum", "name": "Status", "symbols": ["NEW", "DONE"] } },
+					{ "name": "amount", "type": { "type": "bytes", "logicalType": "decimal", "precision": 10 } },
+					{ "name": "item", "type": ["null", "Item"], "default": null },
+					{ "name": "value", "type": ["int", "string"] }
+				]
+			}
+			{ "type": "record", "name": "Item", "fields": [ { "name": "sku", "type": "string" } ] }`,
+			`[{ "title": "orders", "required": ["id", "status", "amount"], "properties": {
+				"id": { "type": "integer" },
+				"status": { "type": "string" },
+				"amount": { "type": "number" },
+				"item": { "type": "object", "required": ["sku"], "properties": { "sku": { "type": "string" } } }
+			} }]`,
+			[]string{
+				"orders.status: enum symbols are not preserved",
+				"orders.amount: decimal precision is not preserved",
+				"orders.value: union types are not supported",
+			},
+		},
+		{
+			"openapi_yaml", FormatOpenAPI, `
+openapi: 3.1.0
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        sku: { type: string, format: email }
+    orders:
+      type: object
+      x-primary-key: [id]
+      required: [total]
+      properties:
+        id: { type: integer, format: int64 }
+        total: { type: [number, "null"] }
+        item: { $ref: "#/components/schemas/Item" }
+        kind: { oneOf: [ { type: string }, { type: integer } ] }
+`,
+			`[{ "title": "orders", "primary_key": ["id"], "required": ["total"], "properties": {
+				"id": { "type": "integer" },
+				"total": { "type": "number" },
+				"item": { "type": "object", "properties": { "sku": { "type": "string" } } }
+			} }]`,
+			[]string{
+				"orders.item.sku: format email is not supported",
+				"orders.kind: oneOf, anyOf and allOf are not supported",
+			},
+		},
+		{
+			"protobuf", FormatProtobuf, `
+syntax = "proto3";
+package shop;
+
+import "google/protobuf/timestamp.proto";
+
+// Customer orders
+message Order {
+  enum Status { NEW = 0; DONE = 1; }
+  message Line { string sku = 1; uint64 qty = 2; }
+
+  int64 id = 1;
+  Status status = 2;
+  repeated Line lines = 3 [packed = true];
+  map<string, string> labels = 4;
+  oneof payment { string card = 5; string cash = 6; }
+  google.protobuf.Timestamp created = 7;
+}
+
+service Orders { rpc Get(Order) returns (Order); }
+`,
+			`[{ "title": "order", "description": "Customer orders", "properties": {
+				"id": { "type": "integer" },
+				"status": { "type": "string" },
+				"lines": { "type": "array", "items": { "type": "object", "properties": {
+					"sku": { "type": "string" }, "qty": { "type": "integer" }
+				} } },
+				"labels": { "type": "object" },
+				"created": { "type": "string", "format": "date-time" }
+			} }]`,
+			[]string{
+				"Order.payment: oneof is not supported",
+				"Orders: services are not supported",
+				"order.status: enum values are not preserved",
+				"order.lines.qty: unsigned 64-bit integer is represented as signed",
+				"order.labels: map is represented as object without properties",
+			},
+		},
+		{
+			"sql", FormatSQL, `
+-- Orders table
+CREATE TABLE IF NOT EXISTS public.orders (
+  id BIGINT GENERATED ALWAYS AS IDENTITY,
+  "Customer" character varying(32) NOT NULL REFERENCES customers (id),
+  total NUMERIC(10, 2) DEFAULT 0,
+  paid boolean NOT NULL DEFAULT false,
+  created timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+  tags text[],
+  CONSTRAINT orders_pk PRIMARY KEY (id),
+  UNIQUE ("Customer", created)
+);
+CREATE INDEX orders_created_idx ON orders USING btree (created DESC);
+COMMENT ON COLUMN orders.paid IS 'Whether the order is paid';
+`,
+			`[{ "title": "orders", "primary_key": ["id"], "required": ["Customer", "paid"], "properties": {
+				"id": { "type": "integer", "autoGenerate": true },
+				"Customer": { "type": "string", "maxLength": 32 },
+				"total": { "type": "number", "default": 0 },
+				"paid": { "type": "boolean", "default": false, "description": "Whether the order is paid" },
+				"created": { "type": "string", "format": "date-time", "default": "now()", "index": true },
+				"tags": { "type": "array", "items": { "type": "string" } }
+			} }]`,
+			[]string{
+				"orders.Customer: foreign key is not supported",
+				"orders.total: decimal precision is not preserved",
+				"orders: UNIQUE constraint is not supported",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			schemas, notes, err := Import(c.format, []byte(c.input))
+			require.NoError(t, err)
+
+			b, err := json.Marshal(schemas)
+			require.NoError(t, err)
+			assert.JSONEq(t, c.exp, string(b))
+
+			var actual []string
+			for _, n := range notes {
+				actual = append(actual, n.String())
+			}
+
+			assert.Equal(t, c.notes, actual)
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	_, _, err := Convert("xml", FormatTigris, []byte("{}"))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, _, err = Convert(FormatSQL, FormatTigris, []byte("DROP TABLE users;"))
+	require.ErrorIs(t, err, ErrNoSchemas)
+
+	_, _, err = Convert(FormatAvro, FormatTigris, []byte(`"string"`))
+	require.ErrorIs(t, err, ErrAvroTopLevel)
+
+	_, _, err = Convert(FormatProtobuf, FormatTigris, []byte(`message User { string name = 1;`))
+	require.ErrorIs(t, err, ErrSyntax)
+
+	_, _, err = Convert(FormatSQL, FormatTigris, []byte(`CREATE TABLE users (name 'text')`))
+	require.ErrorIs(t, err, ErrSyntax)
+}
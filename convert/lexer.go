@@ -0,0 +1,323 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+)
+
+var (
+	ErrSyntax       = fmt.Errorf("syntax error")
+	ErrUnterminated = fmt.Errorf("unterminated string or comment")
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenQuotedIdent
+	tokenPunct
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	line     int
+	comments []string // line comments preceding the token
+}
+
+// lexer splits Protobuf and SQL sources into tokens.
+// Identifiers include dots, so qualified names, like google.protobuf.Timestamp, are single tokens.
+type lexer struct {
+	src         []rune
+	pos         int
+	line        int
+	lineComment string
+	// quotedIdent is the quote character of the identifiers, strings are quoted by other quote characters
+	quotedIdent rune
+}
+
+func (l *lexer) hasPrefix(s string) bool {
+	for i, r := range []rune(s) {
+		if l.pos+i >= len(l.src) || l.src[l.pos+i] != r {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// skipSpace skips whitespace and comments, returns line comments.
+func (l *lexer) skipSpace() ([]string, error) {
+	var comments []string
+
+	for l.pos < len(l.src) {
+		switch r := l.src[l.pos]; {
+		case r == '\n':
+			l.line++
+			l.pos++
+		case unicode.IsSpace(r):
+			l.pos++
+		case l.hasPrefix(l.lineComment):
+			start := l.pos + len(l.lineComment)
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+
+			comments = append(comments, strings.TrimSpace(string(l.src[start:l.pos])))
+		case l.hasPrefix("/*"):
+			for l.pos += 2; !l.hasPrefix("*/"); l.pos++ {
+				if l.pos >= len(l.src) {
+					return nil, fmt.Errorf("%w: line %d", ErrUnterminated, l.line)
+				}
+
+				if l.src[l.pos] == '\n' {
+					l.line++
+				}
+			}
+
+			l.pos += 2
+		default:
+			return comments, nil
+		}
+	}
+
+	return comments, nil
+}
+
+func (l *lexer) quoted(q rune) (string, error) {
+	var b strings.Builder
+
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		r := l.src[l.pos]
+
+		switch {
+		case r == '\\' && q != l.quotedIdent && l.pos+1 < len(l.src):
+			l.pos++
+			b.WriteRune(l.src[l.pos])
+		case r == q && l.pos+1 < len(l.src) && l.src[l.pos+1] == q:
+			// SQL escapes quotes by doubling them
+			l.pos++
+			b.WriteRune(q)
+		case r == q:
+			l.pos++
+			return b.String(), nil
+		default:
+			if r == '\n' {
+				l.line++
+			}
+
+			b.WriteRune(r)
+		}
+	}
+
+	return "", fmt.Errorf("%w: line %d", ErrUnterminated, l.line)
+}
+
+func (l *lexer) next() (*token, error) {
+	comments, err := l.skipSpace()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &token{line: l.line, comments: comments}
+
+	if l.pos >= len(l.src) {
+		return t, nil
+	}
+
+	start := l.pos
+
+	switch r := l.src[l.pos]; {
+	case r == '\'' || r == '"' || r == '`':
+		t.kind = tokenString
+		if r == l.quotedIdent || r == '`' {
+			t.kind = tokenQuotedIdent
+		}
+
+		t.text, err = l.quoted(r)
+
+		return t, err
+	case unicode.IsDigit(r):
+		t.kind = tokenNumber
+	case isIdentRune(r):
+		t.kind = tokenIdent
+	default:
+		l.pos++
+		t.kind, t.text = tokenPunct, string(r)
+
+		return t, nil
+	}
+
+	for l.pos < len(l.src) && isIdentRune(l.src[l.pos]) {
+		l.pos++
+	}
+
+	t.text = string(l.src[start:l.pos])
+
+	return t, nil
+}
+
+func tokenize(src string, lineComment string, quotedIdent rune) ([]*token, error) {
+	l := &lexer{src: []rune(src), line: 1, lineComment: lineComment, quotedIdent: quotedIdent}
+
+	var res []*token
+
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, t)
+
+		if t.kind == tokenEOF {
+			return res, nil
+		}
+	}
+}
+
+// parser is the cursor over the tokens.
+type parser struct {
+	tokens []*token
+	pos    int
+	// fold enables case-insensitive keywords
+	fold bool
+}
+
+func (p *parser) peek() *token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() *token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) eof() bool {
+	return p.peek().kind == tokenEOF
+}
+
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	if t.kind != tokenIdent && t.kind != tokenPunct {
+		return false
+	}
+
+	if p.fold {
+		return strings.EqualFold(t.text, text)
+	}
+
+	return t.text == text
+}
+
+func (p *parser) accept(texts ...string) bool {
+	for i, text := range texts {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+
+		t := p.tokens[p.pos+i]
+		if (t.kind != tokenIdent && t.kind != tokenPunct) ||
+			(p.fold && !strings.EqualFold(t.text, text)) || (!p.fold && t.text != text) {
+			return false
+		}
+	}
+
+	p.pos += len(texts)
+
+	return true
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		t := p.peek()
+		return fmt.Errorf("%w: line %d: expected %q, got %q", ErrSyntax, t.line, text, t.text)
+	}
+
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.next()
+	if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+		return "", fmt.Errorf("%w: line %d: expected identifier, got %q", ErrSyntax, t.line, t.text)
+	}
+
+	return t.text, nil
+}
+
+// skip skips tokens up to and including the terminator at the top nesting level.
+func (p *parser) skip(terminator string) {
+	depth := 0
+
+	for !p.eof() {
+		t := p.next()
+
+		if depth == 0 && t.kind == tokenPunct && t.text == terminator {
+			return
+		}
+
+		if t.kind == tokenPunct {
+			switch t.text {
+			case "(", "{", "[":
+				depth++
+			case ")", "}", "]":
+				depth--
+
+				if depth == 0 && t.text == terminator {
+					return
+				}
+			}
+		}
+	}
+}
+
+// skipTo skips tokens up to, but not including, any of the terminators at the top nesting level.
+func (p *parser) skipTo(terminators ...string) {
+	depth := 0
+
+	for !p.eof() {
+		t := p.peek()
+
+		if t.kind == tokenPunct {
+			switch {
+			case depth == 0 && tschema.IndexOf(terminators, t.text) >= 0:
+				return
+			case t.text == "(" || t.text == "{" || t.text == "[":
+				depth++
+			case t.text == ")" || t.text == "}" || t.text == "]":
+				depth--
+			}
+		}
+
+		p.next()
+	}
+}
@@ -0,0 +1,485 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+	"gopkg.in/yaml.v2"
+)
+
+const openAPIVersion = "3.0.3"
+
+//nolint:tagliatelle
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"                 yaml:"$ref"`
+	Type                 any                       `json:"type,omitempty"                 yaml:"type"`
+	Format               string                    `json:"format,omitempty"               yaml:"format"`
+	Description          string                    `json:"description,omitempty"          yaml:"description"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"           yaml:"properties"`
+	Items                *openAPISchema            `json:"items,omitempty"                yaml:"items"`
+	Required             []string                  `json:"required,omitempty"             yaml:"required"`
+	MaxLength            int                       `json:"maxLength,omitempty"            yaml:"maxLength"`
+	MinItems             int                       `json:"minItems,omitempty"             yaml:"minItems"`
+	MaxItems             int                       `json:"maxItems,omitempty"             yaml:"maxItems"`
+	Default              any                       `json:"default,omitempty"              yaml:"default"`
+	Enum                 []any                     `json:"enum,omitempty"                 yaml:"enum"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"                yaml:"oneOf"`
+	AnyOf                []*openAPISchema          `json:"anyOf,omitempty"                yaml:"anyOf"`
+	AllOf                []*openAPISchema          `json:"allOf,omitempty"                yaml:"allOf"`
+	AdditionalProperties any                       `json:"additionalProperties,omitempty" yaml:"additionalProperties"`
+	PrimaryKey           []string                  `json:"x-primary-key,omitempty"        yaml:"x-primary-key"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"   yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas" yaml:"schemas"`
+}
+
+type openAPIDoc struct {
+	OpenAPI    string            `json:"openapi"    yaml:"openapi"`
+	Info       openAPIInfo       `json:"info"       yaml:"info"`
+	Paths      map[string]any    `json:"paths"      yaml:"paths"`
+	Components openAPIComponents `json:"components" yaml:"components"`
+}
+
+// openAPIConverter converts collections to the schemas of the OpenAPI components and back.
+// Primary key is stored in the x-primary-key extension.
+type openAPIConverter struct{}
+
+type openAPIExporter struct {
+	coll  string
+	notes *notes
+}
+
+func (e *openAPIExporter) object(prefix string, fields map[string]*schema.Field, required []string) *openAPISchema {
+	s := &openAPISchema{Type: tschema.TypeObject, Required: required}
+
+	if len(fields) == 0 {
+		s.AdditionalProperties = true
+		return s
+	}
+
+	s.Properties = make(map[string]*openAPISchema)
+
+	for _, name := range tschema.SortedFields(fields, nil) {
+		if p := e.schema(tschema.JoinPath(prefix, name), fields[name]); p != nil {
+			s.Properties[name] = p
+		}
+	}
+
+	return s
+}
+
+func (e *openAPIExporter) schema(path string, f *schema.Field) *openAPISchema {
+	var s *openAPISchema
+
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		s = &openAPISchema{Type: tschema.TypeInteger, Format: tschema.FormatInt64}
+		if f.Format == tschema.FormatInt32 {
+			s.Format = tschema.FormatInt32
+		}
+	case tschema.TypeNumber:
+		s = &openAPISchema{Type: tschema.TypeNumber, Format: "double"}
+	case tschema.TypeBoolean:
+		s = &openAPISchema{Type: tschema.TypeBoolean}
+	case tschema.TypeString:
+		s = &openAPISchema{Type: tschema.TypeString, Format: f.Format, MaxLength: f.MaxLength}
+	case tschema.TypeObject:
+		s = e.object(path, f.Fields, f.Required)
+	case tschema.TypeArray:
+		switch {
+		case f.Format == tschema.FormatVector:
+			s = &openAPISchema{
+				Type: tschema.TypeArray, Format: tschema.FormatVector, Items: &openAPISchema{Type: tschema.TypeNumber},
+				MinItems: f.Dimensions, MaxItems: f.Dimensions,
+			}
+		case f.Items == nil:
+			e.notes.add(e.coll, path, "array without items type is not supported")
+			return nil
+		default:
+			items := e.schema(path+"[]", f.Items)
+			if items == nil {
+				return nil
+			}
+
+			s = &openAPISchema{Type: tschema.TypeArray, Items: items, MaxItems: f.MaxItems}
+		}
+	default:
+		e.notes.add(e.coll, path, "type %s is not supported", f.Type.First())
+		return nil
+	}
+
+	e.notes.dropped(e.coll, path, f, "default", "description", "dimensions", "maxItems", "maxLength")
+
+	if isDefaultFunc(f.Default) {
+		e.notes.add(e.coll, path, "default function %v is not supported", f.Default)
+	} else {
+		s.Default = f.Default
+	}
+
+	s.Description = f.Desc
+
+	return s
+}
+
+func (*openAPIConverter) Export(schemas []*schema.Schema) ([]byte, notes, error) {
+	var n notes
+
+	doc := &openAPIDoc{
+		OpenAPI:    openAPIVersion,
+		Info:       openAPIInfo{Title: "Tigris collections", Version: "1.0.0"},
+		Paths:      map[string]any{},
+		Components: openAPIComponents{Schemas: make(map[string]*openAPISchema)},
+	}
+
+	for _, sch := range schemas {
+		e := &openAPIExporter{coll: sch.Name, notes: &n}
+
+		s := e.object("", sch.Fields, sch.Required)
+		s.Description = sch.Desc
+		s.PrimaryKey = sch.PrimaryKey
+
+		doc.Components.Schemas[sch.Name] = s
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(b, '\n'), n, nil
+}
+
+type openAPIImporter struct {
+	coll     string
+	notes    notes
+	schemas  map[string]*openAPISchema
+	visiting map[string]bool
+}
+
+func sortedNames(props map[string]*openAPISchema) []string {
+	names := make([]string, 0, len(props))
+	for k := range props {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// references collects the names of the components referenced from the schema.
+func references(s *openAPISchema, res map[string]bool) {
+	if s == nil {
+		return
+	}
+
+	if s.Ref != "" {
+		res[refName(s.Ref)] = true
+	}
+
+	for _, p := range s.Properties {
+		references(p, res)
+	}
+
+	references(s.Items, res)
+
+	for _, l := range [][]*openAPISchema{s.OneOf, s.AnyOf, s.AllOf} {
+		for _, v := range l {
+			references(v, res)
+		}
+	}
+}
+
+// schemaType returns the type of the schema, ignoring "null" in the OpenAPI 3.1 type lists.
+func schemaType(s *openAPISchema) (string, bool) {
+	switch t := s.Type.(type) {
+	case string:
+		return t, true
+	case []any:
+		var res []string
+
+		for _, v := range t {
+			// Unquoted null in YAML is decoded as nil
+			if v != "null" && v != nil {
+				res = append(res, toString(v))
+			}
+		}
+
+		if len(res) == 1 {
+			return res[0], true
+		}
+
+		return "", false
+	case nil:
+		if len(s.Properties) > 0 {
+			return tschema.TypeObject, true
+		}
+	}
+
+	return "", false
+}
+
+func toString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// isScalar returns true if the value can be used as field default.
+func isScalar(v any) bool {
+	switch v.(type) {
+	case string, bool, int, int64, float64:
+		return true
+	}
+
+	return false
+}
+
+func (imp *openAPIImporter) ref(path string, ref string) *schema.Field {
+	name := refName(ref)
+
+	s, ok := imp.schemas[name]
+	if !ok {
+		imp.notes.add(imp.coll, path, "unresolved reference %s", ref)
+		return nil
+	}
+
+	if imp.visiting[name] {
+		imp.notes.add(imp.coll, path, "recursive reference %s is not supported", ref)
+		return nil
+	}
+
+	imp.visiting[name] = true
+	defer delete(imp.visiting, name)
+
+	return imp.field(path, s)
+}
+
+func (imp *openAPIImporter) stringField(path string, s *openAPISchema) *schema.Field {
+	f := newField(tschema.TypeString, "")
+	f.MaxLength = s.MaxLength
+
+	switch s.Format {
+	case "":
+	case tschema.FormatUUID, tschema.FormatDateTime, tschema.FormatByte:
+		f.Format = s.Format
+	case "binary":
+		f.Format = tschema.FormatByte
+	case "date":
+		imp.notes.add(imp.coll, path, "date is converted to date-time")
+		f.Format = tschema.FormatDateTime
+	default:
+		imp.notes.add(imp.coll, path, "format %s is not supported", s.Format)
+	}
+
+	return f
+}
+
+func (imp *openAPIImporter) arrayField(path string, s *openAPISchema) *schema.Field {
+	f := newField(tschema.TypeArray, "")
+
+	if s.Format == tschema.FormatVector {
+		f.Format, f.Dimensions, f.Items = tschema.FormatVector, s.MaxItems, newField(tschema.TypeNumber, "")
+		return f
+	}
+
+	if s.Items == nil {
+		imp.notes.add(imp.coll, path, "array without items type is not supported")
+		return nil
+	}
+
+	if f.Items = imp.field(path+"[]", s.Items); f.Items == nil {
+		return nil
+	}
+
+	f.MaxItems = s.MaxItems
+
+	return f
+}
+
+func (imp *openAPIImporter) objectField(path string, s *openAPISchema) *schema.Field {
+	f := newField(tschema.TypeObject, "")
+
+	if _, ok := s.AdditionalProperties.(bool); !ok && s.AdditionalProperties != nil {
+		imp.notes.add(imp.coll, path, "additionalProperties schema is not supported")
+	}
+
+	if len(s.Properties) > 0 {
+		f.Fields = imp.fields(path, s.Properties)
+	}
+
+	for _, v := range s.Required {
+		if _, ok := f.Fields[v]; ok {
+			f.Required = append(f.Required, v)
+		}
+	}
+
+	return f
+}
+
+func (imp *openAPIImporter) field(path string, s *openAPISchema) *schema.Field {
+	if s.Ref != "" {
+		return imp.ref(path, s.Ref)
+	}
+
+	if len(s.AllOf) == 1 && len(s.OneOf) == 0 && len(s.AnyOf) == 0 {
+		return imp.field(path, s.AllOf[0])
+	}
+
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 || len(s.AllOf) > 0 {
+		imp.notes.add(imp.coll, path, "oneOf, anyOf and allOf are not supported")
+		return nil
+	}
+
+	if len(s.Enum) > 0 {
+		imp.notes.add(imp.coll, path, "enum values are not preserved")
+	}
+
+	typ, ok := schemaType(s)
+	if !ok {
+		imp.notes.add(imp.coll, path, "type %v is not supported", s.Type)
+		return nil
+	}
+
+	var f *schema.Field
+
+	switch typ {
+	case tschema.TypeInteger:
+		f = newField(tschema.TypeInteger, "")
+		if s.Format == tschema.FormatInt32 {
+			f.Format = tschema.FormatInt32
+		}
+	case tschema.TypeNumber:
+		f = newField(tschema.TypeNumber, "")
+	case tschema.TypeBoolean:
+		f = newField(tschema.TypeBoolean, "")
+	case tschema.TypeString:
+		f = imp.stringField(path, s)
+	case tschema.TypeArray:
+		f = imp.arrayField(path, s)
+	case tschema.TypeObject:
+		f = imp.objectField(path, s)
+	default:
+		imp.notes.add(imp.coll, path, "type %s is not supported", typ)
+	}
+
+	if f == nil {
+		return nil
+	}
+
+	f.Desc = s.Description
+
+	switch {
+	case s.Default == nil:
+	case isScalar(s.Default):
+		f.Default = s.Default
+	default:
+		imp.notes.add(imp.coll, path, "non scalar default value is not supported")
+	}
+
+	return f
+}
+
+func (imp *openAPIImporter) fields(prefix string, props map[string]*openAPISchema) map[string]*schema.Field {
+	res := make(map[string]*schema.Field)
+
+	for _, name := range sortedNames(props) {
+		if f := imp.field(tschema.JoinPath(prefix, name), props[name]); f != nil {
+			res[name] = f
+		}
+	}
+
+	return res
+}
+
+func (imp *openAPIImporter) collection(name string) *schema.Schema {
+	s := imp.schemas[name]
+
+	imp.coll = name
+	imp.visiting[name] = true
+
+	f := imp.objectField("", s)
+
+	delete(imp.visiting, name)
+
+	return &schema.Schema{
+		Name:       name,
+		Desc:       s.Description,
+		Fields:     f.Fields,
+		PrimaryKey: s.PrimaryKey,
+		Required:   f.Required,
+	}
+}
+
+// Import accepts OpenAPI document in JSON or YAML format.
+// Object schemas of the components which have x-primary-key extension, or, if there are no such schemas,
+// which are not referenced by other schemas, become collections.
+func (*openAPIConverter) Import(data []byte) ([]*schema.Schema, notes, error) {
+	var doc openAPIDoc
+
+	if t := bytes.TrimSpace(data); len(t) > 0 && t[0] == '{' {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, nil, err
+		}
+	} else if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	imp := &openAPIImporter{schemas: doc.Components.Schemas, visiting: make(map[string]bool)}
+
+	var names []string
+
+	marked := make(map[string]bool)
+	referenced := make(map[string]bool)
+
+	for name, s := range doc.Components.Schemas {
+		references(s, referenced)
+
+		if typ, _ := schemaType(s); typ != tschema.TypeObject {
+			continue
+		}
+
+		names = append(names, name)
+
+		if len(s.PrimaryKey) > 0 {
+			marked[name] = true
+		}
+	}
+
+	sort.Strings(names)
+
+	res := make([]*schema.Schema, 0, len(names))
+
+	for _, name := range collectionNames(names, marked, referenced) {
+		res = append(res, imp.collection(name))
+	}
+
+	return res, imp.notes, nil
+}
@@ -0,0 +1,579 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gertd/go-pluralize"
+	"github.com/iancoleman/strcase"
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// Comments of the message, which mark it as a collection and set the primary key.
+const (
+	protoCollection = "tigris.collection:"
+	protoPrimaryKey = "tigris.primary_key:"
+
+	protoTimestamp = "google.protobuf.Timestamp"
+	protoStruct    = "google.protobuf.Struct"
+)
+
+var (
+	plural = pluralize.NewClient()
+
+	protoImports = map[string]string{
+		protoTimestamp: "google/protobuf/timestamp.proto",
+		protoStruct:    "google/protobuf/struct.proto",
+	}
+
+	// protoWrappers maps well known wrapper types to scalar types.
+	protoWrappers = map[string]string{
+		"google.protobuf.DoubleValue": "double",
+		"google.protobuf.FloatValue":  "float",
+		"google.protobuf.Int64Value":  "int64",
+		"google.protobuf.UInt64Value": "uint64",
+		"google.protobuf.Int32Value":  "int32",
+		"google.protobuf.UInt32Value": "uint32",
+		"google.protobuf.BoolValue":   "bool",
+		"google.protobuf.StringValue": "string",
+		"google.protobuf.BytesValue":  "bytes",
+	}
+)
+
+// protoConverter converts collections to Protobuf messages and back.
+// Collection name and primary key are stored in the comments of the message:
+//
+//	// tigris.collection: users
+//	// tigris.primary_key: id
+//	message User { ... }
+type protoConverter struct{}
+
+type protoExporter struct {
+	coll    string
+	notes   *notes
+	imports map[string]bool
+}
+
+func writeComments(b *strings.Builder, indent string, lines ...string) {
+	for _, l := range lines {
+		for _, s := range strings.Split(l, "\n") {
+			fmt.Fprintf(b, "%s// %s\n", indent, s)
+		}
+	}
+}
+
+// fieldType returns the type of the message field and whether the field is repeated.
+// Messages of the nested objects are written to b.
+func (e *protoExporter) fieldType(b *strings.Builder, indent string, path string, name string, f *schema.Field,
+) (string, bool) {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		if f.Format == tschema.FormatInt32 {
+			return "int32", false
+		}
+
+		return "int64", false
+	case tschema.TypeNumber:
+		return "double", false
+	case tschema.TypeBoolean:
+		return "bool", false
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatDateTime:
+			e.imports[protoTimestamp] = true
+			return protoTimestamp, false
+		case tschema.FormatByte:
+			return "bytes", false
+		case "":
+		default:
+			e.notes.add(e.coll, path, "format %s is represented as string", f.Format)
+		}
+
+		return "string", false
+	case tschema.TypeObject:
+		if len(f.Fields) == 0 {
+			e.imports[protoStruct] = true
+			return protoStruct, false
+		}
+
+		msg := strcase.ToCamel(name)
+		e.message(b, indent, msg, path, f.Fields, nil)
+
+		return msg, false
+	case tschema.TypeArray:
+		switch {
+		case f.Format == tschema.FormatVector:
+			e.notes.add(e.coll, path, "vector is represented as repeated double")
+			return "double", true
+		case f.Items == nil:
+			e.notes.add(e.coll, path, "array without items type is not supported")
+			return "", false
+		case f.Items.Type.First() == tschema.TypeArray:
+			e.notes.add(e.coll, path, "nested arrays are not supported")
+			return "", false
+		}
+
+		typ, _ := e.fieldType(b, indent, path+"[]", plural.Singular(name), f.Items)
+
+		return typ, true
+	}
+
+	e.notes.add(e.coll, path, "type %s is not supported", f.Type.First())
+
+	return "", false
+}
+
+func (e *protoExporter) message(b *strings.Builder, indent string, name string, prefix string,
+	fields map[string]*schema.Field, pk []string,
+) {
+	fmt.Fprintf(b, "%smessage %s {\n", indent, name)
+
+	var body strings.Builder
+
+	num := 1
+
+	for _, n := range tschema.SortedFields(fields, pk) {
+		f, path := fields[n], tschema.JoinPath(prefix, n)
+
+		typ, repeated := e.fieldType(b, indent+"  ", path, n, f)
+		if typ == "" {
+			continue
+		}
+
+		e.notes.dropped(e.coll, path, f, "description", "dimensions")
+
+		if f.Desc != "" {
+			writeComments(&body, indent+"  ", f.Desc)
+		}
+
+		if repeated {
+			typ = "repeated " + typ
+		}
+
+		fmt.Fprintf(&body, "%s  %s %s = %d;\n", indent, typ, n, num)
+		num++
+	}
+
+	b.WriteString(body.String())
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+func (*protoConverter) Export(schemas []*schema.Schema) ([]byte, notes, error) {
+	var (
+		n notes
+		b strings.Builder
+	)
+
+	imports := make(map[string]bool)
+
+	for _, sch := range schemas {
+		e := &protoExporter{coll: sch.Name, notes: &n, imports: imports}
+
+		b.WriteString("\n")
+
+		if sch.Desc != "" {
+			writeComments(&b, "", sch.Desc)
+		}
+
+		writeComments(&b, "", protoCollection+" "+sch.Name)
+
+		if len(sch.PrimaryKey) > 0 {
+			writeComments(&b, "", protoPrimaryKey+" "+strings.Join(sch.PrimaryKey, ", "))
+		}
+
+		e.message(&b, "", strcase.ToCamel(plural.Singular(sch.Name)), "", sch.Fields, sch.PrimaryKey)
+	}
+
+	var h strings.Builder
+
+	h.WriteString("syntax = \"proto3\";\n")
+
+	if len(imports) > 0 {
+		h.WriteString("\n")
+
+		names := make([]string, 0, len(imports))
+		for k := range imports {
+			names = append(names, protoImports[k])
+		}
+
+		sort.Strings(names)
+
+		for _, v := range names {
+			fmt.Fprintf(&h, "import %q;\n", v)
+		}
+	}
+
+	return []byte(h.String() + b.String()), n, nil
+}
+
+type protoField struct {
+	name     string
+	typ      string
+	repeated bool
+	isMap    bool
+	comments []string
+}
+
+type protoMessage struct {
+	name     string
+	full     string // name qualified by the names of the enclosing messages
+	enum     bool
+	comments []string
+	fields   []*protoField
+}
+
+type protoImporter struct {
+	parser
+
+	coll     string
+	pkg      string
+	notes    notes
+	defs     map[string]*protoMessage // messages and enums by qualified name
+	messages []*protoMessage          // top level messages
+	visiting map[string]bool
+}
+
+func (imp *protoImporter) field(msg *protoMessage, t *token) error {
+	f := &protoField{comments: t.comments}
+
+	switch t.text {
+	case "repeated":
+		f.repeated = true
+		t = imp.next()
+	case "optional", "required":
+		t = imp.next()
+	}
+
+	if t.text == "map" && imp.accept("<") {
+		imp.skip(">")
+
+		f.isMap = true
+	} else {
+		f.typ = t.text
+	}
+
+	var err error
+	if f.name, err = imp.ident(); err != nil {
+		return err
+	}
+
+	msg.fields = append(msg.fields, f)
+
+	// Skip field number and options
+	imp.skip(";")
+
+	return nil
+}
+
+func (imp *protoImporter) message(scope string, comments []string) error {
+	name, err := imp.ident()
+	if err != nil {
+		return err
+	}
+
+	msg := &protoMessage{name: name, full: tschema.JoinPath(scope, name), comments: comments}
+	imp.defs[msg.full] = msg
+
+	if scope == "" {
+		imp.messages = append(imp.messages, msg)
+	}
+
+	if err = imp.expect("{"); err != nil {
+		return err
+	}
+
+	for !imp.accept("}") {
+		if imp.eof() {
+			return fmt.Errorf("%w: unexpected end of message %s", ErrSyntax, name)
+		}
+
+		t := imp.next()
+
+		switch t.text {
+		case "message":
+			err = imp.message(msg.full, t.comments)
+		case "enum":
+			err = imp.enum(msg.full)
+		case "oneof":
+			imp.notes.add(name, imp.peek().text, "oneof is not supported")
+			imp.skip("}")
+		case "option", "reserved", "extensions":
+			imp.skip(";")
+		case "extend":
+			imp.skip("}")
+		case ";":
+		default:
+			err = imp.field(msg, t)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (imp *protoImporter) enum(scope string) error {
+	name, err := imp.ident()
+	if err != nil {
+		return err
+	}
+
+	imp.defs[tschema.JoinPath(scope, name)] = &protoMessage{name: name, full: tschema.JoinPath(scope, name), enum: true}
+	imp.skip("}")
+
+	return nil
+}
+
+func (imp *protoImporter) parse() error {
+	for !imp.eof() {
+		var err error
+
+		switch t := imp.next(); t.text {
+		case "message":
+			err = imp.message("", t.comments)
+		case "enum":
+			err = imp.enum("")
+		case "package":
+			imp.pkg, err = imp.ident()
+			imp.skip(";")
+		case "service":
+			imp.notes.add(imp.peek().text, "", "services are not supported")
+			imp.skip("}")
+		case "extend":
+			imp.skip("}")
+		case ";":
+		default:
+			// syntax, import and option statements
+			imp.skip(";")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookup resolves type name in the scope of the message, following Protobuf scoping rules.
+func (imp *protoImporter) lookup(scope string, name string) *protoMessage {
+	if strings.HasPrefix(name, ".") {
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "."), imp.pkg+".")
+		return imp.defs[name]
+	}
+
+	for {
+		if d, ok := imp.defs[tschema.JoinPath(scope, name)]; ok {
+			return d
+		}
+
+		if scope == "" {
+			break
+		}
+
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+
+	if imp.pkg != "" {
+		return imp.defs[strings.TrimPrefix(name, imp.pkg+".")]
+	}
+
+	return nil
+}
+
+func (imp *protoImporter) scalarType(path string, typ string) *schema.Field {
+	switch typ {
+	case "double", "float":
+		return newField(tschema.TypeNumber, "")
+	case "int32", "sint32", "sfixed32":
+		return newField(tschema.TypeInteger, tschema.FormatInt32)
+	case "uint32", "fixed32", "int64", "sint64", "sfixed64":
+		return newField(tschema.TypeInteger, "")
+	case "uint64", "fixed64":
+		imp.notes.add(imp.coll, path, "unsigned 64-bit integer is represented as signed")
+		return newField(tschema.TypeInteger, "")
+	case "bool":
+		return newField(tschema.TypeBoolean, "")
+	case "string":
+		return newField(tschema.TypeString, "")
+	case "bytes":
+		return newField(tschema.TypeString, tschema.FormatByte)
+	case protoTimestamp, "." + protoTimestamp:
+		return newField(tschema.TypeString, tschema.FormatDateTime)
+	case protoStruct, "." + protoStruct:
+		return newField(tschema.TypeObject, "")
+	}
+
+	if w, ok := protoWrappers[strings.TrimPrefix(typ, ".")]; ok {
+		return imp.scalarType(path, w)
+	}
+
+	return nil
+}
+
+func (imp *protoImporter) fieldType(path string, scope string, f *protoField) *schema.Field {
+	if f.isMap {
+		imp.notes.add(imp.coll, path, "map is represented as object without properties")
+		return newField(tschema.TypeObject, "")
+	}
+
+	if s := imp.scalarType(path, f.typ); s != nil {
+		return s
+	}
+
+	d := imp.lookup(scope, f.typ)
+
+	switch {
+	case d == nil:
+		imp.notes.add(imp.coll, path, "type %s is not supported", f.typ)
+		return nil
+	case d.enum:
+		imp.notes.add(imp.coll, path, "enum values are not preserved")
+		return newField(tschema.TypeString, "")
+	case imp.visiting[d.full]:
+		imp.notes.add(imp.coll, path, "recursive message %s is not supported", d.full)
+		return nil
+	}
+
+	obj := newField(tschema.TypeObject, "")
+	obj.Fields = imp.fields(path, d)
+
+	return obj
+}
+
+// description returns the comments which are not annotations.
+func description(comments []string) string {
+	var res []string
+
+	for _, c := range comments {
+		if !strings.HasPrefix(c, protoCollection) && !strings.HasPrefix(c, protoPrimaryKey) {
+			res = append(res, c)
+		}
+	}
+
+	return strings.Join(res, "\n")
+}
+
+// annotation returns the value of the annotation comment.
+func annotation(comments []string, prefix string) (string, bool) {
+	for _, c := range comments {
+		if strings.HasPrefix(c, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(c, prefix)), true
+		}
+	}
+
+	return "", false
+}
+
+func (imp *protoImporter) fields(prefix string, msg *protoMessage) map[string]*schema.Field {
+	imp.visiting[msg.full] = true
+	defer delete(imp.visiting, msg.full)
+
+	res := make(map[string]*schema.Field)
+
+	for _, pf := range msg.fields {
+		path := tschema.JoinPath(prefix, pf.name)
+
+		f := imp.fieldType(path, msg.full, pf)
+		if f == nil {
+			continue
+		}
+
+		if pf.repeated {
+			f = &schema.Field{Type: schema.NewMultiType(tschema.TypeArray), Items: f}
+		}
+
+		f.Desc = description(pf.comments)
+		res[pf.name] = f
+	}
+
+	return res
+}
+
+func (imp *protoImporter) collection(msg *protoMessage) *schema.Schema {
+	name, ok := annotation(msg.comments, protoCollection)
+	if !ok {
+		name = strcase.ToSnake(msg.name)
+	}
+
+	imp.coll = name
+
+	sch := &schema.Schema{Name: name, Desc: description(msg.comments), Fields: imp.fields("", msg)}
+
+	if pk, ok := annotation(msg.comments, protoPrimaryKey); ok {
+		for _, v := range strings.Split(pk, ",") {
+			sch.PrimaryKey = append(sch.PrimaryKey, strings.TrimSpace(v))
+		}
+	}
+
+	return sch
+}
+
+// Import parses .proto file. Top level messages which are marked with tigris.collection comment, or,
+// if there are no such messages, which are not used as field types of other messages, become collections.
+func (*protoConverter) Import(data []byte) ([]*schema.Schema, notes, error) {
+	tokens, err := tokenize(string(data), "//", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imp := &protoImporter{
+		parser:   parser{tokens: tokens},
+		defs:     make(map[string]*protoMessage),
+		visiting: make(map[string]bool),
+	}
+
+	if err = imp.parse(); err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(imp.messages))
+	marked := make(map[string]bool)
+	referenced := make(map[string]bool)
+
+	for _, m := range imp.messages {
+		names = append(names, m.full)
+
+		if _, ok := annotation(m.comments, protoCollection); ok {
+			marked[m.full] = true
+		}
+	}
+
+	for _, d := range imp.defs {
+		for _, f := range d.fields {
+			if r := imp.lookup(d.full, f.typ); r != nil {
+				referenced[r.full] = true
+			}
+		}
+	}
+
+	res := make([]*schema.Schema, 0, len(names))
+
+	for _, name := range collectionNames(names, marked, referenced) {
+		res = append(res, imp.collection(imp.defs[name]))
+	}
+
+	return res, imp.notes, nil
+}
@@ -0,0 +1,797 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var sqlPlainIdent = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// sqlConverter converts collections to PostgreSQL tables and back.
+// Nested objects and arrays of objects are stored in JSONB columns,
+// vectors are stored in the pgvector VECTOR columns.
+type sqlConverter struct{}
+
+func sqlIdent(s string) string {
+	if sqlPlainIdent.MatchString(s) {
+		return s
+	}
+
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+type sqlExporter struct {
+	coll  string
+	notes *notes
+}
+
+func (e *sqlExporter) columnType(path string, f *schema.Field) string {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		switch {
+		case f.AutoGenerate && f.Format == tschema.FormatInt32:
+			return "SERIAL"
+		case f.AutoGenerate:
+			return "BIGSERIAL"
+		case f.Format == tschema.FormatInt32:
+			return "INTEGER"
+		}
+
+		return "BIGINT"
+	case tschema.TypeNumber:
+		return "DOUBLE PRECISION"
+	case tschema.TypeBoolean:
+		return "BOOLEAN"
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatUUID:
+			return "UUID"
+		case tschema.FormatDateTime:
+			return "TIMESTAMPTZ"
+		case tschema.FormatByte:
+			return "BYTEA"
+		case "":
+		default:
+			e.notes.add(e.coll, path, "format %s is represented as text", f.Format)
+		}
+
+		if f.MaxLength > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", f.MaxLength)
+		}
+
+		return "TEXT"
+	case tschema.TypeObject:
+		e.notes.add(e.coll, path, "nested object is stored as JSONB")
+		return "JSONB"
+	case tschema.TypeArray:
+		switch {
+		case f.Format == tschema.FormatVector:
+			return fmt.Sprintf("VECTOR(%d)", f.Dimensions)
+		case f.Items == nil || f.Items.Type.First() == tschema.TypeObject || f.Items.Type.First() == tschema.TypeArray:
+			e.notes.add(e.coll, path, "array of objects or arrays is stored as JSONB")
+			return "JSONB"
+		}
+
+		return e.columnType(path+"[]", f.Items) + "[]"
+	}
+
+	e.notes.add(e.coll, path, "type %s is not supported", f.Type.First())
+
+	return ""
+}
+
+// columnDefault returns the DEFAULT clause of the column.
+func (e *sqlExporter) columnDefault(path string, f *schema.Field) string {
+	switch {
+	case f.AutoGenerate && f.Format == tschema.FormatUUID, f.Default == defaultUUID:
+		return " DEFAULT gen_random_uuid()"
+	case f.AutoGenerate && f.Format == tschema.FormatDateTime, f.CreatedAt, f.Default == defaultNow:
+		return " DEFAULT now()"
+	case f.AutoGenerate && f.Type.First() != tschema.TypeInteger:
+		e.notes.add(e.coll, path, "autoGenerate is not supported for the type")
+	case isDefaultFunc(f.Default):
+		e.notes.add(e.coll, path, "default function %v is not supported", f.Default)
+	case f.Default != nil:
+		switch v := f.Default.(type) {
+		case string:
+			return " DEFAULT " + sqlString(v)
+		case bool, int, int64, float64:
+			return fmt.Sprintf(" DEFAULT %v", v)
+		}
+
+		e.notes.add(e.coll, path, "default value %v is not supported", f.Default)
+	}
+
+	if f.UpdatedAt {
+		e.notes.add(e.coll, path, "updatedAt requires a trigger")
+	}
+
+	return ""
+}
+
+func (e *sqlExporter) table(b *strings.Builder, sch *schema.Schema) {
+	table := sqlIdent(sch.Name)
+
+	var (
+		cols []string
+		post []string
+	)
+
+	for _, n := range tschema.SortedFields(sch.Fields, sch.PrimaryKey) {
+		f := sch.Fields[n]
+
+		typ := e.columnType(n, f)
+		if typ == "" {
+			continue
+		}
+
+		e.notes.dropped(e.coll, n, f, "autoGenerate", "createdAt", "default", "description", "dimensions",
+			"index", "maxLength", "updatedAt")
+
+		col := sqlIdent(n) + " " + typ
+
+		if tschema.IndexOf(sch.PrimaryKey, n) >= 0 || tschema.IndexOf(sch.Required, n) >= 0 {
+			col += " NOT NULL"
+		}
+
+		cols = append(cols, col+e.columnDefault(n, f))
+
+		if f.Index {
+			post = append(post, fmt.Sprintf("CREATE INDEX %s ON %s (%s);",
+				sqlIdent(sch.Name+"_"+n+"_idx"), table, sqlIdent(n)))
+		}
+
+		if f.Desc != "" {
+			post = append(post, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", table, sqlIdent(n), sqlString(f.Desc)))
+		}
+	}
+
+	if len(sch.PrimaryKey) > 0 {
+		pk := make([]string, 0, len(sch.PrimaryKey))
+		for _, v := range sch.PrimaryKey {
+			pk = append(pk, sqlIdent(v))
+		}
+
+		cols = append(cols, "PRIMARY KEY ("+strings.Join(pk, ", ")+")")
+	}
+
+	fmt.Fprintf(b, "CREATE TABLE %s (\n    %s\n);\n", table, strings.Join(cols, ",\n    "))
+
+	if sch.Desc != "" {
+		fmt.Fprintf(b, "COMMENT ON TABLE %s IS %s;\n", table, sqlString(sch.Desc))
+	}
+
+	for _, v := range post {
+		b.WriteString(v + "\n")
+	}
+}
+
+func (*sqlConverter) Export(schemas []*schema.Schema) ([]byte, notes, error) {
+	var (
+		n notes
+		b strings.Builder
+	)
+
+	for i, sch := range schemas {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		e := &sqlExporter{coll: sch.Name, notes: &n}
+		e.table(&b, sch)
+	}
+
+	return []byte(b.String()), n, nil
+}
+
+type sqlImporter struct {
+	parser
+
+	coll   string
+	notes  notes
+	tables map[string]*schema.Schema
+	order  []string
+}
+
+// qualifiedName parses optionally schema qualified name and returns its last part.
+func (imp *sqlImporter) qualifiedName() (string, error) {
+	name, err := imp.ident()
+	if err != nil {
+		return "", err
+	}
+
+	for imp.accept(".") {
+		if name, err = imp.ident(); err != nil {
+			return "", err
+		}
+	}
+
+	return name[strings.LastIndex(name, ".")+1:], nil
+}
+
+// identList parses parenthesized list of column names.
+func (imp *sqlImporter) identList() ([]string, error) {
+	if err := imp.expect("("); err != nil {
+		return nil, err
+	}
+
+	var res []string
+
+	for {
+		name, err := imp.ident()
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, name)
+
+		// Sort order and operator classes of the index columns
+		imp.skipTo(",", ")")
+
+		if imp.accept(")") {
+			return res, nil
+		}
+
+		if err = imp.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// typeArgs parses optional parenthesized numeric arguments of the type, like VARCHAR(64).
+func (imp *sqlImporter) typeArgs() []int {
+	var res []int
+
+	if !imp.accept("(") {
+		return nil
+	}
+
+	for !imp.eof() && !imp.accept(")") {
+		if t := imp.next(); t.kind == tokenNumber {
+			v, _ := strconv.Atoi(t.text)
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// typeName parses possibly multi-word type name.
+func (imp *sqlImporter) typeName() (string, error) {
+	name, err := imp.ident()
+	if err != nil {
+		return "", err
+	}
+
+	name = strings.ToLower(name[strings.LastIndex(name, ".")+1:])
+
+	switch name {
+	case "double":
+		imp.accept("PRECISION")
+	case "character", "char":
+		if imp.accept("VARYING") {
+			name = "varchar"
+		}
+	case "timestamp", "time":
+		if imp.accept("WITH", "TIME", "ZONE") {
+			name += "tz"
+		}
+
+		imp.accept("WITHOUT", "TIME", "ZONE")
+	}
+
+	return name, nil
+}
+
+func (imp *sqlImporter) scalarType(path string, name string, args []int) *schema.Field {
+	switch name {
+	case "smallint", "int2", "integer", "int", "int4", "mediumint", "tinyint":
+		return newField(tschema.TypeInteger, tschema.FormatInt32)
+	case "bigint", "int8":
+		return newField(tschema.TypeInteger, "")
+	case "serial", "smallserial", "serial4", "serial2":
+		f := newField(tschema.TypeInteger, tschema.FormatInt32)
+		f.AutoGenerate = true
+
+		return f
+	case "bigserial", "serial8":
+		f := newField(tschema.TypeInteger, "")
+		f.AutoGenerate = true
+
+		return f
+	case "real", "float", "float4", "float8", "double":
+		return newField(tschema.TypeNumber, "")
+	case "numeric", "decimal":
+		imp.notes.add(imp.coll, path, "decimal precision is not preserved")
+		return newField(tschema.TypeNumber, "")
+	case "boolean", "bool":
+		return newField(tschema.TypeBoolean, "")
+	case "varchar", "char", "character", "nvarchar", "nchar":
+		f := newField(tschema.TypeString, "")
+		if len(args) > 0 {
+			f.MaxLength = args[0]
+		}
+
+		return f
+	case "text", "citext", "clob", "string":
+		return newField(tschema.TypeString, "")
+	case "uuid":
+		return newField(tschema.TypeString, tschema.FormatUUID)
+	case "timestamp", "timestamptz", "datetime":
+		return newField(tschema.TypeString, tschema.FormatDateTime)
+	case "date":
+		imp.notes.add(imp.coll, path, "date is converted to date-time")
+		return newField(tschema.TypeString, tschema.FormatDateTime)
+	case "time", "timetz", "interval":
+		imp.notes.add(imp.coll, path, "%s is represented as string", name)
+		return newField(tschema.TypeString, "")
+	case "bytea", "blob", "binary", "varbinary":
+		return newField(tschema.TypeString, tschema.FormatByte)
+	case "json", "jsonb":
+		return newField(tschema.TypeObject, "")
+	case "vector":
+		f := newField(tschema.TypeArray, tschema.FormatVector)
+		f.Items = newField(tschema.TypeNumber, "")
+
+		if len(args) > 0 {
+			f.Dimensions = args[0]
+		}
+
+		return f
+	}
+
+	imp.notes.add(imp.coll, path, "type %s is not supported", name)
+
+	return nil
+}
+
+func (imp *sqlImporter) columnType(path string) (*schema.Field, error) {
+	name, err := imp.typeName()
+	if err != nil {
+		return nil, err
+	}
+
+	args := imp.typeArgs()
+
+	// MySQL integer modifiers
+	imp.accept("UNSIGNED")
+
+	f := imp.scalarType(path, name, args)
+
+	for {
+		switch {
+		case imp.accept("["):
+			imp.skip("]")
+		case imp.accept("ARRAY"):
+			imp.typeArgs()
+		default:
+			return f, nil
+		}
+
+		if f != nil {
+			f = &schema.Field{Type: schema.NewMultiType(tschema.TypeArray), Items: f}
+		}
+	}
+}
+
+// defaultValue parses the DEFAULT expression of the column.
+func (imp *sqlImporter) defaultValue(path string) any {
+	var res any
+
+	t := imp.next()
+
+	switch t.kind {
+	case tokenString:
+		res = t.text
+	case tokenNumber:
+		if v, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			res = v
+		} else if v, err := strconv.ParseFloat(t.text, 64); err == nil {
+			res = v
+		}
+	case tokenPunct:
+		if t.text == "-" && imp.peek().kind == tokenNumber {
+			if v, err := strconv.ParseFloat(imp.next().text, 64); err == nil {
+				res = -v
+			}
+		}
+	case tokenIdent:
+		fn := strings.ToLower(t.text)
+		if imp.accept("(") {
+			imp.skip(")")
+		}
+
+		switch fn {
+		case "true", "false":
+			res = fn == "true"
+		case "null":
+		case "now", "current_timestamp", "localtimestamp":
+			res = defaultNow
+		case "gen_random_uuid", "uuid_generate_v4", "uuid":
+			res = defaultUUID
+		default:
+			imp.notes.add(imp.coll, path, "default expression %s is not supported", t.text)
+		}
+	}
+
+	// Type casts, like 'a'::text
+	for imp.accept(":", ":") {
+		_, _ = imp.typeName()
+		imp.typeArgs()
+	}
+
+	return res
+}
+
+// columnConstraint parses one column constraint. Returns true if the column is NOT NULL.
+func (imp *sqlImporter) columnConstraint(sch *schema.Schema, name string, f *schema.Field) bool {
+	switch {
+	case imp.accept("CONSTRAINT"):
+		_, _ = imp.ident()
+	case imp.accept("NOT", "NULL"):
+		return true
+	case imp.accept("NULL"):
+	case imp.accept("PRIMARY", "KEY"):
+		sch.PrimaryKey = append(sch.PrimaryKey, name)
+	case imp.accept("DEFAULT"):
+		f.Default = imp.defaultValue(name)
+	case imp.accept("AUTO_INCREMENT"), imp.accept("AUTOINCREMENT"):
+		f.AutoGenerate = true
+	case imp.accept("GENERATED"):
+		if imp.accept("ALWAYS", "AS", "IDENTITY") || imp.accept("BY", "DEFAULT", "AS", "IDENTITY") {
+			f.AutoGenerate = true
+			imp.typeArgs()
+		} else {
+			imp.notes.add(imp.coll, name, "generated columns are not supported")
+			imp.skipTo(",", ")")
+		}
+	case imp.accept("COLLATE"):
+		_, _ = imp.ident()
+	case imp.accept("UNIQUE"):
+		imp.notes.add(imp.coll, name, "unique constraint is not supported")
+	case imp.accept("CHECK"):
+		imp.notes.add(imp.coll, name, "check constraint is not supported")
+
+		if imp.accept("(") {
+			imp.skip(")")
+		}
+	case imp.accept("REFERENCES"):
+		imp.notes.add(imp.coll, name, "foreign key is not supported")
+		imp.skipTo(",", ")")
+	default:
+		imp.notes.add(imp.coll, name, "column option %s is not supported", imp.next().text)
+	}
+
+	return false
+}
+
+func (imp *sqlImporter) column(sch *schema.Schema) error {
+	name, err := imp.ident()
+	if err != nil {
+		return err
+	}
+
+	f, err := imp.columnType(name)
+	if err != nil {
+		return err
+	}
+
+	if f == nil {
+		f = &schema.Field{}
+	}
+
+	notNull := false
+
+	for !imp.eof() && !imp.is(",") && !imp.is(")") {
+		notNull = imp.columnConstraint(sch, name, f) || notNull
+	}
+
+	if f.Type.First() == "" {
+		return nil
+	}
+
+	if f.Default == defaultNow && f.Format != tschema.FormatDateTime {
+		f.Default = nil
+	}
+
+	if notNull {
+		sch.Required = append(sch.Required, name)
+	}
+
+	sch.Fields[name] = f
+
+	return nil
+}
+
+// tableConstraint parses table level constraint, returns false if the element is not a constraint.
+func (imp *sqlImporter) tableConstraint(sch *schema.Schema) (bool, error) {
+	if imp.accept("CONSTRAINT") {
+		if _, err := imp.ident(); err != nil {
+			return false, err
+		}
+	}
+
+	switch {
+	case imp.accept("PRIMARY", "KEY"):
+		pk, err := imp.identList()
+		if err != nil {
+			return false, err
+		}
+
+		sch.PrimaryKey = pk
+	case (imp.is("INDEX") || imp.is("KEY")) && imp.indexDefinition():
+		imp.next()
+
+		if !imp.is("(") {
+			_, _ = imp.ident()
+		}
+
+		cols, err := imp.identList()
+		if err != nil {
+			return false, err
+		}
+
+		imp.setIndex(sch, cols)
+	case imp.is("UNIQUE") || imp.is("FOREIGN") || imp.is("CHECK") || imp.is("EXCLUDE"):
+		imp.notes.add(imp.coll, "", "%s constraint is not supported", strings.ToUpper(imp.peek().text))
+		imp.skipTo(",", ")")
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// indexDefinition distinguishes MySQL index definition, like KEY name (column), from the column named "key".
+func (imp *sqlImporter) indexDefinition() bool {
+	for i := 1; i <= 2 && imp.pos+i < len(imp.tokens); i++ {
+		if t := imp.tokens[imp.pos+i]; t.kind == tokenPunct && t.text == "(" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (imp *sqlImporter) table() error {
+	imp.accept("IF", "NOT", "EXISTS")
+
+	name, err := imp.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	sch := &schema.Schema{Name: name, Fields: make(map[string]*schema.Field)}
+
+	imp.coll = name
+	imp.tables[name] = sch
+	imp.order = append(imp.order, name)
+
+	if err = imp.expect("("); err != nil {
+		return err
+	}
+
+	for {
+		ok, err := imp.tableConstraint(sch)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			if err = imp.column(sch); err != nil {
+				return err
+			}
+		}
+
+		if imp.accept(")") {
+			break
+		}
+
+		if err = imp.expect(","); err != nil {
+			return err
+		}
+	}
+
+	// Primary key columns are required implicitly
+	var required []string
+
+	for _, v := range sch.Required {
+		if tschema.IndexOf(sch.PrimaryKey, v) < 0 {
+			required = append(required, v)
+		}
+	}
+
+	sch.Required = required
+
+	// Table options
+	imp.skip(";")
+
+	return nil
+}
+
+func (imp *sqlImporter) setIndex(sch *schema.Schema, cols []string) {
+	for _, c := range cols {
+		if f, ok := sch.Fields[c]; ok {
+			f.Index = true
+		} else {
+			imp.notes.add(sch.Name, c, "index of unknown column")
+		}
+	}
+}
+
+func (imp *sqlImporter) index(unique bool) error {
+	imp.accept("CONCURRENTLY")
+	imp.accept("IF", "NOT", "EXISTS")
+
+	if !imp.is("ON") {
+		if _, err := imp.ident(); err != nil {
+			return err
+		}
+	}
+
+	if err := imp.expect("ON"); err != nil {
+		return err
+	}
+
+	imp.accept("ONLY")
+
+	table, err := imp.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	if imp.accept("USING") {
+		_, _ = imp.ident()
+	}
+
+	sch, ok := imp.tables[table]
+	if !ok {
+		imp.notes.add(table, "", "index of unknown table")
+		imp.skip(";")
+
+		return nil
+	}
+
+	if unique {
+		imp.notes.add(table, "", "unique index is converted to regular index")
+	}
+
+	cols, err := imp.identList()
+	if err != nil {
+		imp.notes.add(table, "", "expression indexes are not supported")
+		imp.skip(";")
+
+		return nil //nolint:nilerr
+	}
+
+	imp.setIndex(sch, cols)
+	imp.skip(";")
+
+	return nil
+}
+
+func (imp *sqlImporter) comment() error {
+	column := imp.accept("COLUMN")
+	if !column && !imp.accept("TABLE") {
+		imp.skip(";")
+		return nil
+	}
+
+	name, err := imp.ident()
+	if err != nil {
+		return err
+	}
+
+	for imp.accept(".") {
+		n, err := imp.ident()
+		if err != nil {
+			return err
+		}
+
+		name += "." + n
+	}
+
+	if err = imp.expect("IS"); err != nil {
+		return err
+	}
+
+	desc := imp.next().text
+	imp.skip(";")
+
+	parts := strings.Split(name, ".")
+
+	if !column {
+		if sch, ok := imp.tables[parts[len(parts)-1]]; ok {
+			sch.Desc = desc
+		}
+
+		return nil
+	}
+
+	if len(parts) < 2 {
+		return nil
+	}
+
+	if sch, ok := imp.tables[parts[len(parts)-2]]; ok {
+		if f, ok := sch.Fields[parts[len(parts)-1]]; ok {
+			f.Desc = desc
+		}
+	}
+
+	return nil
+}
+
+func (imp *sqlImporter) statement() error {
+	switch {
+	case imp.accept(";"):
+	case imp.accept("CREATE"):
+		imp.accept("OR", "REPLACE")
+
+		_ = imp.accept("TEMPORARY") || imp.accept("TEMP") || imp.accept("UNLOGGED")
+
+		switch {
+		case imp.accept("TABLE"):
+			return imp.table()
+		case imp.accept("UNIQUE", "INDEX"):
+			return imp.index(true)
+		case imp.accept("INDEX"):
+			return imp.index(false)
+		}
+
+		imp.notes.add("", "", "CREATE %s statement is not supported", strings.ToUpper(imp.peek().text))
+		imp.skip(";")
+	case imp.accept("COMMENT", "ON"):
+		return imp.comment()
+	default:
+		imp.notes.add("", "", "%s statement is not supported", strings.ToUpper(imp.peek().text))
+		imp.skip(";")
+	}
+
+	return nil
+}
+
+// Import parses CREATE TABLE, CREATE INDEX and COMMENT ON statements. Every table becomes a collection.
+func (*sqlConverter) Import(data []byte) ([]*schema.Schema, notes, error) {
+	tokens, err := tokenize(string(data), "--", '"')
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imp := &sqlImporter{parser: parser{tokens: tokens, fold: true}, tables: make(map[string]*schema.Schema)}
+
+	for !imp.eof() {
+		if err = imp.statement(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	res := make([]*schema.Schema, 0, len(imp.order))
+	for _, name := range imp.order {
+		res = append(res, imp.tables[name])
+	}
+
+	return res, imp.notes, nil
+}
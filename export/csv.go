@@ -0,0 +1,114 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/tigrisdata/tigris-cli/iterate"
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// csvWriter writes the documents with nested objects flattened to the dotted column names,
+// in the form recognized by CSV import.
+type csvWriter struct {
+	w       *csv.Writer
+	fields  []*field
+	columns []string
+}
+
+func newCSVWriter(w io.Writer, fields []*field) (*csvWriter, error) {
+	c := &csvWriter{w: csv.NewWriter(w), fields: fields, columns: columns(fields, "")}
+
+	if iterate.CSVDelimiter != rune(0) {
+		c.w.Comma = iterate.CSVDelimiter
+	}
+
+	if err := c.w.Write(c.columns); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// columns returns dotted names of the leaf fields in the schema order.
+func columns(fields []*field, prefix string) []string {
+	var res []string
+
+	for _, f := range fields {
+		name := f.name
+		if prefix != "" {
+			name = prefix + "." + f.name
+		}
+
+		if f.typ == tschema.TypeObject && len(f.fields) > 0 {
+			res = append(res, columns(f.fields, name)...)
+		} else {
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
+
+// flatten collects the values of the columns from the document.
+// Unlike util.FlattenDoc it doesn't flatten the objects which have no properties in the schema.
+func flatten(row map[string]any, fields []*field, prefix string, doc map[string]any) {
+	for _, f := range fields {
+		v, ok := doc[f.name]
+		if !ok {
+			continue
+		}
+
+		name := f.name
+		if prefix != "" {
+			name = prefix + "." + f.name
+		}
+
+		if m, ok := v.(map[string]any); ok && f.typ == tschema.TypeObject && len(f.fields) > 0 {
+			flatten(row, f.fields, name, m)
+		} else {
+			row[name] = v
+		}
+	}
+}
+
+func (c *csvWriter) Write(doc json.RawMessage) error {
+	m, err := decodeDoc(doc)
+	if err != nil {
+		return err
+	}
+
+	flat := make(map[string]any)
+	flatten(flat, c.fields, "", m)
+
+	row := make([]string, 0, len(c.columns))
+
+	// missing fields are written as null, empty cells would be imported as empty strings
+	for _, col := range c.columns {
+		row = append(row, util.FormatValue(flat[col], "null"))
+	}
+
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+
+	return c.w.Error()
+}
@@ -0,0 +1,370 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export writes collection documents to CSV, JSON, NDJSON and Parquet files.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+)
+
+// Export formats.
+const (
+	FormatCSV     = "csv"
+	FormatJSON    = "json"
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+)
+
+var (
+	ErrUnknownFormat = fmt.Errorf("unknown export format. supported are: csv, json, ndjson, parquet")
+	ErrNoFields      = fmt.Errorf("no fields to export")
+)
+
+// Writer writes the documents to the output in the export format.
+type Writer interface {
+	Write(doc json.RawMessage) error
+	// Close writes the trailer of the output, it doesn't close underlying io.Writer.
+	Close() error
+}
+
+// NewWriter returns the writer of the format.
+// The collection schema defines CSV columns order and Parquet schema.
+// The projection, if not empty, is applied to the schema the same way it's applied to the documents.
+func NewWriter(w io.Writer, format string, schema []byte, projection []byte) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w}, nil
+	case FormatCSV, FormatParquet:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+
+	fields, err := parseSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields, err = project(fields, projection); err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, ErrNoFields
+	}
+
+	if format == FormatCSV {
+		return newCSVWriter(w, fields)
+	}
+
+	return newParquetWriter(w, fields)
+}
+
+// field is the collection schema field. Unlike schema.Field it retains the order of the properties.
+type field struct {
+	name   string
+	typ    string
+	format string
+	fields []*field
+	items  *field
+}
+
+// unstructured returns true for the fields which have no fixed structure, like objects without properties.
+func (f *field) unstructured() bool {
+	switch f.typ {
+	case tschema.TypeObject:
+		return len(f.fields) == 0
+	case tschema.TypeArray:
+		return f.items == nil
+	case tschema.TypeInteger, tschema.TypeNumber, tschema.TypeBoolean, tschema.TypeString:
+		return false
+	}
+
+	return true
+}
+
+type rawField struct {
+	Type       json.RawMessage `json:"type"`
+	Format     string          `json:"format"`
+	Properties json.RawMessage `json:"properties"`
+	Items      json.RawMessage `json:"items"`
+}
+
+func parseSchema(raw []byte) ([]*field, error) {
+	f, err := parseField("", raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.fields, nil
+}
+
+func parseField(name string, raw json.RawMessage) (*field, error) {
+	var rf rawField
+
+	if err := json.Unmarshal(raw, &rf); err != nil {
+		return nil, err
+	}
+
+	f := &field{name: name, typ: fieldType(rf.Type), format: rf.Format}
+
+	if len(rf.Properties) > 0 {
+		fields, err := parseProperties(rf.Properties)
+		if err != nil {
+			return nil, err
+		}
+
+		f.fields = fields
+	}
+
+	if len(rf.Items) > 0 {
+		items, err := parseField("", rf.Items)
+		if err != nil {
+			return nil, err
+		}
+
+		f.items = items
+	}
+
+	return f, nil
+}
+
+// fieldType returns the type of the field, ignoring "null" in the list of types.
+func fieldType(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	var l []string
+
+	_ = json.Unmarshal(raw, &l)
+
+	for _, v := range l {
+		if v != "null" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// parseProperties parses properties object preserving the order of the fields.
+func parseProperties(raw json.RawMessage) ([]*field, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var res []*field
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var v json.RawMessage
+		if err = dec.Decode(&v); err != nil {
+			return nil, err
+		}
+
+		name, _ := t.(string)
+
+		f, err := parseField(name, v)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, f)
+	}
+
+	return res, nil
+}
+
+type match int
+
+const (
+	matchNone match = iota
+	matchAll
+	matchPartial
+)
+
+// projection is the parsed read fields, like {"name": true, "address.city": true}.
+type projection struct {
+	fields  map[string]bool
+	include bool
+}
+
+func (p *projection) match(path string) match {
+	partial := false
+
+	for k := range p.fields {
+		switch {
+		case k == path || strings.HasPrefix(path, k+"."):
+			if p.include {
+				return matchAll
+			}
+
+			return matchNone
+		case strings.HasPrefix(k, path+"."):
+			partial = true
+		}
+	}
+
+	switch {
+	case partial:
+		return matchPartial
+	case p.include:
+		return matchNone
+	}
+
+	return matchAll
+}
+
+func (p *projection) apply(fields []*field, prefix string) []*field {
+	var res []*field
+
+	for _, f := range fields {
+		path := f.name
+		if prefix != "" {
+			path = prefix + "." + f.name
+		}
+
+		switch p.match(path) {
+		case matchAll:
+			res = append(res, f)
+		case matchPartial:
+			if len(f.fields) == 0 {
+				res = append(res, f)
+				continue
+			}
+
+			if sub := p.apply(f.fields, path); len(sub) > 0 {
+				c := *f
+				c.fields = sub
+				res = append(res, &c)
+			}
+		case matchNone:
+		}
+	}
+
+	return res
+}
+
+func project(fields []*field, raw []byte) ([]*field, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return fields, nil
+	}
+
+	p := &projection{}
+
+	if err := json.Unmarshal(raw, &p.fields); err != nil {
+		return nil, err
+	}
+
+	if len(p.fields) == 0 {
+		return fields, nil
+	}
+
+	for _, v := range p.fields {
+		if v {
+			p.include = true
+		}
+	}
+
+	return p.apply(fields, ""), nil
+}
+
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+// Write writes the document as the element of the JSON array.
+// The array is streamed, so the documents are not accumulated in memory.
+func (j *jsonWriter) Write(doc json.RawMessage) error {
+	var buf bytes.Buffer
+
+	if j.count == 0 {
+		buf.WriteString("[\n")
+	} else {
+		buf.WriteString(",\n")
+	}
+
+	if err := json.Compact(&buf, doc); err != nil {
+		return err
+	}
+
+	j.count++
+
+	_, err := j.w.Write(buf.Bytes())
+
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if j.count == 0 {
+		_, err := io.WriteString(j.w, "[]\n")
+		return err
+	}
+
+	_, err := io.WriteString(j.w, "\n]\n")
+
+	return err
+}
+
+type ndjsonWriter struct {
+	w io.Writer
+}
+
+func (n *ndjsonWriter) Write(doc json.RawMessage) error {
+	var buf bytes.Buffer
+
+	if err := json.Compact(&buf, doc); err != nil {
+		return err
+	}
+
+	buf.WriteByte('\n')
+
+	_, err := n.w.Write(buf.Bytes())
+
+	return err
+}
+
+func (*ndjsonWriter) Close() error {
+	return nil
+}
+
+func decodeDoc(doc json.RawMessage) (map[string]any, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+var testSchema = `{
+  "title": "users",
+  "properties": {
+    "id": { "type": "integer" },
+    "name": { "type": "string" },
+    "address": { "type": "object", "properties": {
+      "street": { "type": "string" },
+      "city": { "type": "string" }
+    } },
+    "meta": { "type": "object" },
+    "tags": { "type": "array", "items": { "type": "string" } },
+    "created": { "type": "string", "format": "date-time" },
+    "score": { "type": "number" }
+  },
+  "primary_key": ["id"]
+}`
+
+var testDocs = []string{
+	`{"id": 1, "name": "Alice, Jr.", "address": {"city": "SF", "street": "Main"}, "meta": {"a": 1},
+		"tags": ["x", "y"], "created": "2023-01-02T03:04:05Z", "score": 1.5}`,
+	`{"id": 2, "name": null, "address": {"city": "NY"}}`,
+}
+
+func write(t *testing.T, format string, projection string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, format, []byte(testSchema), []byte(projection))
+	require.NoError(t, err)
+
+	for _, d := range testDocs {
+		require.NoError(t, w.Write(json.RawMessage(d)))
+	}
+
+	require.NoError(t, w.Close())
+
+	return buf.String()
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		projection string
+		exp        string
+	}{
+		{
+			"csv", FormatCSV, `{}`, `id,name,address.street,address.city,meta,tags,created,score
+1,"Alice, Jr.",Main,SF,"{""a"":1}","[""x"",""y""]",2023-01-02T03:04:05Z,1.5
+2,null,null,NY,null,null,null,null
+`,
+		},
+		{
+			"csv_include", FormatCSV, `{"address.city": true, "name": true}`, `name,address.city
+"Alice, Jr.",SF
+null,NY
+`,
+		},
+		{
+			"csv_exclude", FormatCSV, `{"address": false, "meta": false, "tags": false, "created": false}`,
+			`id,name,score
+1,"Alice, Jr.",1.5
+2,null,null
+`,
+		},
+		{
+			"json", FormatJSON, ``, `[
+{"id":1,"name":"Alice, Jr.","address":{"city":"SF","street":"Main"},"meta":{"a":1},"tags":["x","y"],` +
+				`"created":"2023-01-02T03:04:05Z","score":1.5},
+{"id":2,"name":null,"address":{"city":"NY"}}
+]
+`,
+		},
+		{
+			"ndjson", FormatNDJSON, ``, `{"id":1,"name":"Alice, Jr.","address":{"city":"SF","street":"Main"},"meta":{"a":1},` +
+				`"tags":["x","y"],"created":"2023-01-02T03:04:05Z","score":1.5}
+{"id":2,"name":null,"address":{"city":"NY"}}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, write(t, tt.format, tt.projection))
+		})
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	out := write(t, FormatParquet, `{}`)
+
+	f, err := buffer.NewBufferFile([]byte(out))
+	require.NoError(t, err)
+
+	pr, err := reader.NewParquetReader(f, nil, 1)
+	require.NoError(t, err)
+
+	defer pr.ReadStop()
+
+	require.Equal(t, int64(2), pr.GetNumRows())
+
+	rows, err := pr.ReadByNumber(2)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(rows)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"Id": 1, "Name": "Alice, Jr.", "Address": {"Street": "Main", "City": "SF"}, "Meta": "{\"a\":1}",
+			"Tags": ["x", "y"], "Created": 1672628645000, "Score": 1.5},
+		{"Id": 2, "Name": null, "Address": {"Street": null, "City": "NY"}, "Meta": null,
+			"Tags": null, "Created": null, "Score": null}
+	]`, string(b))
+}
+
+func TestExportErrors(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, "xml", []byte(testSchema), nil)
+	require.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, err = NewWriter(&bytes.Buffer{}, FormatCSV, []byte(testSchema), []byte(`{"unknown": true}`))
+	require.ErrorIs(t, err, ErrNoFields)
+}
@@ -0,0 +1,183 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetParallelism is the number of goroutines used to marshal the documents.
+const parquetParallelism = 4
+
+// parquetSchema is the schema definition in the format of the Parquet JSON writer.
+type parquetSchema struct {
+	Tag    string           `json:"Tag"`
+	Fields []*parquetSchema `json:"Fields,omitempty"`
+}
+
+// parquetWriter writes the documents as the Parquet file with nested objects represented by groups
+// and arrays by lists. Date-time fields are stored as timestamps, fields without fixed structure as JSON strings.
+type parquetWriter struct {
+	w      *writer.JSONWriter
+	fields []*field
+}
+
+func newParquetWriter(w io.Writer, fields []*field) (*parquetWriter, error) {
+	root := &parquetSchema{Tag: "name=parquet_go_root, repetitiontype=REQUIRED"}
+
+	for _, f := range fields {
+		root.Fields = append(root.Fields, parquetField(f.name, f))
+	}
+
+	sch, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+
+	pw, err := writer.NewJSONWriterFromWriter(string(sch), w, parquetParallelism)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetWriter{w: pw, fields: fields}, nil
+}
+
+func parquetField(name string, f *field) *parquetSchema {
+	tag := "name=" + name + ", repetitiontype=OPTIONAL"
+
+	switch {
+	case f.unstructured():
+		return &parquetSchema{Tag: tag + ", type=BYTE_ARRAY, convertedtype=UTF8"}
+	case f.typ == tschema.TypeObject:
+		s := &parquetSchema{Tag: tag}
+		for _, v := range f.fields {
+			s.Fields = append(s.Fields, parquetField(v.name, v))
+		}
+
+		return s
+	case f.typ == tschema.TypeArray:
+		return &parquetSchema{Tag: tag + ", type=LIST", Fields: []*parquetSchema{parquetField("element", f.items)}}
+	case f.typ == tschema.TypeInteger && f.format == tschema.FormatInt32:
+		return &parquetSchema{Tag: tag + ", type=INT32"}
+	case f.typ == tschema.TypeInteger:
+		return &parquetSchema{Tag: tag + ", type=INT64"}
+	case f.typ == tschema.TypeNumber:
+		return &parquetSchema{Tag: tag + ", type=DOUBLE"}
+	case f.typ == tschema.TypeBoolean:
+		return &parquetSchema{Tag: tag + ", type=BOOLEAN"}
+	case f.format == tschema.FormatDateTime:
+		return &parquetSchema{Tag: tag + ", type=INT64, convertedtype=TIMESTAMP_MILLIS"}
+	}
+
+	return &parquetSchema{Tag: tag + ", type=BYTE_ARRAY, convertedtype=UTF8"}
+}
+
+// parquetValue converts the document value to the representation expected by the Parquet JSON writer.
+func parquetValue(f *field, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch {
+	case f.unstructured():
+		b, err := json.Marshal(v)
+		return string(b), err
+	case f.typ == tschema.TypeObject:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+
+		return parquetObject(f.fields, m)
+	case f.typ == tschema.TypeArray:
+		arr, ok := v.([]any)
+		if !ok {
+			return v, nil
+		}
+
+		res := make([]any, 0, len(arr))
+
+		for _, e := range arr {
+			c, err := parquetValue(f.items, e)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, c)
+		}
+
+		return res, nil
+	case f.typ == tschema.TypeString && f.format == tschema.FormatDateTime:
+		s, _ := v.(string)
+
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.UnixMilli(), nil
+	}
+
+	return v, nil
+}
+
+func parquetObject(fields []*field, doc map[string]any) (map[string]any, error) {
+	res := make(map[string]any, len(fields))
+
+	for _, f := range fields {
+		v, ok := doc[f.name]
+		if !ok {
+			continue
+		}
+
+		c, err := parquetValue(f, v)
+		if err != nil {
+			return nil, err
+		}
+
+		res[f.name] = c
+	}
+
+	return res, nil
+}
+
+func (p *parquetWriter) Write(doc json.RawMessage) error {
+	m, err := decodeDoc(doc)
+	if err != nil {
+		return err
+	}
+
+	res, err := parquetObject(p.fields, m)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	return p.w.Write(string(b))
+}
+
+// Close writes the footer of the Parquet file.
+func (p *parquetWriter) Close() error {
+	return p.w.WriteStop()
+}
@@ -74,6 +74,10 @@ func findKey(d map[string]any, names [][]string, key int) map[string]any {
 	return d
 }
 
+func isJSONArray(v string) bool {
+	return strings.HasPrefix(strings.TrimSpace(v), "[") && json.Valid([]byte(v))
+}
+
 func readCSVBatch(reader *csv.Reader, names [][]string, batchSize int) []json.RawMessage {
 	var docs []json.RawMessage
 
@@ -103,6 +107,9 @@ func readCSVBatch(reader *csv.Reader, names [][]string, batchSize int) []json.Ra
 				d[names[k][len(names[k])-1]] = true
 			case strings.TrimSpace(v) == "false":
 				d[names[k][len(names[k])-1]] = false
+			case isJSONArray(v):
+				// arrays are exported as JSON
+				d[names[k][len(names[k])-1]] = json.RawMessage(v)
 			default:
 				d[names[k][len(names[k])-1]] = v
 			}
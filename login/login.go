@@ -205,14 +205,14 @@ func Ensure(cctx context.Context, fn func(ctx context.Context) error) {
 		config.DefaultConfig.ClientID != "" || config.DefaultConfig.ClientSecret != "" || !util.IsTTY(os.Stdin) ||
 		isLocalConn(GetHost("")) {
 		util.PrintError(err)
-		os.Exit(1) //nolint:revive
+		util.Exit(1)
 	}
 
 	lctx, lcancel := util.GetContext(cctx)
 
 	if err = CmdLow(lctx, GetHost("")); err != nil {
 		lcancel()
-		os.Exit(1) //nolint:revive
+		util.Exit(1)
 	}
 
 	lcancel()
@@ -227,7 +227,7 @@ func Ensure(cctx context.Context, fn func(ctx context.Context) error) {
 
 	if err != nil {
 		util.PrintError(err)
-		os.Exit(1) //nolint:revive
+		util.Exit(1)
 	}
 }
 
@@ -0,0 +1,246 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate implements loading and planning of the versioned schema migrations.
+//
+// Migrations are stored in the directory as JSON files named {version}_{name}.json.
+// Every file contains operations to apply and revert the migration:
+//
+//	{
+//	  "up": [ { "create_or_update_collection": { "collection": "users", "schema": {...} } } ],
+//	  "down": [ { "drop_collection": { "collection": "users" } } ]
+//	}
+//
+// Operations have the same format as the operations of the "transact" command.
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Collection where applied migrations are recorded.
+	Collection = "tigris_migrations"
+
+	fileExtension = ".json"
+)
+
+// CollectionSchema is the schema of the collection where applied migrations are recorded.
+var CollectionSchema = []byte(`{
+	"title": "` + Collection + `",
+	"properties": {
+		"version": { "type": "integer" },
+		"name": { "type": "string" },
+		"applied_at": { "type": "string", "format": "date-time" }
+	},
+	"primary_key": ["version"]
+}`)
+
+var (
+	ErrInvalidFileName  = fmt.Errorf("migration file name should be in the format {version}_{name}.json")
+	ErrDuplicateVersion = fmt.Errorf("duplicate migration version")
+	ErrMissingFile      = fmt.Errorf("migration file not found for the applied version")
+	ErrNoDownOps        = fmt.Errorf("migration doesn't have down operations")
+)
+
+// IsInternal returns true for the collections managed by the CLI,
+// which are not a part of the project schema.
+func IsInternal(coll string) bool {
+	return coll == Collection
+}
+
+// Migration is a versioned set of operations.
+type Migration struct {
+	Version int64             `json:"-"`
+	Name    string            `json:"-"`
+	Up      []json.RawMessage `json:"up"`
+	Down    []json.RawMessage `json:"down"`
+}
+
+// Record of the applied migration stored in the migrations collection.
+type Record struct {
+	Version   int64     `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// Status of the migration.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Missing   bool // applied, but the file doesn't exist
+}
+
+func parseFileName(name string) (int64, string, error) {
+	base := strings.TrimSuffix(name, fileExtension)
+
+	v, n, _ := strings.Cut(base, "_")
+
+	version, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", fmt.Errorf("%w: %s", ErrInvalidFileName, name)
+	}
+
+	return version, n, nil
+}
+
+// Load reads migrations from the directory, ordered by version.
+func Load(dir string) ([]*Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[int64]string)
+
+	var res []*Migration
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != fileExtension {
+			continue
+		}
+
+		version, name, err := parseFileName(e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if prev, ok := versions[version]; ok {
+			return nil, fmt.Errorf("%w: %s and %s", ErrDuplicateVersion, prev, e.Name())
+		}
+
+		versions[version] = e.Name()
+
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := &Migration{Version: version, Name: name}
+		if err = json.Unmarshal(b, m); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, e.Name())
+		}
+
+		log.Debug().Int64("version", version).Str("name", name).Msg("loaded migration")
+
+		res = append(res, m)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
+
+// Statuses merges local migrations with applied records, ordered by version.
+func Statuses(migrations []*Migration, applied []*Record) []*Status {
+	m := make(map[int64]*Status)
+
+	for _, v := range migrations {
+		m[v.Version] = &Status{Version: v.Version, Name: v.Name}
+	}
+
+	for _, v := range applied {
+		s, ok := m[v.Version]
+		if !ok {
+			s = &Status{Version: v.Version, Name: v.Name, Missing: true}
+			m[v.Version] = s
+		}
+
+		s.Applied = true
+		s.AppliedAt = v.AppliedAt
+	}
+
+	res := make([]*Status, 0, len(m))
+	for _, v := range m {
+		res = append(res, v)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res
+}
+
+// PlanUp returns pending migrations in the order of application.
+// Only migrations up to and including version "to" are returned, if "to" is not zero.
+// The number of migrations is limited by "steps", if "steps" is not zero.
+func PlanUp(migrations []*Migration, applied []*Record, to int64, steps int) []*Migration {
+	done := make(map[int64]bool)
+	for _, v := range applied {
+		done[v.Version] = true
+	}
+
+	var res []*Migration
+
+	for _, v := range migrations {
+		if done[v.Version] {
+			continue
+		}
+
+		if (to != 0 && v.Version > to) || (steps != 0 && len(res) == steps) {
+			break
+		}
+
+		res = append(res, v)
+	}
+
+	return res
+}
+
+// PlanDown returns applied migrations in the order of reverting.
+// Migrations with versions greater than "to" are reverted, limited by "steps", if "steps" is not zero.
+func PlanDown(migrations []*Migration, applied []*Record, to int64, steps int) ([]*Migration, error) {
+	byVersion := make(map[int64]*Migration)
+	for _, v := range migrations {
+		byVersion[v.Version] = v
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for _, v := range applied {
+		versions = append(versions, v.Version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	var res []*Migration
+
+	for _, v := range versions {
+		if v <= to || (steps != 0 && len(res) == steps) {
+			break
+		}
+
+		m, ok := byVersion[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrMissingFile, v)
+		}
+
+		if len(m.Down) == 0 {
+			return nil, fmt.Errorf("%w: %d_%s", ErrNoDownOps, m.Version, m.Name)
+		}
+
+		res = append(res, m)
+	}
+
+	return res, nil
+}
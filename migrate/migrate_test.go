@@ -0,0 +1,140 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, body := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600))
+	}
+
+	return dir
+}
+
+func versions(migrations []*Migration) []int64 {
+	var res []int64
+
+	for _, v := range migrations {
+		res = append(res, v.Version)
+	}
+
+	return res
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"10_third.json":  `{ "up": [ { "drop_collection": { "collection": "c1" } } ] }`,
+		"2_second.json":  `{ "up": [], "down": [] }`,
+		"1_first.json":   `{ "up": [ { "insert": { "collection": "c1" } } ], "down": [ { "delete": {} } ] }`,
+		"README.md":      `ignored`,
+		"notes.json.bak": `ignored`,
+	})
+
+	res, err := Load(dir)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 10}, versions(res))
+
+	assert.Equal(t, "first", res[0].Name)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{ "insert": { "collection": "c1" } }`)}, res[0].Up)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{ "delete": {} }`)}, res[0].Down)
+	assert.Equal(t, "third", res[2].Name)
+	assert.Empty(t, res[2].Down)
+}
+
+func TestLoadNegative(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		err   error
+	}{
+		{"invalid_version", map[string]string{"first.json": `{}`}, ErrInvalidFileName},
+		{"zero_version", map[string]string{"0_first.json": `{}`}, ErrInvalidFileName},
+		{"duplicate", map[string]string{"1_first.json": `{}`, "01_second.json": `{}`}, ErrDuplicateVersion},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Load(writeFiles(t, c.files))
+			assert.ErrorIs(t, err, c.err)
+		})
+	}
+
+	_, err := Load(writeFiles(t, map[string]string{"1_first.json": `{ "up": {} }`}))
+	assert.Error(t, err)
+}
+
+func TestPlan(t *testing.T) {
+	down := []json.RawMessage{json.RawMessage(`{}`)}
+
+	migrations := []*Migration{
+		{Version: 1, Down: down}, {Version: 2, Down: down}, {Version: 3, Down: down}, {Version: 4}, {Version: 5},
+	}
+
+	applied := []*Record{{Version: 2}, {Version: 1}, {Version: 3}}
+
+	t.Run("up", func(t *testing.T) {
+		assert.Equal(t, []int64{4, 5}, versions(PlanUp(migrations, applied, 0, 0)))
+		assert.Equal(t, []int64{4}, versions(PlanUp(migrations, applied, 4, 0)))
+		assert.Equal(t, []int64{4}, versions(PlanUp(migrations, applied, 0, 1)))
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, versions(PlanUp(migrations, nil, 0, 0)))
+		assert.Empty(t, PlanUp(migrations, applied, 3, 0))
+	})
+
+	t.Run("down", func(t *testing.T) {
+		res, err := PlanDown(migrations, applied, 0, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3}, versions(res))
+
+		res, err = PlanDown(migrations, applied, 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 2}, versions(res))
+
+		res, err = PlanDown(migrations, applied, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 2, 1}, versions(res))
+
+		_, err = PlanDown(migrations, []*Record{{Version: 4}}, 0, 0)
+		assert.ErrorIs(t, err, ErrNoDownOps)
+
+		_, err = PlanDown(migrations, []*Record{{Version: 7}}, 0, 0)
+		assert.ErrorIs(t, err, ErrMissingFile)
+	})
+}
+
+func TestStatuses(t *testing.T) {
+	res := Statuses(
+		[]*Migration{{Version: 1, Name: "first"}, {Version: 3, Name: "third"}},
+		[]*Record{{Version: 1, Name: "first"}, {Version: 2, Name: "second"}},
+	)
+
+	assert.Equal(t, []*Status{
+		{Version: 1, Name: "first", Applied: true},
+		{Version: 2, Name: "second", Applied: true, Missing: true},
+		{Version: 3, Name: "third"},
+	}, res)
+}
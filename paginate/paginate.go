@@ -0,0 +1,345 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package paginate implements keyset pagination of the read results.
+//
+// The page continues after the sort key of the last document of the previous page,
+// which is converted to the filter, so the pages are consistent even when the documents
+// are inserted or deleted between the reads, unlike the pages read with skip.
+package paginate
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/schema"
+)
+
+const (
+	asc  = "$asc"
+	desc = "$desc"
+)
+
+var (
+	ErrInvalidSort   = fmt.Errorf("sort should be an object with $asc or $desc values, like {\"name\": \"$asc\"}")
+	ErrInvalidToken  = fmt.Errorf("invalid page token")
+	ErrSortMismatch  = fmt.Errorf("page token was issued for different sort order")
+	ErrAfterMismatch = fmt.Errorf("number of --after values doesn't match number of sort fields")
+	ErrNoSortField   = fmt.Errorf("document doesn't contain sort field, it should be included in the projection")
+)
+
+// SortField is the field of the sort order.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+func (s SortField) MarshalJSON() ([]byte, error) {
+	dir := asc
+	if s.Desc {
+		dir = desc
+	}
+
+	return json.Marshal(map[string]string{s.Field: dir})
+}
+
+func (s *SortField) UnmarshalJSON(b []byte) error {
+	o, err := parseSortObject(b)
+	if err != nil {
+		return err
+	}
+
+	if len(o) != 1 {
+		return ErrInvalidSort
+	}
+
+	*s = o[0]
+
+	return nil
+}
+
+// Order is the sort order of the read request.
+type Order []SortField
+
+// ParseSort parses the list of sort objects, like {"created_at": "$desc"}.
+// The object may contain multiple fields, which are applied in the order of appearance.
+func ParseSort(sort []string) (Order, error) {
+	var res Order
+
+	for _, v := range sort {
+		o, err := parseSortObject([]byte(v))
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, o...)
+	}
+
+	return res, nil
+}
+
+func parseSortObject(b []byte) (Order, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, ErrInvalidSort
+	}
+
+	var res Order
+
+	for dec.More() {
+		k, err := dec.Token()
+		if err != nil {
+			return nil, ErrInvalidSort
+		}
+
+		v, err := dec.Token()
+		if err != nil {
+			return nil, ErrInvalidSort
+		}
+
+		name, _ := k.(string)
+
+		switch v {
+		case asc:
+			res = append(res, SortField{Field: name})
+		case desc:
+			res = append(res, SortField{Field: name, Desc: true})
+		default:
+			return nil, ErrInvalidSort
+		}
+	}
+
+	return res, nil
+}
+
+// JSON returns the sort order in the format of the read request options.
+func (o Order) JSON() []byte {
+	if len(o) == 0 {
+		return nil
+	}
+
+	b, _ := json.Marshal(o)
+
+	return b
+}
+
+// WithKey appends the primary key fields, which are not in the sort order yet,
+// to make the sort key unique, otherwise documents with equal sort key may be skipped between the pages.
+func (o Order) WithKey(pk []string) Order {
+	res := append(Order{}, o...)
+
+	for _, k := range pk {
+		found := false
+
+		for _, f := range o {
+			if f.Field == k {
+				found = true
+			}
+		}
+
+		if !found {
+			res = append(res, SortField{Field: k})
+		}
+	}
+
+	return res
+}
+
+// Projection amends the fields projection to include the sort fields,
+// as the token of the next page is made of the sort key of the last document.
+// Sort fields are added to the included fields or removed from the excluded fields.
+func (o Order) Projection(fields []byte) ([]byte, error) {
+	var proj map[string]any
+
+	if len(bytes.TrimSpace(fields)) > 0 {
+		if err := json.Unmarshal(fields, &proj); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(proj) == 0 || len(o) == 0 {
+		return fields, nil
+	}
+
+	include := false
+
+	for _, v := range proj {
+		if b, ok := v.(bool); ok && b {
+			include = true
+		}
+	}
+
+	for _, f := range o {
+		if include {
+			proj[f.Field] = true
+		} else {
+			delete(proj, f.Field)
+		}
+	}
+
+	return json.Marshal(proj)
+}
+
+func (o Order) equal(other Order) bool {
+	if len(o) != len(other) {
+		return false
+	}
+
+	for i := range o {
+		if o[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Token is the continuation token of the page.
+// It contains the sort key of the last document of the page.
+type Token struct {
+	Sort  Order `json:"sort"`
+	After []any `json:"after"`
+}
+
+// Encode returns the opaque representation of the token for the command line.
+func (t *Token) Encode() string {
+	b, _ := json.Marshal(t)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeToken decodes the token and checks that it was issued for the same sort order.
+// Empty sort order means the order of the token.
+func DecodeToken(s string, sort Order) (*Token, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var t Token
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	if err = dec.Decode(&t); err != nil || len(t.Sort) == 0 || len(t.Sort) != len(t.After) {
+		return nil, ErrInvalidToken
+	}
+
+	if len(sort) > 0 && !sort.equal(t.Sort) {
+		return nil, ErrSortMismatch
+	}
+
+	return &t, nil
+}
+
+// ParseAfter parses the --after value: the JSON value of the single sort field,
+// or JSON array of the values of all the sort fields. The value which is not JSON is treated as string.
+func ParseAfter(o Order, s string) (*Token, error) {
+	var v any
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err != nil {
+		v = s
+	}
+
+	var after []any
+
+	if arr, ok := v.([]any); ok && len(o) > 1 {
+		after = arr
+	} else {
+		after = []any{v}
+	}
+
+	if len(after) != len(o) {
+		return nil, ErrAfterMismatch
+	}
+
+	return &Token{Sort: o, After: after}, nil
+}
+
+// Next returns the token of the page which starts after the document.
+func Next(o Order, doc json.RawMessage) (*Token, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	t := &Token{Sort: o}
+
+	for _, f := range o {
+		v, ok := schema.LookupValue(m, f.Field)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNoSortField, f.Field)
+		}
+
+		t.After = append(t.After, v)
+	}
+
+	return t, nil
+}
+
+// Filter combines the filter with the condition selecting the documents after the token's sort key.
+// For sort order (a, b) and the key (x, y), the condition is: a > x or (a = x and b > y).
+func (t *Token) Filter(filter []byte) ([]byte, error) {
+	or := make([]any, 0, len(t.Sort))
+
+	for i, f := range t.Sort {
+		op := "$gt"
+		if f.Desc {
+			op = "$lt"
+		}
+
+		and := make([]any, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, map[string]any{t.Sort[j].Field: t.After[j]})
+		}
+
+		and = append(and, map[string]any{f.Field: map[string]any{op: t.After[i]}})
+
+		if len(and) == 1 {
+			or = append(or, and[0])
+		} else {
+			or = append(or, map[string]any{"$and": and})
+		}
+	}
+
+	var cond any = map[string]any{"$or": or}
+	if len(or) == 1 {
+		cond = or[0]
+	}
+
+	var user map[string]any
+
+	if len(bytes.TrimSpace(filter)) > 0 {
+		if err := json.Unmarshal(filter, &user); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(user) > 0 {
+		cond = map[string]any{"$and": []any{json.RawMessage(filter), cond}}
+	}
+
+	return json.Marshal(cond)
+}
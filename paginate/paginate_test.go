@@ -0,0 +1,136 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package paginate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort []string
+		exp  string
+		err  error
+	}{
+		{nil, ``, nil},
+		{[]string{`{"b": "$desc", "a": "$asc"}`, `{"c": "$asc"}`}, `[{"b":"$desc"},{"a":"$asc"},{"c":"$asc"}]`, nil},
+		{[]string{`{"a": "up"}`}, ``, ErrInvalidSort},
+		{[]string{`["a"]`}, ``, ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		o, err := ParseSort(tt.sort)
+		require.ErrorIs(t, err, tt.err)
+		assert.Equal(t, tt.exp, string(o.JSON()))
+	}
+}
+
+func TestProjection(t *testing.T) {
+	order := Order{{Field: "created", Desc: true}, {Field: "id"}}
+
+	tests := []struct {
+		name   string
+		order  Order
+		fields string
+		exp    string
+	}{
+		{"all fields", order, `{}`, `{}`},
+		{"no order", nil, `{"name": true}`, `{"name": true}`},
+		{"included", order, `{"name": true, "id": true}`, `{"name": true, "id": true, "created": true}`},
+		{"excluded", order, `{"bio": false, "created": false}`, `{"bio": false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.order.Projection([]byte(tt.fields))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.exp, string(res))
+		})
+	}
+
+	_, err := order.Projection([]byte(`[1]`))
+	require.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	order := Order{{Field: "created", Desc: true}, {Field: "address.city"}}.WithKey([]string{"id", "created"})
+
+	tests := []struct {
+		name   string
+		doc    string
+		filter string
+		exp    string
+	}{
+		{
+			"single", `{"created": "2023-01-01"}`, `{}`, `{"created": {"$lt": "2023-01-01"}}`,
+		},
+		{
+			"multiple", `{"id": 12345678901234567890, "created": "2023-01-01", "address": {"city": "SF"}}`, ``,
+			`{"$or": [
+				{"created": {"$lt": "2023-01-01"}},
+				{"$and": [{"created": "2023-01-01"}, {"address.city": {"$gt": "SF"}}]},
+				{"$and": [{"created": "2023-01-01"}, {"address.city": "SF"}, {"id": {"$gt": 12345678901234567890}}]}
+			]}`,
+		},
+		{
+			"user_filter", `{"created": "2023-01-01"}`, `{"status": "new"}`,
+			`{"$and": [{"status": "new"}, {"created": {"$lt": "2023-01-01"}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := order
+			if tt.name != "multiple" {
+				o = order[:1]
+			}
+
+			next, err := Next(o, []byte(tt.doc))
+			require.NoError(t, err)
+
+			// token survives the round trip through the command line
+			tok, err := DecodeToken(next.Encode(), o)
+			require.NoError(t, err)
+
+			f, err := tok.Filter([]byte(tt.filter))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.exp, string(f))
+		})
+	}
+}
+
+func TestTokenErrors(t *testing.T) {
+	o := Order{{Field: "id"}}
+
+	_, err := Next(o, []byte(`{"name": "a"}`))
+	require.ErrorIs(t, err, ErrNoSortField)
+
+	_, err = DecodeToken("not a token", o)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	tok := &Token{Sort: o, After: []any{1}}
+	_, err = DecodeToken(tok.Encode(), Order{{Field: "id", Desc: true}})
+	require.ErrorIs(t, err, ErrSortMismatch)
+
+	_, err = ParseAfter(Order{{Field: "a"}, {Field: "id"}}, `5`)
+	require.ErrorIs(t, err, ErrAfterMismatch)
+
+	tok, err = ParseAfter(Order{{Field: "name"}}, `Alice`)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"Alice"}, tok.After)
+}
@@ -0,0 +1,95 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reindex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+var ErrNoID = fmt.Errorf("index document has no id")
+
+// Hash returns the content hash of the document.
+// Documents which differ only in the order of the fields, the formatting of the numbers
+// or the null fields have the same hash.
+func Hash(doc json.RawMessage) (string, error) {
+	m, err := decode(doc)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(normalize(m))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+
+		for k, e := range v {
+			if e != nil {
+				res[k] = normalize(e)
+			}
+		}
+
+		return res
+	case []any:
+		res := make([]any, 0, len(v))
+		for _, e := range v {
+			res = append(res, normalize(e))
+		}
+
+		return res
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return json.Number(strconv.FormatInt(i, 10))
+		}
+
+		if f, err := v.Float64(); err == nil {
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+// DocID returns the id of the index document.
+func DocID(doc json.RawMessage) (string, error) {
+	var d struct {
+		ID *string `json:"id"`
+	}
+
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return "", err
+	}
+
+	if d.ID == nil {
+		return "", ErrNoID
+	}
+
+	return *d.ID, nil
+}
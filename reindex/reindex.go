@@ -0,0 +1,274 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reindex converts the collection schema and documents to the search index schema and documents.
+//
+// The id of the index documents is the string made of the primary key of the collection documents,
+// so the documents of the collection and the index can be matched.
+package reindex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// IDField is the id field of the search index documents.
+const IDField = "id"
+
+var (
+	ErrUnknownField  = fmt.Errorf("field is not in the collection schema")
+	ErrIDConflict    = fmt.Errorf("id field of the collection is not the primary key")
+	ErrMissingKey    = fmt.Errorf("primary key field is missing in the document")
+	ErrNoPrimaryKey  = fmt.Errorf("collection schema has no primary key")
+	ErrInvalidObject = fmt.Errorf("document should be an object")
+	ErrInvalidID     = fmt.Errorf("index document id doesn't match the primary key type")
+)
+
+// Converter converts the collection documents to the index documents.
+type Converter struct {
+	pk     []string
+	fields []string
+
+	// idType is the collection type of the id primary key, which is replaced by the string id in the index
+	idType string
+}
+
+// New returns the index schema derived from the collection schema, and the converter of the documents.
+// Only the fields, if set, and the primary key fields are indexed.
+func New(coll *schema.Schema, index string, fields []string) (*schema.Schema, *Converter, error) {
+	if len(coll.PrimaryKey) == 0 {
+		return nil, nil, ErrNoPrimaryKey
+	}
+
+	if coll.Fields[IDField] != nil && (len(coll.PrimaryKey) != 1 || coll.PrimaryKey[0] != IDField) {
+		return nil, nil, ErrIDConflict
+	}
+
+	c := &Converter{pk: coll.PrimaryKey}
+
+	if f := coll.Fields[IDField]; f != nil {
+		c.idType = f.Type.First()
+	}
+
+	if len(fields) > 0 {
+		c.fields = append(append(c.fields, coll.PrimaryKey...), fields...)
+	}
+
+	sch := &schema.Schema{
+		Name:   index,
+		Desc:   coll.Desc,
+		Fields: make(map[string]*schema.Field),
+	}
+
+	for name, f := range coll.Fields {
+		if c.indexed(name) {
+			sch.Fields[name] = indexField(f)
+		}
+	}
+
+	for _, name := range fields {
+		if coll.Fields[name] == nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+	}
+
+	// documents are scanned in the id order
+	sch.Fields[IDField] = &schema.Field{Type: schema.NewMultiType("string"), Sort: true}
+
+	return sch, c, nil
+}
+
+func (c *Converter) indexed(name string) bool {
+	if len(c.fields) == 0 {
+		return true
+	}
+
+	for _, f := range c.fields {
+		// the object containing the nested key field is indexed
+		if f == name || strings.HasPrefix(f, name+tschema.PathSeparator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexField drops the attributes which only apply to the collections.
+func indexField(f *schema.Field) *schema.Field {
+	if f == nil {
+		return nil
+	}
+
+	res := &schema.Field{
+		Type:        f.Type,
+		Format:      f.Format,
+		Desc:        f.Desc,
+		Items:       indexField(f.Items),
+		SearchIndex: f.SearchIndex,
+		Sort:        f.Sort,
+		Facet:       f.Facet,
+		Dimensions:  f.Dimensions,
+	}
+
+	if f.Fields != nil {
+		res.Fields = make(map[string]*schema.Field, len(f.Fields))
+		for k, v := range f.Fields {
+			res.Fields[k] = indexField(v)
+		}
+	}
+
+	return res
+}
+
+// ID returns the index document id of the collection document.
+// It's the value of the single primary key field, or JSON array of the values of the composite key.
+// Nested key fields are resolved by the dotted path.
+func (c *Converter) ID(doc map[string]any) (string, error) {
+	vals := make([]any, 0, len(c.pk))
+
+	for _, k := range c.pk {
+		v, ok := tschema.LookupValue(doc, k)
+		if !ok || v == nil {
+			return "", fmt.Errorf("%w: %s", ErrMissingKey, k)
+		}
+
+		vals = append(vals, v)
+	}
+
+	if len(vals) == 1 {
+		if s, ok := vals[0].(string); ok {
+			return s, nil
+		}
+
+		b, err := json.Marshal(vals[0])
+
+		return string(b), err
+	}
+
+	b, err := json.Marshal(vals)
+
+	return string(b), err
+}
+
+// PrimaryKey returns the primary key fields of the collection.
+func (c *Converter) PrimaryKey() []string {
+	return c.pk
+}
+
+// KeyFilter returns the collection filter selecting the documents with the primary keys of the index documents.
+func (c *Converter) KeyFilter(docs []json.RawMessage) ([]byte, error) {
+	or := make([]any, 0, len(docs))
+
+	for _, doc := range docs {
+		m, err := decode(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		and := make([]any, 0, len(c.pk))
+
+		for _, k := range c.pk {
+			v, ok := tschema.LookupValue(m, k)
+			if !ok || v == nil {
+				return nil, fmt.Errorf("%w: %s", ErrMissingKey, k)
+			}
+
+			if k == IDField {
+				if v, err = c.idValue(v); err != nil {
+					return nil, err
+				}
+			}
+
+			and = append(and, map[string]any{k: v})
+		}
+
+		if len(and) == 1 {
+			or = append(or, and[0])
+		} else {
+			or = append(or, map[string]any{"$and": and})
+		}
+	}
+
+	if len(or) == 1 {
+		return json.Marshal(or[0])
+	}
+
+	return json.Marshal(map[string]any{"$or": or})
+}
+
+// idValue restores the collection value of the id primary key from the string id of the index document.
+func (c *Converter) idValue(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok || c.idType == "" || c.idType == tschema.TypeString {
+		return v, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	var res any
+	if err := dec.Decode(&res); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidID, s)
+	}
+
+	return res, nil
+}
+
+// Convert returns the index document and its id.
+func (c *Converter) Convert(doc json.RawMessage) (json.RawMessage, string, error) {
+	m, err := decode(doc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, err := c.ID(m)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res := make(map[string]any, len(m)+1)
+
+	for k, v := range m {
+		if c.indexed(k) {
+			res[k] = v
+		}
+	}
+
+	res[IDField] = id
+
+	b, err := json.Marshal(res)
+
+	return b, id, err
+}
+
+func decode(doc json.RawMessage) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	if m == nil {
+		return nil, ErrInvalidObject
+	}
+
+	return m, nil
+}
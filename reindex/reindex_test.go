@@ -0,0 +1,245 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reindex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func parseSchema(t *testing.T, s string) *schema.Schema {
+	t.Helper()
+
+	var sch schema.Schema
+	require.NoError(t, json.Unmarshal([]byte(s), &sch))
+
+	return &sch
+}
+
+func TestNew(t *testing.T) {
+	coll := parseSchema(t, `{
+		"title": "users",
+		"properties": {
+			"uid": {"type": "integer", "autoGenerate": true},
+			"name": {"type": "string", "maxLength": 100, "searchIndex": true, "sort": true},
+			"created": {"type": "string", "format": "date-time", "createdAt": true},
+			"address": {"type": "object", "properties": {"city": {"type": "string", "default": "SF"}}},
+			"vec": {"type": "array", "format": "vector", "dimensions": 3}
+		},
+		"primary_key": ["uid"]
+	}`)
+
+	sch, _, err := New(coll, "users_idx", nil)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(sch)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"title": "users_idx",
+		"properties": {
+			"id": {"type": "string", "sort": true},
+			"uid": {"type": "integer"},
+			"name": {"type": "string", "searchIndex": true, "sort": true},
+			"created": {"type": "string", "format": "date-time"},
+			"address": {"type": "object", "properties": {"city": {"type": "string"}}},
+			"vec": {"type": "array", "format": "vector", "dimensions": 3}
+		}
+	}`, string(b))
+
+	sch, _, err = New(coll, "users_idx", []string{"name"})
+	require.NoError(t, err)
+
+	b, err = json.Marshal(sch)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"title": "users_idx",
+		"properties": {
+			"id": {"type": "string", "sort": true},
+			"uid": {"type": "integer"},
+			"name": {"type": "string", "searchIndex": true, "sort": true}
+		}
+	}`, string(b))
+
+	_, _, err = New(coll, "users_idx", []string{"unknown"})
+	require.ErrorIs(t, err, ErrUnknownField)
+
+	_, _, err = New(parseSchema(t, `{"properties": {"id": {"type": "string", "sort": true}, "k": {"type": "string"}},
+		"primary_key": ["k"]}`), "idx", nil)
+	require.ErrorIs(t, err, ErrIDConflict)
+
+	_, _, err = New(parseSchema(t, `{"properties": {"k": {"type": "string"}}}`), "idx", nil)
+	require.ErrorIs(t, err, ErrNoPrimaryKey)
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name   string
+		pk     []string
+		fields []string
+		doc    string
+		exp    string
+		id     string
+		err    error
+	}{
+		{"string key", []string{"id"}, nil, `{"id": "a1", "name": "Alice"}`, `{"id": "a1", "name": "Alice"}`, "a1", nil},
+		{
+			"integer key", []string{"id"}, nil, `{"id": 12345678901234567, "name": "Alice"}`,
+			`{"id": "12345678901234567", "name": "Alice"}`, "12345678901234567", nil,
+		},
+		{
+			"composite key", []string{"a", "b"}, nil, `{"a": 1, "b": "x", "n": 1.5}`,
+			`{"id": "[1,\"x\"]", "a": 1, "b": "x", "n": 1.5}`, `[1,"x"]`, nil,
+		},
+		{
+			"fields", []string{"uid"}, []string{"name"}, `{"uid": 1, "name": "Alice", "age": 30}`,
+			`{"id": "1", "uid": 1, "name": "Alice"}`, "1", nil,
+		},
+		{
+			"nested key", []string{"user.id"}, []string{"n"}, `{"user": {"id": 5, "name": "Alice"}, "n": 1, "m": 2}`,
+			`{"id": "5", "user": {"id": 5, "name": "Alice"}, "n": 1}`, "5", nil,
+		},
+		{"missing nested key", []string{"user.id"}, nil, `{"user": {"name": "Alice"}}`, "", "", ErrMissingKey},
+		{"missing key", []string{"uid"}, nil, `{"name": "Alice"}`, "", "", ErrMissingKey},
+		{"null", []string{"uid"}, nil, `null`, "", "", ErrInvalidObject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{pk: tt.pk}
+			if len(tt.fields) > 0 {
+				c.fields = append(append(c.fields, tt.pk...), tt.fields...)
+			}
+
+			doc, id, err := c.Convert(json.RawMessage(tt.doc))
+			require.ErrorIs(t, err, tt.err)
+
+			if tt.err != nil {
+				return
+			}
+
+			assert.JSONEq(t, tt.exp, string(doc))
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
+
+func TestKeyFilter(t *testing.T) {
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id": "[12345678901234567,\"x\"]", "a": 12345678901234567, "u": {"b": "x"}}`),
+		json.RawMessage(`{"id": "[2,\"y\"]", "a": 2, "u": {"b": "y"}}`),
+	}
+
+	c := &Converter{pk: []string{"a", "u.b"}}
+
+	f, err := c.KeyFilter(docs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"$or": [
+		{"$and": [{"a": 12345678901234567}, {"u.b": "x"}]},
+		{"$and": [{"a": 2}, {"u.b": "y"}]}
+	]}`, string(f))
+
+	c = &Converter{pk: []string{"a"}}
+
+	f, err = c.KeyFilter(docs[1:])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": 2}`, string(f))
+
+	_, err = (&Converter{pk: []string{"c"}}).KeyFilter(docs)
+	require.ErrorIs(t, err, ErrMissingKey)
+}
+
+func TestKeyFilterID(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  string
+		doc  string
+		exp  string
+	}{
+		{"integer", "integer", `{"id": 12345678901234567, "name": "a"}`, `{"id": 12345678901234567}`},
+		{"string", "string", `{"id": "5", "name": "a"}`, `{"id": "5"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coll := &schema.Schema{
+				Fields: map[string]*schema.Field{
+					"id":   {Type: schema.NewMultiType(tt.typ)},
+					"name": {Type: schema.NewMultiType("string")},
+				},
+				PrimaryKey: []string{"id"},
+			}
+
+			_, c, err := New(coll, "idx", nil)
+			require.NoError(t, err)
+
+			idoc, _, err := c.Convert(json.RawMessage(tt.doc))
+			require.NoError(t, err)
+
+			f, err := c.KeyFilter([]json.RawMessage{idoc})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.exp, string(f))
+		})
+	}
+
+	c := &Converter{pk: []string{"id"}, idType: "integer"}
+
+	_, err := c.KeyFilter([]json.RawMessage{json.RawMessage(`{"id": "x"}`)})
+	require.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestHash(t *testing.T) {
+	h, err := Hash(json.RawMessage(`{"id": "1", "n": 1.0, "a": [{"x": 1, "y": null}], "z": null}`))
+	require.NoError(t, err)
+
+	for _, doc := range []string{
+		`{"a": [{"x": 1.00}], "n": 1, "id": "1"}`,
+		`{"n": 1e0, "id": "1", "a": [{"x": 1}]}`,
+	} {
+		h2, err := Hash(json.RawMessage(doc))
+		require.NoError(t, err)
+		assert.Equal(t, h, h2, doc)
+	}
+
+	for _, doc := range []string{
+		`{"id": "1", "n": 2, "a": [{"x": 1}]}`,
+		`{"id": "1", "n": "1", "a": [{"x": 1}]}`,
+		`{"id": "1", "n": 1, "a": [{"x": 1}], "b": false}`,
+	} {
+		h2, err := Hash(json.RawMessage(doc))
+		require.NoError(t, err)
+		assert.NotEqual(t, h, h2, doc)
+	}
+
+	_, err = Hash(json.RawMessage(`[1]`))
+	require.Error(t, err)
+}
+
+func TestDocID(t *testing.T) {
+	id, err := DocID(json.RawMessage(`{"id": "a1", "name": "Alice"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "a1", id)
+
+	_, err = DocID(json.RawMessage(`{"name": "Alice"}`))
+	require.ErrorIs(t, err, ErrNoID)
+
+	_, err = DocID(json.RawMessage(`{"id": 1}`))
+	require.Error(t, err)
+}
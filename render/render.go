@@ -0,0 +1,265 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render renders search results for the terminal:
+// facets as bar histograms and hits as a compact table with the query terms highlighted.
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/search"
+)
+
+const (
+	highlightStart = "\x1b[1;33m"
+	highlightEnd   = "\x1b[0m"
+
+	barWidth     = 40
+	maxCellWidth = 40
+	scoreColumn  = "score"
+)
+
+// Color enables ANSI colors in the output.
+var Color = true
+
+// Terms returns the lower case terms of the search query to highlight.
+func Terms(query string) []string {
+	var res []string
+
+	seen := make(map[string]bool)
+
+	for _, t := range strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		t = lower(t)
+		if !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+
+	return res
+}
+
+// lower converts rune by rune, so the positions in the string are preserved.
+func lower(s string) string {
+	r := []rune(s)
+	for i := range r {
+		r[i] = unicode.ToLower(r[i])
+	}
+
+	return string(r)
+}
+
+// Highlight highlights the case-insensitive occurrences of the terms in the text.
+func Highlight(s string, terms []string) string {
+	if !Color || len(terms) == 0 {
+		return s
+	}
+
+	text := []rune(s)
+	low := []rune(lower(s))
+	marked := make([]bool, len(text))
+
+	for _, t := range terms {
+		tr := []rune(t)
+		if len(tr) == 0 {
+			continue
+		}
+
+		for i := 0; i+len(tr) <= len(low); i++ {
+			if string(low[i:i+len(tr)]) == t {
+				for j := i; j < i+len(tr); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+
+	for i, r := range text {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(highlightStart)
+		}
+
+		b.WriteRune(r)
+
+		if marked[i] && (i == len(text)-1 || !marked[i+1]) {
+			b.WriteString(highlightEnd)
+		}
+	}
+
+	return b.String()
+}
+
+// Facets writes the facet value counts as the histograms, sorted by the count.
+func Facets(w io.Writer, facets map[string]search.Facet) {
+	names := make([]string, 0, len(facets))
+	for n := range facets {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	for _, n := range names {
+		f := facets[n]
+
+		counts := append([]search.FacetCount{}, f.Counts...)
+		sort.SliceStable(counts, func(i, j int) bool {
+			if counts[i].Count != counts[j].Count {
+				return counts[i].Count > counts[j].Count
+			}
+
+			return counts[i].Value < counts[j].Value
+		})
+
+		_, _ = fmt.Fprintf(w, "%s\n", n)
+
+		var maxCount int64
+
+		valueWidth := 0
+
+		for _, c := range counts {
+			if c.Count > maxCount {
+				maxCount = c.Count
+			}
+
+			if l := len([]rune(c.Value)); l > valueWidth {
+				valueWidth = l
+			}
+		}
+
+		for _, c := range counts {
+			bar := 0
+			if maxCount > 0 {
+				bar = int(c.Count * barWidth / maxCount)
+			}
+
+			if bar == 0 && c.Count > 0 {
+				bar = 1
+			}
+
+			_, _ = fmt.Fprintf(w, "  %s  %s %d\n", pad(c.Value, valueWidth), strings.Repeat("█", bar), c.Count)
+		}
+
+		if s := f.Stats; s.Avg != nil && s.Min != nil && s.Max != nil {
+			_, _ = fmt.Fprintf(w, "  min %g  max %g  avg %g\n", *s.Min, *s.Max, *s.Avg)
+		}
+	}
+}
+
+// Hit is the search hit document with its relevance score.
+type Hit struct {
+	Score    string
+	Document any
+}
+
+// Hits writes the hits as the table of the score and the fields.
+// All the fields of the documents are shown if fields is empty.
+// The terms are highlighted in the highlight fields, or in all the fields if highlight is empty.
+func Hits(w io.Writer, hits []Hit, fields []string, highlight []string, terms []string) {
+	rows := make([]map[string]any, 0, len(hits))
+	for _, h := range hits {
+		rows = append(rows, util.FlattenDoc(h.Document))
+	}
+
+	if len(fields) == 0 {
+		fields = columns(rows)
+	}
+
+	cols := append([]string{scoreColumn}, fields...)
+	cells := make([][]string, 0, len(hits)+1)
+	cells = append(cells, cols)
+
+	for i, row := range rows {
+		line := []string{hits[i].Score}
+		for _, f := range fields {
+			line = append(line, cell(util.FormatValue(row[f], "")))
+		}
+
+		cells = append(cells, line)
+	}
+
+	widths := make([]int, len(cols))
+
+	for _, line := range cells {
+		for i, c := range line {
+			if l := len([]rune(c)); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+
+	for n, line := range cells {
+		var b strings.Builder
+
+		for i, c := range line {
+			text := c
+			if n > 0 && i > 0 && (len(highlight) == 0 || util.Contains(highlight, cols[i])) {
+				text = Highlight(c, terms)
+			}
+
+			if i == len(line)-1 {
+				b.WriteString(text)
+				break
+			}
+
+			b.WriteString(text)
+			b.WriteString(strings.Repeat(" ", widths[i]-len([]rune(c))+2))
+		}
+
+		_, _ = fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+}
+
+// cell makes the value fit single line of the table cell.
+func cell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+
+	if r := []rune(s); len(r) > maxCellWidth {
+		return string(r[:maxCellWidth-1]) + "…"
+	}
+
+	return s
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-len([]rune(s)))
+}
+
+func columns(rows []map[string]any) []string {
+	set := make(map[string]bool)
+
+	for _, row := range rows {
+		for k := range row {
+			set[k] = true
+		}
+	}
+
+	cols := make([]string, 0, len(set))
+	for k := range set {
+		cols = append(cols, k)
+	}
+
+	sort.Strings(cols)
+
+	return cols
+}
@@ -0,0 +1,103 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tigrisdata/tigris-client-go/search"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"alice", "o", "neil", "sf"}, Terms(`"Alice" O'Neil, sf alice`))
+	assert.Nil(t, Terms(" "))
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		s     string
+		terms []string
+		exp   string
+	}{
+		{"Alice Wong", []string{"alice"}, "\x1b[1;33mAlice\x1b[0m Wong"},
+		{"Alice Wong", []string{"wong", "ice"}, "Al\x1b[1;33mice\x1b[0m \x1b[1;33mWong\x1b[0m"},
+		{"aaa", []string{"aa"}, "\x1b[1;33maaa\x1b[0m"},
+		{"Ünïcode", []string{"ünï"}, "\x1b[1;33mÜnï\x1b[0mcode"},
+		{"Bob", []string{"alice"}, "Bob"},
+		{"Bob", nil, "Bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			assert.Equal(t, tt.exp, Highlight(tt.s, tt.terms))
+		})
+	}
+
+	Color = false
+	defer func() { Color = true }()
+
+	assert.Equal(t, "Alice", Highlight("Alice", []string{"alice"}))
+}
+
+func TestFacets(t *testing.T) {
+	avg, minV, maxV := 30.5, 18.0, 65.0
+
+	var buf bytes.Buffer
+
+	Facets(&buf, map[string]search.Facet{
+		"city": {Counts: []search.FacetCount{
+			{Value: "SF", Count: 2}, {Value: "New York", Count: 8}, {Value: "LA", Count: 2},
+		}},
+		"age": {
+			Counts: []search.FacetCount{{Value: "30", Count: 100}, {Value: "31", Count: 1}},
+			Stats:  search.FacetStats{Avg: &avg, Min: &minV, Max: &maxV, Count: 101},
+		},
+	})
+
+	assert.Equal(t, `age
+  30  ████████████████████████████████████████ 100
+  31  █ 1
+  min 18  max 65  avg 30.5
+city
+  New York  ████████████████████████████████████████ 8
+  LA        ██████████ 2
+  SF        ██████████ 2
+`, buf.String())
+}
+
+func TestHits(t *testing.T) {
+	hits := []Hit{
+		{Score: "1200", Document: map[string]any{"name": "Alice Wong", "address": map[string]any{"city": "Alice Springs"}}},
+		{Score: "900", Document: map[string]any{
+			"name": "Bob", "bio": "Alice's friend, with a very long biography which doesn't fit the table",
+		}},
+	}
+
+	var buf bytes.Buffer
+
+	Hits(&buf, hits, nil, []string{"name"}, []string{"alice"})
+
+	assert.Equal(t, "score  address.city   bio                                       name\n"+
+		"1200   Alice Springs                                            \x1b[1;33mAlice\x1b[0m Wong\n"+
+		"900                   Alice's friend, with a very long biogra…  Bob\n", buf.String())
+
+	buf.Reset()
+
+	Hits(&buf, hits, []string{"name"}, nil, []string{"bob"})
+
+	assert.Equal(t, "score  name\n1200   Alice Wong\n900    \x1b[1;33mBob\x1b[0m\n", buf.String())
+}
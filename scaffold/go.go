@@ -16,7 +16,12 @@ package scaffold
 
 import (
 	"fmt"
+	"go/format"
 	"strings"
+
+	"github.com/iancoleman/strcase"
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
 )
 
 var ErrUnknownScaffoldType = fmt.Errorf("unknown scaffold template")
@@ -30,3 +35,134 @@ func (*JSONToGo) HasTime(schema string) bool {
 func (*JSONToGo) HasUUID(schema string) bool {
 	return strings.Contains(schema, "uuid.UUID")
 }
+
+func goType(f *schema.Field, typeName string) string {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		if f.Format == tschema.FormatInt32 {
+			return "int32"
+		}
+
+		return "int64"
+	case tschema.TypeNumber:
+		return "float64"
+	case tschema.TypeBoolean:
+		return "bool"
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatDateTime:
+			return "time.Time"
+		case tschema.FormatUUID:
+			return "uuid.UUID"
+		case tschema.FormatByte:
+			return "[]byte"
+		}
+
+		return "string"
+	case tschema.TypeObject:
+		if typeName != "" {
+			return typeName
+		}
+
+		return "map[string]any"
+	case tschema.TypeArray:
+		if f.Format == tschema.FormatVector {
+			return fmt.Sprintf("[%d]float64", f.Dimensions)
+		}
+
+		if f.Items == nil {
+			return "[]any"
+		}
+
+		return "[]" + goType(f.Items, typeName)
+	}
+
+	return "any"
+}
+
+func goTags(f *modelField) string {
+	var tags []string
+
+	if f.PrimaryKey > 0 {
+		tags = append(tags, fmt.Sprintf("primaryKey:%d", f.PrimaryKey))
+	}
+
+	if f.Field.AutoGenerate {
+		tags = append(tags, "autoGenerate")
+	}
+
+	if f.Required {
+		tags = append(tags, "required")
+	}
+
+	if f.Field.MaxLength > 0 {
+		tags = append(tags, fmt.Sprintf("maxLength:%d", f.Field.MaxLength))
+	}
+
+	for _, v := range []struct {
+		set  bool
+		name string
+	}{
+		{f.Field.Format == tschema.FormatVector, "vector"},
+		{f.Field.Index, "index"},
+		{f.Field.SearchIndex, "searchIndex"},
+		{f.Field.Sort, "sort"},
+		{f.Field.Facet, "facet"},
+		{f.Field.CreatedAt, "createdAt"},
+		{f.Field.UpdatedAt, "updatedAt"},
+	} {
+		if v.set {
+			tags = append(tags, v.name)
+		}
+	}
+
+	if len(tags) == 0 {
+		return ""
+	}
+
+	return ` tigris:"` + strings.Join(tags, ",") + `"`
+}
+
+// Model generates Go structs with tigris tags.
+func (*JSONToGo) Model(sch *schema.Schema) (string, error) {
+	var b strings.Builder
+
+	for _, t := range modelTypes(sch) {
+		fmt.Fprintf(&b, "\ntype %s struct {\n", t.Name)
+
+		for _, f := range t.Fields {
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s\"%s`\n",
+				strcase.ToCamel(f.Name), goType(f.Field, f.TypeName), f.Name, goTags(f))
+		}
+
+		b.WriteString("}\n")
+	}
+
+	res, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", err
+	}
+
+	return "\n" + strings.TrimLeft(string(res), "\n"), nil
+}
+
+func (g *JSONToGo) ModelFile(pkg string, sch *schema.Schema, model string) (string, string) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "package %s\n", pkg)
+
+	hasTime, hasUUID := g.HasTime(model), g.HasUUID(model)
+
+	switch {
+	case hasTime && hasUUID:
+		b.WriteString("\nimport (\n\t\"time\"\n\n\t\"github.com/google/uuid\"\n)\n")
+	case hasTime:
+		b.WriteString("\nimport \"time\"\n")
+	case hasUUID:
+		b.WriteString("\nimport \"github.com/google/uuid\"\n")
+	}
+
+	b.WriteString(model)
+
+	return plural.Singular(sch.Name) + ".go", b.String()
+}
@@ -15,7 +15,12 @@
 package scaffold
 
 import (
+	"fmt"
 	"strings"
+
+	"github.com/iancoleman/strcase"
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
 )
 
 type JSONToJava struct{}
@@ -27,3 +32,207 @@ func (*JSONToJava) HasTime(schema string) bool {
 func (*JSONToJava) HasUUID(schema string) bool {
 	return strings.Contains(schema, "private UUID")
 }
+
+func javaType(f *schema.Field, typeName string) string {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		if f.Format == tschema.FormatInt32 {
+			return "int"
+		}
+
+		return "long"
+	case tschema.TypeNumber:
+		return "double"
+	case tschema.TypeBoolean:
+		return "boolean"
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatDateTime:
+			return "Date"
+		case tschema.FormatUUID:
+			return "UUID"
+		case tschema.FormatByte:
+			return "byte[]"
+		}
+
+		return "String"
+	case tschema.TypeObject:
+		if typeName != "" {
+			return typeName
+		}
+
+		return "Object"
+	case tschema.TypeArray:
+		if f.Items == nil {
+			return "Object[]"
+		}
+
+		return javaType(f.Items, typeName) + "[]"
+	}
+
+	return "Object"
+}
+
+func javaIsPrimitive(t string) bool {
+	switch t {
+	case "int", "long", "double", "boolean":
+		return true
+	}
+
+	return false
+}
+
+func javaAnnotation(f *modelField) string {
+	if f.PrimaryKey > 0 {
+		if f.Field.AutoGenerate {
+			return fmt.Sprintf("    @TigrisPrimaryKey(order = %d, autoGenerate = true)\n", f.PrimaryKey)
+		}
+
+		return fmt.Sprintf("    @TigrisPrimaryKey(order = %d)\n", f.PrimaryKey)
+	}
+
+	if f.Field.Desc != "" {
+		return fmt.Sprintf("    @TigrisField(description = %q)\n", f.Field.Desc)
+	}
+
+	return ""
+}
+
+func javaEquals(b *strings.Builder, t *modelType) {
+	fmt.Fprintf(b, "\n    @Override\n    public boolean equals(Object o) {\n")
+	fmt.Fprintf(b, "        if (this == o) {\n            return true;\n        }\n")
+	fmt.Fprintf(b, "        if (o == null || getClass() != o.getClass()) {\n            return false;\n        }\n\n")
+
+	if len(t.Fields) == 0 {
+		b.WriteString("        return true;\n    }\n")
+		return
+	}
+
+	fmt.Fprintf(b, "        %s other = (%s) o;\n        return", t.Name, t.Name)
+
+	for i, f := range t.Fields {
+		if i > 0 {
+			b.WriteString(" &&")
+		}
+
+		typ := javaType(f.Field, f.TypeName)
+
+		switch {
+		case strings.HasSuffix(typ, "[]"):
+			fmt.Fprintf(b, "\n            Arrays.equals(%[1]s, other.%[1]s)", f.Name)
+		case javaIsPrimitive(typ):
+			fmt.Fprintf(b, "\n            %[1]s == other.%[1]s", f.Name)
+		default:
+			fmt.Fprintf(b, "\n            Objects.equals(%[1]s, other.%[1]s)", f.Name)
+		}
+	}
+
+	b.WriteString(";\n    }\n")
+}
+
+func javaHashCode(b *strings.Builder, t *modelType) {
+	var fields, arrays []string
+
+	for _, f := range t.Fields {
+		if strings.HasSuffix(javaType(f.Field, f.TypeName), "[]") {
+			arrays = append(arrays, f.Name)
+		} else {
+			fields = append(fields, f.Name)
+		}
+	}
+
+	fmt.Fprintf(b, "\n    @Override\n    public int hashCode() {\n")
+	fmt.Fprintf(b, "        int result = Objects.hash(%s);\n", strings.Join(fields, ", "))
+
+	for _, v := range arrays {
+		fmt.Fprintf(b, "        result = 31 * result + Arrays.hashCode(%s);\n", v)
+	}
+
+	b.WriteString("        return result;\n    }\n")
+}
+
+func javaClass(b *strings.Builder, t *modelType, collection string, nested []*modelType) {
+	if collection != "" {
+		fmt.Fprintf(b, "@com.tigrisdata.db.annotation.TigrisCollection(value = %q)\n", collection)
+		fmt.Fprintf(b, "public class %s implements TigrisDocumentCollectionType {\n", t.Name)
+	} else {
+		fmt.Fprintf(b, "public static class %s {\n", t.Name)
+	}
+
+	for _, f := range t.Fields {
+		fmt.Fprintf(b, "%s    private %s %s;\n", javaAnnotation(f), javaType(f.Field, f.TypeName), f.Name)
+	}
+
+	for _, f := range t.Fields {
+		typ, name := javaType(f.Field, f.TypeName), strcase.ToCamel(f.Name)
+
+		fmt.Fprintf(b, "\n    public %s get%s() {\n        return %s;\n    }\n", typ, name, f.Name)
+		fmt.Fprintf(b, "\n    public void set%s(%s %s) {\n        this.%s = %s;\n    }\n",
+			name, typ, f.Name, f.Name, f.Name)
+	}
+
+	fmt.Fprintf(b, "\n    public %s() {};\n", t.Name)
+
+	javaEquals(b, t)
+	javaHashCode(b, t)
+
+	for _, v := range nested {
+		var nb strings.Builder
+
+		nb.WriteString("\n")
+		javaClass(&nb, v, "", nil)
+
+		// indent nested class
+		for _, l := range strings.SplitAfter(nb.String(), "\n") {
+			if strings.TrimSpace(l) != "" {
+				b.WriteString("    ")
+			}
+
+			b.WriteString(l)
+		}
+	}
+
+	b.WriteString("}\n")
+}
+
+// Model generates Java class with Tigris annotations.
+// Types of the nested objects are generated as static nested classes.
+func (*JSONToJava) Model(sch *schema.Schema) (string, error) {
+	var b strings.Builder
+
+	types := modelTypes(sch)
+
+	b.WriteString("\n")
+	javaClass(&b, types[len(types)-1], sch.Name, types[:len(types)-1])
+
+	return b.String(), nil
+}
+
+func (g *JSONToJava) ModelFile(pkg string, sch *schema.Schema, model string) (string, string) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "package %s;\n\n", pkg)
+
+	if strings.Contains(model, "@TigrisField") {
+		b.WriteString("import com.tigrisdata.db.annotation.TigrisField;\n")
+	}
+
+	if strings.Contains(model, "@TigrisPrimaryKey") {
+		b.WriteString("import com.tigrisdata.db.annotation.TigrisPrimaryKey;\n")
+	}
+
+	b.WriteString("import com.tigrisdata.db.type.TigrisDocumentCollectionType;\n")
+
+	if g.HasUUID(model) {
+		b.WriteString("import java.util.UUID;\n")
+	}
+
+	if g.HasTime(model) {
+		b.WriteString("import java.util.Date;\n")
+	}
+
+	b.WriteString("import java.util.Objects;\nimport java.util.Arrays;\n")
+	b.WriteString(model)
+
+	return modelName(sch) + ".java", b.String()
+}
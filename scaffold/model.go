@@ -0,0 +1,146 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/iancoleman/strcase"
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// modelField is the field of the generated class or struct.
+type modelField struct {
+	Name       string // field name in the schema
+	Field      *schema.Field
+	TypeName   string // name of the generated type of the object or array of objects
+	PrimaryKey int    // position in the primary key starting from 1, zero if the field is not a part of the key
+	Required   bool
+}
+
+// modelType is the generated class or struct.
+type modelType struct {
+	Name   string
+	Fields []*modelField
+}
+
+// arrayItems returns innermost items of the nested arrays.
+func arrayItems(f *schema.Field) *schema.Field {
+	for f != nil && f.Type.First() == tschema.TypeArray {
+		f = f.Items
+	}
+
+	return f
+}
+
+// typeNamer names the generated types of the nested objects.
+type typeNamer map[string]bool
+
+// name returns the unique name of the type of the field.
+// Types of the objects nested deeper than the top level fields are prefixed with the name of the parent type,
+// so billing.address and shipping.address produce BillingAddress and ShippingAddress.
+func (u typeNamer) name(parent string, field string) string {
+	name := parent + strcase.ToCamel(field)
+
+	res := name
+	for i := 2; u[res]; i++ {
+		res = name + strconv.Itoa(i)
+	}
+
+	u[res] = true
+
+	return res
+}
+
+func appendModelTypes(res []*modelType, names typeNamer, name string, prefix string,
+	fields map[string]*schema.Field, pk []string, required []string,
+) []*modelType {
+	t := &modelType{Name: name}
+
+	for _, n := range tschema.SortedFields(fields, pk) {
+		f := fields[n]
+
+		mf := &modelField{
+			Name: n, Field: f,
+			PrimaryKey: tschema.IndexOf(pk, n) + 1, Required: tschema.IndexOf(required, n) >= 0,
+		}
+
+		switch f.Type.First() {
+		case tschema.TypeObject:
+			if len(f.Fields) > 0 {
+				mf.TypeName = names.name(prefix, n)
+				res = appendModelTypes(res, names, mf.TypeName, mf.TypeName, f.Fields, nil, f.Required)
+			}
+		case tschema.TypeArray:
+			if items := arrayItems(f); items != nil && items.Type.First() == tschema.TypeObject && len(items.Fields) > 0 {
+				mf.TypeName = names.name(prefix, plural.Singular(n))
+				res = appendModelTypes(res, names, mf.TypeName, mf.TypeName, items.Fields, nil, items.Required)
+			}
+		}
+
+		t.Fields = append(t.Fields, mf)
+	}
+
+	return append(res, t)
+}
+
+// modelName returns the name of the generated type of the collection.
+func modelName(sch *schema.Schema) string {
+	return strcase.ToCamel(plural.Singular(sch.Name))
+}
+
+// modelTypes flattens collection schema into the list of types.
+// Types of the nested objects precede the types which reference them,
+// the collection type is the last one.
+func modelTypes(sch *schema.Schema) []*modelType {
+	name := modelName(sch)
+
+	return appendModelTypes(nil, typeNamer{name: true}, name, "", sch.Fields, sch.PrimaryKey, sch.Required)
+}
+
+// SortedSchemas returns schemas ordered by collection name.
+func SortedSchemas(schemas map[string]*schema.Schema) []*schema.Schema {
+	res := make([]*schema.Schema, 0, len(schemas))
+	for _, v := range schemas {
+		res = append(res, v)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+// Model generates language types from the collection schema locally,
+// in the format of the language schemas returned by the server.
+func Model(lang string, sch *schema.Schema) (string, error) {
+	return getGenerator(lang).Model(sch)
+}
+
+// ModelFile generates the source file of the package with the types of the collection.
+// Returns the name and the content of the file.
+func ModelFile(lang string, pkg string, sch *schema.Schema) (string, string, error) {
+	gen := getGenerator(lang)
+
+	model, err := gen.Model(sch)
+	if err != nil {
+		return "", "", err
+	}
+
+	name, body := gen.ModelFile(pkg, sch, model)
+
+	return name, body, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//nolint:funlen
+package scaffold
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var testModelSchema = `{ "title": "users", "properties": {
+	"name": { "type": "string", "maxLength": 64 },
+	"id": { "type": "integer", "autoGenerate": true },
+	"created": { "type": "string", "format": "date-time" },
+	"address": { "type": "object", "properties": { "city": { "type": "string" } } },
+	"tags": { "type": "array", "items": { "type": "string" } }
+}, "primary_key": ["id"] }`
+
+func TestModel(t *testing.T) {
+	cases := []struct {
+		lang    string
+		model   string
+		file    string
+		hasTime bool
+	}{
+		{
+			"go", `
+type Address struct {
+	City string ` + "`json:\"city\"`" + `
+}
+
+type User struct {
+	Id      int64     ` + "`json:\"id\" tigris:\"primaryKey:1,autoGenerate\"`" + `
+	Address Address   ` + "`json:\"address\"`" + `
+	Created time.Time ` + "`json:\"created\"`" + `
+	Name    string    ` + "`json:\"name\" tigris:\"maxLength:64\"`" + `
+	Tags    []string  ` + "`json:\"tags\"`" + `
+}
+`, "user.go", true,
+		},
+		{
+			"ts", `
+export class Address {
+  @Field()
+  city: string;
+}
+
+@TigrisCollection("users")
+export class User {
+  @PrimaryKey(TigrisDataTypes.INT64, { order: 1, autoGenerate: true })
+  id?: string;
+
+  @Field()
+  address: Address;
+
+  @Field(TigrisDataTypes.DATE_TIME)
+  created: string;
+
+  @Field({ maxLength: 64 })
+  name: string;
+
+  @Field({ elements: TigrisDataTypes.STRING })
+  tags: Array<string>;
+}
+`, "user.ts", true,
+		},
+		{
+			"python", `
+
+class Address(BaseModel):
+    city: str
+
+
+class User(BaseModel):
+    id: Optional[int] = None
+    address: Address
+    created: datetime
+    name: str = Field(max_length=64)
+    tags: List[str]
+`, "user.py", true,
+		},
+		{
+			"rust", `
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Address {
+    pub city: String,
+}
+
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct User {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub id: Option<i64>,
+    pub address: Address,
+    pub created: DateTime<Utc>,
+    pub name: String,
+    pub tags: Vec<String>,
+}
+`, "user.rs", true,
+		},
+	}
+
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(testModelSchema), &sch))
+
+	for _, c := range cases {
+		t.Run(c.lang, func(t *testing.T) {
+			model, err := Model(c.lang, &sch)
+			require.NoError(t, err)
+			assert.Equal(t, c.model, model)
+
+			gen := getGenerator(c.lang)
+			assert.Equal(t, c.hasTime, gen.HasTime(model))
+			assert.False(t, gen.HasUUID(model))
+
+			name, body, err := ModelFile(c.lang, "model", &sch)
+			require.NoError(t, err)
+			assert.Equal(t, c.file, name)
+			assert.Contains(t, body, model)
+		})
+	}
+
+	t.Run("java", func(t *testing.T) {
+		name, body, err := ModelFile("java", "com.example.collections", &sch)
+		require.NoError(t, err)
+		assert.Equal(t, "User.java", name)
+
+		assert.Contains(t, body, "package com.example.collections;\n")
+		assert.Contains(t, body, "import java.util.Date;\n")
+		assert.Contains(t, body, `@com.tigrisdata.db.annotation.TigrisCollection(value = "users")`)
+		assert.Contains(t, body, "    @TigrisPrimaryKey(order = 1, autoGenerate = true)\n    private long id;\n")
+		assert.Contains(t, body, "    private Date created;\n")
+		assert.Contains(t, body, "    private String[] tags;\n")
+		assert.Contains(t, body, "    public static class Address {\n        private String city;\n")
+		assert.True(t, (&JSONToJava{}).HasTime(body))
+	})
+}
+
+func TestModelNestedTypeNames(t *testing.T) {
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(`{ "title": "orders", "properties": {
+		"id": { "type": "integer" },
+		"billing": { "type": "object", "properties": {
+			"address": { "type": "object", "properties": { "city": { "type": "string" } } } } },
+		"shipping": { "type": "object", "properties": {
+			"address": { "type": "object", "properties": { "zip": { "type": "string" } } } } },
+		"order": { "type": "object", "properties": { "note": { "type": "string" } } }
+	}, "primary_key": ["id"] }`), &sch))
+
+	names := make([]string, 0)
+	for _, v := range modelTypes(&sch) {
+		names = append(names, v.Name)
+	}
+
+	assert.Equal(t, []string{"BillingAddress", "Billing", "Order2", "ShippingAddress", "Shipping", "Order"}, names)
+
+	model, err := Model("go", &sch)
+	require.NoError(t, err)
+	assert.Contains(t, model, "type BillingAddress struct {\n\tCity string")
+	assert.Contains(t, model, "type ShippingAddress struct {\n\tZip string")
+	assert.Contains(t, model, "\tAddress BillingAddress `json:\"address\"`\n")
+	assert.Contains(t, model, "\tOrder    Order2   `json:\"order\"`\n")
+}
+
+func TestPythonImports(t *testing.T) {
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(testModelSchema), &sch))
+
+	_, body, err := ModelFile("python", "model", &sch)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(body, "from datetime import datetime\nfrom typing import List, Optional\n\n"+
+		"from pydantic import BaseModel, Field\n\n\nclass"), body)
+
+	g := &JSONToPython{}
+	assert.Equal(t, "from pydantic import BaseModel\n", g.Imports("\n\nclass User(BaseModel):\n    name: str\n"))
+	assert.Equal(t, "from typing import Any, Dict\n\nfrom pydantic import BaseModel\n",
+		g.Imports("\n\nclass User(BaseModel):\n    a: Any\n    m: Dict[str, Any]\n"))
+}
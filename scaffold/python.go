@@ -0,0 +1,173 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+type JSONToPython struct{}
+
+func (*JSONToPython) HasTime(schema string) bool {
+	return strings.Contains(schema, "datetime")
+}
+
+func (*JSONToPython) HasUUID(schema string) bool {
+	return strings.Contains(schema, "UUID")
+}
+
+func pythonType(f *schema.Field, typeName string) string {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		return "int"
+	case tschema.TypeNumber:
+		return "float"
+	case tschema.TypeBoolean:
+		return "bool"
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatDateTime:
+			return "datetime"
+		case tschema.FormatUUID:
+			return "UUID"
+		case tschema.FormatByte:
+			return "bytes"
+		}
+
+		return "str"
+	case tschema.TypeObject:
+		if typeName != "" {
+			return typeName
+		}
+
+		return "Dict[str, Any]"
+	case tschema.TypeArray:
+		if f.Format == tschema.FormatVector {
+			return "List[float]"
+		}
+
+		if f.Items == nil {
+			return "List[Any]"
+		}
+
+		return "List[" + pythonType(f.Items, typeName) + "]"
+	}
+
+	return "Any"
+}
+
+// pythonField returns type annotation and default value of the field.
+// Autogenerated fields are optional, as they are populated by the server.
+func pythonField(f *modelField) (string, string) {
+	typ := pythonType(f.Field, f.TypeName)
+
+	var args []string
+
+	if f.Field.AutoGenerate {
+		typ = "Optional[" + typ + "]"
+
+		args = append(args, "default=None")
+	}
+
+	if f.Field.MaxLength > 0 {
+		args = append(args, fmt.Sprintf("max_length=%d", f.Field.MaxLength))
+	}
+
+	switch {
+	case len(args) == 1 && f.Field.AutoGenerate:
+		return typ, " = None"
+	case len(args) > 0:
+		return typ, " = Field(" + strings.Join(args, ", ") + ")"
+	}
+
+	return typ, ""
+}
+
+// Model generates pydantic models.
+func (*JSONToPython) Model(sch *schema.Schema) (string, error) {
+	var b strings.Builder
+
+	for _, t := range modelTypes(sch) {
+		fmt.Fprintf(&b, "\n\nclass %s(BaseModel):\n", t.Name)
+
+		if len(t.Fields) == 0 {
+			b.WriteString("    pass\n")
+		}
+
+		for _, f := range t.Fields {
+			typ, def := pythonField(f)
+			fmt.Fprintf(&b, "    %s: %s%s\n", f.Name, typ, def)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// pythonTypingNames are the names of the typing module which the generated models can use.
+var pythonTypingNames = []string{"Any", "Dict", "List", "Optional"}
+
+// pythonTypingUse matches the names of the typing module in the type annotations.
+var pythonTypingUse = regexp.MustCompile(`[:\[,] ?(Any|Dict|List|Optional)\b`)
+
+// Imports returns the imports of the names used by the model.
+func (g *JSONToPython) Imports(model string) string {
+	var b strings.Builder
+
+	if g.HasTime(model) {
+		b.WriteString("from datetime import datetime\n")
+	}
+
+	used := make(map[string]bool)
+	for _, m := range pythonTypingUse.FindAllStringSubmatch(model, -1) {
+		used[m[1]] = true
+	}
+
+	var typing []string
+
+	for _, n := range pythonTypingNames {
+		if used[n] {
+			typing = append(typing, n)
+		}
+	}
+
+	if len(typing) > 0 {
+		b.WriteString("from typing import " + strings.Join(typing, ", ") + "\n")
+	}
+
+	if g.HasUUID(model) {
+		b.WriteString("from uuid import UUID\n")
+	}
+
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+
+	if strings.Contains(model, " = Field(") {
+		b.WriteString("from pydantic import BaseModel, Field\n")
+	} else {
+		b.WriteString("from pydantic import BaseModel\n")
+	}
+
+	return b.String()
+}
+
+func (g *JSONToPython) ModelFile(_ string, sch *schema.Schema, model string) (string, string) {
+	return plural.Singular(sch.Name) + ".py", g.Imports(model) + model
+}
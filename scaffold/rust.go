@@ -0,0 +1,146 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+type JSONToRust struct{}
+
+func (*JSONToRust) HasTime(schema string) bool {
+	return strings.Contains(schema, "DateTime<Utc>")
+}
+
+func (*JSONToRust) HasUUID(schema string) bool {
+	return strings.Contains(schema, "Uuid")
+}
+
+var rustKeywords = map[string]bool{
+	"as": true, "async": true, "await": true, "break": true, "const": true, "continue": true, "crate": true,
+	"dyn": true, "else": true, "enum": true, "extern": true, "false": true, "fn": true, "for": true, "if": true,
+	"impl": true, "in": true, "let": true, "loop": true, "match": true, "mod": true, "move": true, "mut": true,
+	"pub": true, "ref": true, "return": true, "static": true, "struct": true, "trait": true, "true": true,
+	"type": true, "unsafe": true, "use": true, "where": true, "while": true,
+}
+
+func rustType(f *schema.Field, typeName string) string {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		if f.Format == tschema.FormatInt32 {
+			return "i32"
+		}
+
+		return "i64"
+	case tschema.TypeNumber:
+		return "f64"
+	case tschema.TypeBoolean:
+		return "bool"
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatDateTime:
+			return "DateTime<Utc>"
+		case tschema.FormatUUID:
+			return "Uuid"
+		}
+
+		// Byte strings are base64 encoded in JSON
+		return "String"
+	case tschema.TypeObject:
+		if typeName != "" {
+			return typeName
+		}
+
+		return "serde_json::Value"
+	case tschema.TypeArray:
+		if f.Format == tschema.FormatVector {
+			return "Vec<f64>"
+		}
+
+		if f.Items == nil {
+			return "Vec<serde_json::Value>"
+		}
+
+		return "Vec<" + rustType(f.Items, typeName) + ">"
+	}
+
+	return "serde_json::Value"
+}
+
+// rustFieldName returns snake case field name and serde attribute
+// to preserve original name of the field in JSON.
+func rustFieldName(name string) (string, string) {
+	n := strcase.ToSnake(name)
+
+	var attr string
+	if n != name {
+		attr = fmt.Sprintf("    #[serde(rename = %q)]\n", name)
+	}
+
+	if rustKeywords[n] {
+		n = "r#" + n
+	}
+
+	return n, attr
+}
+
+// Model generates serde structs.
+func (*JSONToRust) Model(sch *schema.Schema) (string, error) {
+	var b strings.Builder
+
+	for _, t := range modelTypes(sch) {
+		fmt.Fprintf(&b, "\n#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]\npub struct %s {\n", t.Name)
+
+		for _, f := range t.Fields {
+			name, attr := rustFieldName(f.Name)
+			typ := rustType(f.Field, f.TypeName)
+
+			// Autogenerated fields are populated by the server
+			if f.Field.AutoGenerate {
+				typ = "Option<" + typ + ">"
+				attr += "    #[serde(skip_serializing_if = \"Option::is_none\")]\n"
+			}
+
+			fmt.Fprintf(&b, "%s    pub %s: %s,\n", attr, name, typ)
+		}
+
+		b.WriteString("}\n")
+	}
+
+	return b.String(), nil
+}
+
+func (g *JSONToRust) ModelFile(_ string, sch *schema.Schema, model string) (string, string) {
+	var b strings.Builder
+
+	if g.HasTime(model) {
+		b.WriteString("use chrono::{DateTime, Utc};\n")
+	}
+
+	b.WriteString("use serde::{Deserialize, Serialize};\n")
+
+	if g.HasUUID(model) {
+		b.WriteString("use uuid::Uuid;\n")
+	}
+
+	b.WriteString(model)
+
+	return strcase.ToSnake(plural.Singular(sch.Name)) + ".rs", b.String()
+}
@@ -42,7 +42,7 @@ import (
 )
 
 var (
-	ErrUnsupportedFormat    = fmt.Errorf("unsupported language. supported are: TypeScript, Go, Java")
+	ErrUnsupportedFormat    = fmt.Errorf("unsupported language. supported are: TypeScript, Go, Java, Python, Rust")
 	ErrTemplatesInvalidPath = fmt.Errorf("only local templates path substitution is allowed")
 
 	templatesRepoURL = "https://github.com/tigrisdata/tigris-templates"
@@ -84,6 +84,12 @@ type TmplVars struct {
 type JSONToLangType interface {
 	HasTime(string) bool
 	HasUUID(string) bool
+
+	// Model generates language types from the collection schema
+	Model(sch *schema.Schema) (string, error)
+	// ModelFile wraps the generated model into the source file of the package.
+	// Returns the name and the content of the file
+	ModelFile(pkg string, sch *schema.Schema, model string) (string, string)
 }
 
 type Collection struct {
@@ -102,6 +108,8 @@ type Collection struct {
 
 	HasTime bool
 	HasUUID bool
+
+	Imports string // imports of the names used by the model, for the languages which need them
 }
 
 func ensureLocalTemplates(base string, lang string, envVar string, repoURL string) string {
@@ -150,6 +158,10 @@ func getGenerator(lang string) JSONToLangType {
 		genType = &JSONToTypeScript{}
 	case "java":
 		genType = &JSONToJava{}
+	case "py", "python":
+		genType = &JSONToPython{}
+	case "rs", "rust":
+		genType = &JSONToRust{}
 	default:
 		util.Fatal(ErrUnsupportedFormat, "")
 	}
@@ -157,17 +169,23 @@ func getGenerator(lang string) JSONToLangType {
 	return genType
 }
 
+// SchemaFormat returns schema formats to request from the server for the language.
+// Models of the languages not supported by the server are generated locally from the JSON schema.
+func SchemaFormat(lang string) string {
+	switch lang {
+	case "go", "ts", "java":
+		return lang + ",json"
+	}
+
+	return "json"
+}
+
 func decodeSchemas(inSchema []byte, lang string) (string, *schema.Schema, string) {
 	schemas := make(map[string]string)
 
 	err := json.Unmarshal(inSchema, &schemas)
 	util.Fatal(err, "unmarshal schema")
 
-	s, ok := schemas[lang]
-	if !ok {
-		util.Fatal(ErrUnsupportedFormat, "schema not found for %v", lang)
-	}
-
 	if schemas["json"] == "" {
 		util.Fatal(ErrUnsupportedFormat, "json schema not found")
 	}
@@ -177,6 +195,16 @@ func decodeSchemas(inSchema []byte, lang string) (string, *schema.Schema, string
 	err = json.Unmarshal([]byte(schemas["json"]), &js)
 	util.Fatal(err, "unmarshalling json schema")
 
+	s, ok := schemas[lang]
+	if !ok {
+		if SchemaFormat(lang) != "json" {
+			util.Fatal(ErrUnsupportedFormat, "schema not found for %v", lang)
+		}
+
+		s, err = Model(lang, &js)
+		util.Fatal(err, "generate %v model", lang)
+	}
+
 	return s, &js, schemas["json"]
 }
 
@@ -210,9 +238,20 @@ func writeCollection(_ *TmplVars, w *bufio.Writer, collection *api.CollectionDes
 
 		HasUUID: genType.HasUUID(s),
 		HasTime: genType.HasTime(s),
+
+		Imports: modelImports(genType, s),
 	}
 }
 
+// modelImports returns the imports of the model if the generator derives them from the model.
+func modelImports(genType JSONToLangType, model string) string {
+	if g, ok := genType.(interface{ Imports(model string) string }); ok {
+		return g.Imports(model)
+	}
+
+	return ""
+}
+
 func substCollectionFn(fn string, c *Collection) string {
 	name := strings.ReplaceAll(fn, "_collection_name_", c.JSONSingular)
 	name = strings.ReplaceAll(name, "_collection_names_", c.JSON)
@@ -15,7 +15,11 @@
 package scaffold
 
 import (
+	"fmt"
 	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
 )
 
 type JSONToTypeScript struct{}
@@ -27,3 +31,182 @@ func (*JSONToTypeScript) HasTime(schema string) bool {
 func (*JSONToTypeScript) HasUUID(schema string) bool {
 	return strings.Contains(schema, "UUID")
 }
+
+// tsType returns TypeScript type and Tigris data type of the field.
+// Data type is empty if it can be inferred from the TypeScript type.
+func tsType(f *schema.Field, typeName string) (string, string) {
+	switch f.Type.First() {
+	case tschema.TypeInteger:
+		if f.Format == tschema.FormatInt32 {
+			return "number", "TigrisDataTypes.INT32"
+		}
+
+		return "string", "TigrisDataTypes.INT64"
+	case tschema.TypeNumber:
+		return "number", "TigrisDataTypes.NUMBER"
+	case tschema.TypeBoolean:
+		return "boolean", ""
+	case tschema.TypeString:
+		switch f.Format {
+		case tschema.FormatDateTime:
+			return "string", "TigrisDataTypes.DATE_TIME"
+		case tschema.FormatUUID:
+			return "string", "TigrisDataTypes.UUID"
+		case tschema.FormatByte:
+			return "string", "TigrisDataTypes.BYTE_STRING"
+		}
+
+		return "string", ""
+	case tschema.TypeObject:
+		if typeName != "" {
+			return typeName, ""
+		}
+
+		return "object", "TigrisDataTypes.OBJECT"
+	case tschema.TypeArray:
+		if f.Items == nil {
+			return "Array<object>", "TigrisDataTypes.ARRAY"
+		}
+
+		t, _ := tsType(f.Items, typeName)
+
+		return "Array<" + t + ">", "TigrisDataTypes.ARRAY"
+	}
+
+	return "object", "TigrisDataTypes.OBJECT"
+}
+
+// tsArrayOptions returns elements type and the depth of the nested arrays.
+func tsArrayOptions(f *modelField) string {
+	depth := 0
+
+	items := f.Field
+	for items != nil && items.Type.First() == tschema.TypeArray {
+		items = items.Items
+		depth++
+	}
+
+	elements := "TigrisDataTypes.OBJECT"
+	if items != nil {
+		t, dt := tsType(items, f.TypeName)
+
+		switch {
+		case dt != "":
+			elements = dt
+		case t == "string":
+			elements = "TigrisDataTypes.STRING"
+		case t == "boolean":
+			elements = "TigrisDataTypes.BOOLEAN"
+		default:
+			elements = t
+		}
+	}
+
+	if depth > 1 {
+		return fmt.Sprintf("elements: %s, depth: %d", elements, depth)
+	}
+
+	return "elements: " + elements
+}
+
+func tsDecorator(f *modelField) string {
+	t, dt := tsType(f.Field, f.TypeName)
+
+	var opts []string
+
+	switch {
+	case f.Field.Format == tschema.FormatVector:
+		return fmt.Sprintf("@VectorField(%d)", f.Field.Dimensions)
+	case f.Field.Type.First() == tschema.TypeArray:
+		opts = append(opts, tsArrayOptions(f))
+		dt = ""
+	case f.PrimaryKey > 0 && dt == "":
+		dt = "TigrisDataTypes.STRING"
+		if t == "boolean" {
+			dt = "TigrisDataTypes.BOOLEAN"
+		}
+	}
+
+	if f.PrimaryKey > 0 {
+		opts = append(opts, fmt.Sprintf("order: %d", f.PrimaryKey))
+	}
+
+	if f.Field.AutoGenerate {
+		opts = append(opts, "autoGenerate: true")
+	}
+
+	if f.Field.MaxLength > 0 {
+		opts = append(opts, fmt.Sprintf("maxLength: %d", f.Field.MaxLength))
+	}
+
+	var args []string
+
+	if dt != "" {
+		args = append(args, dt)
+	}
+
+	if len(opts) > 0 {
+		args = append(args, "{ "+strings.Join(opts, ", ")+" }")
+	}
+
+	name := "Field"
+	if f.PrimaryKey > 0 {
+		name = "PrimaryKey"
+	}
+
+	return "@" + name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Model generates TypeScript classes with Tigris decorators.
+func (*JSONToTypeScript) Model(sch *schema.Schema) (string, error) {
+	var b strings.Builder
+
+	types := modelTypes(sch)
+
+	for i, t := range types {
+		b.WriteString("\n")
+
+		if i == len(types)-1 {
+			fmt.Fprintf(&b, "@TigrisCollection(%q)\n", sch.Name)
+		}
+
+		fmt.Fprintf(&b, "export class %s {\n", t.Name)
+
+		for j, f := range t.Fields {
+			if j > 0 {
+				b.WriteString("\n")
+			}
+
+			typ, _ := tsType(f.Field, f.TypeName)
+
+			optional := ""
+			if f.Field.AutoGenerate {
+				optional = "?"
+			}
+
+			fmt.Fprintf(&b, "  %s\n  %s%s: %s;\n", tsDecorator(f), f.Name, optional, typ)
+		}
+
+		b.WriteString("}\n")
+	}
+
+	return b.String(), nil
+}
+
+func (*JSONToTypeScript) ModelFile(_ string, sch *schema.Schema, model string) (string, string) {
+	imports := []string{"Field"}
+
+	if strings.Contains(model, "@PrimaryKey") {
+		imports = append(imports, "PrimaryKey")
+	}
+
+	imports = append(imports, "TigrisCollection", "TigrisDataTypes")
+
+	if strings.Contains(model, "@VectorField") {
+		imports = append(imports, "VectorField")
+	}
+
+	body := fmt.Sprintf("import {\n  %s,\n} from \"@tigrisdata/core\";\n%s", strings.Join(imports, ",\n  "), model)
+
+	return plural.Singular(sch.Name) + ".ts", body
+}
@@ -0,0 +1,299 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+type ChangeKind string
+
+// Kinds of the schema changes.
+const (
+	CollectionAdded   ChangeKind = "collection_added"
+	CollectionRemoved ChangeKind = "collection_removed"
+	FieldAdded        ChangeKind = "field_added"
+	FieldRemoved      ChangeKind = "field_removed"
+	TypeChanged       ChangeKind = "type_changed"
+	FormatChanged     ChangeKind = "format_changed"
+	PrimaryKeyChanged ChangeKind = "primary_key_changed"
+	IndexChanged      ChangeKind = "index_changed"
+)
+
+type Severity string
+
+// Severity of the schema change for the existing data.
+const (
+	Safe             Severity = "safe"
+	RequiresBackfill Severity = "requires-backfill" // existing documents need to be rewritten or reindexed
+	Breaking         Severity = "breaking"
+)
+
+// ArrayItems is appended to the path of array items fields.
+const ArrayItems = "[]"
+
+// Change is a single difference between two schemas.
+type Change struct {
+	Collection string     `json:"collection"`
+	Field      string     `json:"field,omitempty"`
+	Kind       ChangeKind `json:"kind"`
+	Old        string     `json:"old,omitempty"`
+	New        string     `json:"new,omitempty"`
+	Severity   Severity   `json:"severity"`
+}
+
+func (c *Change) IsBreaking() bool {
+	return c.Severity == Breaking
+}
+
+func (c *Change) String() string {
+	switch c.Kind {
+	case CollectionAdded:
+		return "+ collection"
+	case CollectionRemoved:
+		return "- collection"
+	case FieldAdded:
+		return fmt.Sprintf("+ field %s (%s)", c.Field, c.New)
+	case FieldRemoved:
+		return fmt.Sprintf("- field %s (%s)", c.Field, c.Old)
+	case PrimaryKeyChanged:
+		return fmt.Sprintf("~ primary key: [%s] -> [%s]", c.Old, c.New)
+	}
+
+	what := strings.TrimSuffix(string(c.Kind), "_changed")
+
+	return fmt.Sprintf("~ field %s %s: '%s' -> '%s'", c.Field, what, c.Old, c.New)
+}
+
+func fieldType(f *schema.Field) string {
+	s := f.Type.First()
+	if f.Format != "" {
+		s += ":" + f.Format
+	}
+
+	return s
+}
+
+func fieldFormat(f *schema.Field) string {
+	if f.Format == formatVector {
+		return fmt.Sprintf("%s(%d)", f.Format, f.Dimensions)
+	}
+
+	return f.Format
+}
+
+func fieldIndexes(f *schema.Field) string {
+	var l []string
+
+	if f.Index {
+		l = append(l, "index")
+	}
+
+	if f.SearchIndex {
+		l = append(l, "searchIndex")
+	}
+
+	if f.Sort {
+		l = append(l, "sort")
+	}
+
+	if f.Facet {
+		l = append(l, "facet")
+	}
+
+	return strings.Join(l, ",")
+}
+
+// isWidening returns true if the values of the old type can be stored in the new type.
+func isWidening(name string, oldField *schema.Field, newField *schema.Field) bool {
+	t, f, err := extendedType(name, oldField.Type.First(), oldField.Format, newField.Type.First(), newField.Format)
+
+	return err == nil && t == newField.Type.First() && f == newField.Format
+}
+
+// typeChangeSeverity classifies type and format changes using the inference extension rules.
+// The server doesn't convert existing values, so even compatible changes require rewriting the documents.
+func typeChangeSeverity(name string, oldField *schema.Field, newField *schema.Field) Severity {
+	if oldField.Format == formatVector || newField.Format == formatVector || !isWidening(name, oldField, newField) {
+		return Breaking
+	}
+
+	return RequiresBackfill
+}
+
+// indexChangeSeverity requires reindexing of the existing documents when indexes are added.
+func indexChangeSeverity(oldField *schema.Field, newField *schema.Field) Severity {
+	if newField.Index && !oldField.Index || newField.SearchIndex && !oldField.SearchIndex ||
+		newField.Sort && !oldField.Sort || newField.Facet && !oldField.Facet {
+		return RequiresBackfill
+	}
+
+	return Safe
+}
+
+func diffField(coll string, path string, oldField *schema.Field, newField *schema.Field) []*Change {
+	var res []*Change
+
+	switch {
+	case oldField.Type.First() != newField.Type.First():
+		res = append(res, &Change{
+			Collection: coll, Field: path, Kind: TypeChanged,
+			Old: fieldType(oldField), New: fieldType(newField),
+			Severity: typeChangeSeverity(path, oldField, newField),
+		})
+	case fieldFormat(oldField) != fieldFormat(newField):
+		res = append(res, &Change{
+			Collection: coll, Field: path, Kind: FormatChanged,
+			Old: fieldFormat(oldField), New: fieldFormat(newField),
+			Severity: typeChangeSeverity(path, oldField, newField),
+		})
+	case oldField.Type.First() == typeObject:
+		res = append(res, diffFields(coll, path, oldField.Fields, newField.Fields, newField.Required)...)
+	case oldField.Type.First() == typeArray && oldField.Items != nil && newField.Items != nil:
+		res = append(res, diffField(coll, path+ArrayItems, oldField.Items, newField.Items)...)
+	}
+
+	if fieldIndexes(oldField) != fieldIndexes(newField) {
+		res = append(res, &Change{
+			Collection: coll, Field: path, Kind: IndexChanged,
+			Old: fieldIndexes(oldField), New: fieldIndexes(newField),
+			Severity: indexChangeSeverity(oldField, newField),
+		})
+	}
+
+	return res
+}
+
+func sortedNames(fields ...map[string]*schema.Field) []string {
+	m := make(map[string]bool)
+
+	for _, f := range fields {
+		for k := range f {
+			m[k] = true
+		}
+	}
+
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// diffFields compares fields of the objects.
+// Adding a field which is required by the new object requires backfill of the existing documents.
+func diffFields(coll string, prefix string, oldFields map[string]*schema.Field, newFields map[string]*schema.Field,
+	required []string,
+) []*Change {
+	var res []*Change
+
+	for _, name := range sortedNames(oldFields, newFields) {
+		path := JoinPath(prefix, name)
+
+		o, n := oldFields[name], newFields[name]
+
+		switch {
+		case o == nil:
+			sev := Safe
+			if n.Default == nil && util.Contains(required, name) {
+				sev = RequiresBackfill
+			}
+
+			res = append(res, &Change{Collection: coll, Field: path, Kind: FieldAdded, New: fieldType(n), Severity: sev})
+		case n == nil:
+			res = append(res, &Change{
+				Collection: coll, Field: path, Kind: FieldRemoved, Old: fieldType(o), Severity: Breaking,
+			})
+		default:
+			res = append(res, diffField(coll, path, o, n)...)
+		}
+	}
+
+	return res
+}
+
+// Diff returns field level difference between old and new schemas of the collection.
+func Diff(oldSch *schema.Schema, newSch *schema.Schema) []*Change {
+	coll := newSch.Name
+	if coll == "" {
+		coll = oldSch.Name
+	}
+
+	res := diffFields(coll, "", oldSch.Fields, newSch.Fields, newSch.Required)
+
+	if strings.Join(oldSch.PrimaryKey, ",") != strings.Join(newSch.PrimaryKey, ",") {
+		res = append(res, &Change{
+			Collection: coll, Kind: PrimaryKeyChanged,
+			Old: strings.Join(oldSch.PrimaryKey, ", "), New: strings.Join(newSch.PrimaryKey, ", "),
+			Severity: Breaking,
+		})
+	}
+
+	return res
+}
+
+// DiffCollections returns difference between two sets of collection schemas.
+// The sets are keyed by collection name.
+func DiffCollections(oldColls map[string]*schema.Schema, newColls map[string]*schema.Schema) []*Change {
+	names := make([]string, 0, len(oldColls)+len(newColls))
+
+	for k := range oldColls {
+		names = append(names, k)
+	}
+
+	for k := range newColls {
+		if oldColls[k] == nil {
+			names = append(names, k)
+		}
+	}
+
+	sort.Strings(names)
+
+	var res []*Change
+
+	for _, name := range names {
+		o, n := oldColls[name], newColls[name]
+
+		switch {
+		case o == nil:
+			res = append(res, &Change{Collection: name, Kind: CollectionAdded, Severity: Safe})
+		case n == nil:
+			res = append(res, &Change{Collection: name, Kind: CollectionRemoved, Severity: Breaking})
+		default:
+			res = append(res, Diff(o, n)...)
+		}
+	}
+
+	return res
+}
+
+// HasBreaking returns true if any of the changes is breaking.
+func HasBreaking(changes []*Change) bool {
+	for _, c := range changes {
+		if c.IsBreaking() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,140 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//nolint:golint,funlen
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	cases := []struct {
+		name   string
+		oldSch string
+		newSch string
+		exp    []*Change
+	}{
+		{
+			name:   "no_changes",
+			oldSch: `{ "title": "c1", "properties": { "id": { "type": "integer" } }, "primary_key": ["id"] }`,
+			newSch: `{ "title": "c1", "properties": { "id": { "type": "integer" } }, "primary_key": ["id"] }`,
+		},
+		{
+			name:   "add_remove",
+			oldSch: `{ "title": "c1", "properties": { "f1": { "type": "integer" } } }`,
+			newSch: `{ "title": "c1", "properties": { "f2": { "type": "string", "format": "uuid" } } }`,
+			exp: []*Change{
+				{Collection: "c1", Field: "f1", Kind: FieldRemoved, Old: "integer", Severity: Breaking},
+				{Collection: "c1", Field: "f2", Kind: FieldAdded, New: "string:uuid", Severity: Safe},
+			},
+		},
+		{
+			name: "types",
+			oldSch: `{ "title": "c1", "properties": {
+				"widen": { "type": "integer" },
+				"narrow": { "type": "number" },
+				"incompatible": { "type": "string" },
+				"str_widen": { "type": "string", "format": "uuid" },
+				"str_narrow": { "type": "string" },
+				"vec": { "type": "array", "format": "vector", "dimensions": 3, "items": { "type": "number" } }
+			} }`,
+			newSch: `{ "title": "c1", "properties": {
+				"widen": { "type": "number" },
+				"narrow": { "type": "integer" },
+				"incompatible": { "type": "boolean" },
+				"str_widen": { "type": "string" },
+				"str_narrow": { "type": "string", "format": "date-time" },
+				"vec": { "type": "array", "format": "vector", "dimensions": 4, "items": { "type": "number" } }
+			} }`,
+			exp: []*Change{
+				{Collection: "c1", Field: "incompatible", Kind: TypeChanged, Old: "string", New: "boolean", Severity: Breaking},
+				{Collection: "c1", Field: "narrow", Kind: TypeChanged, Old: "number", New: "integer", Severity: Breaking},
+				{Collection: "c1", Field: "str_narrow", Kind: FormatChanged, Old: "", New: "date-time", Severity: Breaking},
+				{Collection: "c1", Field: "str_widen", Kind: FormatChanged, Old: "uuid", New: "", Severity: RequiresBackfill},
+				{Collection: "c1", Field: "vec", Kind: FormatChanged, Old: "vector(3)", New: "vector(4)", Severity: Breaking},
+				{Collection: "c1", Field: "widen", Kind: TypeChanged, Old: "integer", New: "number", Severity: RequiresBackfill},
+			},
+		},
+		{
+			name: "nested",
+			oldSch: `{ "title": "c1", "properties": {
+				"obj": { "type": "object", "properties": { "f1": { "type": "integer" } } },
+				"arr": { "type": "array", "items": { "type": "object", "properties": { "f1": { "type": "integer" } } } }
+			} }`,
+			newSch: `{ "title": "c1", "properties": {
+				"obj": { "type": "object", "properties": { "f1": { "type": "integer", "index": true, "sort": true } } },
+				"arr": { "type": "array", "items": { "type": "object", "properties": { "f1": { "type": "string" } } } }
+			} }`,
+			exp: []*Change{
+				{Collection: "c1", Field: "arr[].f1", Kind: TypeChanged, Old: "integer", New: "string", Severity: Breaking},
+				{Collection: "c1", Field: "obj.f1", Kind: IndexChanged, Old: "", New: "index,sort", Severity: RequiresBackfill},
+			},
+		},
+		{
+			name: "required_and_indexes",
+			oldSch: `{ "title": "c1", "properties": {
+				"idx": { "type": "integer", "index": true, "sort": true }
+			} }`,
+			newSch: `{ "title": "c1", "properties": {
+				"idx": { "type": "integer", "index": true },
+				"req": { "type": "string" },
+				"req_default": { "type": "string", "default": "value" }
+			}, "required": ["req", "req_default"] }`,
+			exp: []*Change{
+				{Collection: "c1", Field: "idx", Kind: IndexChanged, Old: "index,sort", New: "index", Severity: Safe},
+				{Collection: "c1", Field: "req", Kind: FieldAdded, New: "string", Severity: RequiresBackfill},
+				{Collection: "c1", Field: "req_default", Kind: FieldAdded, New: "string", Severity: Safe},
+			},
+		},
+		{
+			name:   "primary_key",
+			oldSch: `{ "title": "c1", "properties": { "id": { "type": "integer" } }, "primary_key": ["id"] }`,
+			newSch: `{ "title": "c1", "properties": { "id": { "type": "integer" } }, "primary_key": ["id", "id1"] }`,
+			exp: []*Change{
+				{Collection: "c1", Kind: PrimaryKeyChanged, Old: "id", New: "id, id1", Severity: Breaking},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var o, n schema.Schema
+
+			require.NoError(t, json.Unmarshal([]byte(c.oldSch), &o))
+			require.NoError(t, json.Unmarshal([]byte(c.newSch), &n))
+
+			res := Diff(&o, &n)
+			assert.Equal(t, c.exp, res)
+			assert.Equal(t, HasBreaking(c.exp), HasBreaking(res))
+		})
+	}
+}
+
+func TestSchemaDiffCollections(t *testing.T) {
+	c1 := &schema.Schema{Name: "c1", Fields: map[string]*schema.Field{"f1": {Type: schema.NewMultiType(typeString)}}}
+	c2 := &schema.Schema{Name: "c2"}
+	c3 := &schema.Schema{Name: "c3"}
+
+	res := DiffCollections(map[string]*schema.Schema{"c1": c1, "c2": c2}, map[string]*schema.Schema{"c1": c1, "c3": c3})
+	assert.Equal(t, []*Change{
+		{Collection: "c2", Kind: CollectionRemoved, Severity: Breaking},
+		{Collection: "c3", Kind: CollectionAdded, Severity: Safe},
+	}, res)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// JSON schema types of the fields, shared by the model generators and the schema converters.
+const (
+	TypeInteger = typeInteger
+	TypeString  = typeString
+	TypeBoolean = typeBoolean
+	TypeNumber  = typeNumber
+	TypeArray   = typeArray
+	TypeObject  = typeObject
+)
+
+// Supported subtypes.
+const (
+	FormatInt32    = "int32"
+	FormatInt64    = "int64"
+	FormatByte     = formatByte
+	FormatDateTime = formatDateTime
+	FormatUUID     = formatUUID
+	FormatVector   = formatVector
+)
+
+// IndexOf returns the position of s in l or -1 if it's not in the list.
+func IndexOf(l []string, s string) int {
+	for i, v := range l {
+		if v == s {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// SortedFields returns primary key fields first, in the key order, followed by the rest of the fields in
+// alphabetical order.
+func SortedFields(fields map[string]*schema.Field, pk []string) []string {
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := IndexOf(pk, names[i]), IndexOf(pk, names[j])
+
+		switch {
+		case pi >= 0 && pj >= 0:
+			return pi < pj
+		case pi >= 0 || pj >= 0:
+			return pi >= 0
+		}
+
+		return names[i] < names[j]
+	})
+
+	return names
+}
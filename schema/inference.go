@@ -42,6 +42,7 @@ const (
 	formatByte     = "byte"
 	formatDateTime = "date-time"
 	formatUUID     = "uuid"
+	formatVector   = "vector"
 )
 
 var (
@@ -49,11 +50,16 @@ var (
 	DetectUUIDs      = true
 	DetectTimes      = true
 	DetectIntegers   = true
+	DetectVectors    = false
+
+	// VectorMinDimensions is the minimum length of array of numbers to be detected as vector.
+	VectorMinDimensions = 16
 
 	ErrIncompatibleSchema = fmt.Errorf("error incompatible schema")
 	ErrExpectedString     = fmt.Errorf("expected string type")
 	ErrExpectedNumber     = fmt.Errorf("expected json.Number")
 	ErrUnsupportedType    = fmt.Errorf("unsupported type")
+	ErrVectorDimensions   = fmt.Errorf("documents have different number of dimensions than the vector field")
 
 	HasArrayOfObjects bool
 )
@@ -204,7 +210,9 @@ func extendedType(name string, oldType string, oldFormat string, newType string,
 	return "", "", newInompatibleSchemaError(name, oldType, oldFormat, newType, newFormat)
 }
 
-func traverseObject(name string, existingField *schema.Field, newField *schema.Field, values map[string]any) error {
+func traverseObject(name string, path string, autoGen []string, existingField *schema.Field, newField *schema.Field,
+	values map[string]any,
+) error {
 	switch {
 	case existingField == nil:
 		newField.Fields = make(map[string]*schema.Field)
@@ -221,7 +229,7 @@ func traverseObject(name string, existingField *schema.Field, newField *schema.F
 		return newInompatibleSchemaError(name, existingField.Type.First(), "", newField.Type.First(), "")
 	}
 
-	return traverseFields(newField.Fields, values, nil)
+	return traverseFields(newField.Fields, values, path, autoGen)
 }
 
 func traverseArray(name string, existingField *schema.Field, newField *schema.Field, v any) error {
@@ -259,7 +267,7 @@ func traverseArray(name string, existingField *schema.Field, newField *schema.Fi
 			HasArrayOfObjects = true
 
 			values, _ := reflect.ValueOf(v).Index(i).Interface().(map[string]any)
-			if err = traverseObject(name, newField.Items, newField.Items, values); err != nil {
+			if err = traverseObject(name, "", nil, newField.Items, newField.Items, values); err != nil {
 				return err
 			}
 
@@ -272,23 +280,57 @@ func traverseArray(name string, existingField *schema.Field, newField *schema.Fi
 	return nil
 }
 
-func setAutoGenerate(autoGen []string, name string, field *schema.Field) {
+func isNumeric(f *schema.Field) bool {
+	return f.Type.First() == typeNumber || f.Type.First() == typeInteger
+}
+
+// detectVector narrows array of numbers to the vector type.
+// The field stays a vector only while all the arrays have the same length.
+// Vector fields of the schema passed to Infer can't be converted back, see checkVectors.
+func detectVector(existingField *schema.Field, newField *schema.Field, dim int) {
+	if !isNumeric(newField.Items) {
+		return
+	}
+
+	switch {
+	case existingField == nil:
+		if !DetectVectors || dim < VectorMinDimensions {
+			return
+		}
+	case existingField.Format == formatVector:
+		if existingField.Dimensions != dim {
+			log.Debug().Int("old_dimensions", existingField.Dimensions).Int("new_dimensions", dim).
+				Msg("vector converted to array")
+
+			return
+		}
+	default:
+		return
+	}
+
+	newField.Format = formatVector
+	newField.Dimensions = dim
+	newField.Items.Type.Set(typeNumber)
+}
+
+func setAutoGenerate(autoGen []string, path string, field *schema.Field) {
 	if autoGen != nil {
 		// FIXME: Convert to O(1)
 		for i := 0; i < len(autoGen); i++ {
-			if autoGen[i] == name {
+			if autoGen[i] == path {
 				field.AutoGenerate = true
 			}
 		}
 	}
 }
 
-func traverseFieldsLow(t string, format string, name string, f *schema.Field, v any, sch map[string]*schema.Field,
+func traverseFieldsLow(t string, format string, name string, path string, autoGen []string, f *schema.Field, v any,
+	sch map[string]*schema.Field,
 ) (bool, error) {
 	switch {
 	case t == typeObject:
 		vm, _ := v.(map[string]any)
-		if err := traverseObject(name, sch[name], f, vm); err != nil {
+		if err := traverseObject(name, path, autoGen, sch[name], f, vm); err != nil {
 			return false, err
 		}
 
@@ -308,6 +350,8 @@ func traverseFieldsLow(t string, format string, name string, f *schema.Field, v
 		if f.Items == nil {
 			return true, nil // empty object
 		}
+
+		detectVector(sch[name], f, reflect.ValueOf(v).Len())
 	case sch[name] != nil:
 		nt, nf, err := extendedType(name, sch[name].Type.First(), sch[name].Format, t, format)
 		if err != nil {
@@ -321,7 +365,9 @@ func traverseFieldsLow(t string, format string, name string, f *schema.Field, v
 	return false, nil
 }
 
-func traverseFields(sch map[string]*schema.Field, fields map[string]any, autoGen []string) error {
+// traverseFields infers schema of the fields.
+// prefix is the path of the parent object, empty for the top level fields.
+func traverseFields(sch map[string]*schema.Field, fields map[string]any, prefix string, autoGen []string) error {
 	for name, val := range fields {
 		// handle `null` JSON value
 		if val == nil {
@@ -335,7 +381,9 @@ func traverseFields(sch map[string]*schema.Field, fields map[string]any, autoGen
 
 		f := &schema.Field{Type: schema.NewMultiType(t), Format: format}
 
-		skip, err := traverseFieldsLow(t, format, name, f, val, sch)
+		path := JoinPath(prefix, name)
+
+		skip, err := traverseFieldsLow(t, format, name, path, autoGen, f, val, sch)
 		if err != nil {
 			return err
 		}
@@ -344,7 +392,7 @@ func traverseFields(sch map[string]*schema.Field, fields map[string]any, autoGen
 			continue
 		}
 
-		setAutoGenerate(autoGen, name, f)
+		setAutoGenerate(autoGen, path, f)
 
 		sch[name] = f
 	}
@@ -371,7 +419,7 @@ func docToSchema(sch *schema.Schema, name string, data []byte, pk []string, auto
 		sch.Fields = make(map[string]*schema.Field)
 	}
 
-	if err := traverseFields(sch.Fields, m, autoGen); err != nil {
+	if err := traverseFields(sch.Fields, m, "", autoGen); err != nil {
 		return err
 	}
 
@@ -385,9 +433,42 @@ func docToSchema(sch *schema.Schema, name string, data []byte, pk []string, auto
 	return nil
 }
 
+// vectorFields collects the paths and the dimensions of the vector fields.
+func vectorFields(prefix string, fields map[string]*schema.Field, res map[string]int) map[string]int {
+	for name, f := range fields {
+		switch {
+		case f.Format == formatVector:
+			res[JoinPath(prefix, name)] = f.Dimensions
+		case f.Type.First() == typeObject:
+			vectorFields(JoinPath(prefix, name), f.Fields, res)
+		}
+	}
+
+	return res
+}
+
+// checkVectors fails if the vector field, detected by the previous batches, was converted to array.
+// The schema with the vector field can already be applied to the collection
+// and changing it to array is not compatible.
+func checkVectors(before map[string]int, fields map[string]*schema.Field) error {
+	after := vectorFields("", fields, make(map[string]int))
+
+	for path, dim := range before {
+		if _, ok := after[path]; !ok {
+			return fmt.Errorf("%w: %s has %d dimensions. "+
+				"increase --inference-depth and --batch-size to infer over more documents, "+
+				"or disable --detect-vectors", ErrVectorDimensions, path, dim)
+		}
+	}
+
+	return nil
+}
+
 func Infer(sch *schema.Schema, name string, docs []json.RawMessage, primaryKey []string, autoGenerate []string,
 	depth int,
 ) error {
+	vectors := vectorFields("", sch.Fields, make(map[string]int))
+
 	for i := 0; (depth == 0 || i < depth) && i < len(docs); i++ {
 		err := docToSchema(sch, name, docs[i], primaryKey, autoGenerate)
 		if err != nil {
@@ -395,7 +476,21 @@ func Infer(sch *schema.Schema, name string, docs []json.RawMessage, primaryKey [
 		}
 	}
 
-	return nil
+	if err := checkVectors(vectors, sch.Fields); err != nil {
+		return err
+	}
+
+	// The autogenerated fields are usually missing in the documents, the server generates them on insert
+	for _, p := range autoGenerate {
+		addAutoGenerated(sch.Fields, p)
+	}
+
+	// The key fields can be missing in some documents, so the paths are checked after all the documents
+	if err := validatePaths(sch.Fields, sch.PrimaryKey); err != nil {
+		return err
+	}
+
+	return validatePaths(sch.Fields, autoGenerate)
 }
 
 func GenerateInitDoc(sch *schema.Schema, doc json.RawMessage) ([]byte, error) {
@@ -419,6 +514,12 @@ func GenerateInitDoc(sch *schema.Schema, doc json.RawMessage) ([]byte, error) {
 		}
 	}
 
+	for _, path := range sch.PrimaryKey {
+		if err := initDocSetKey(initDoc, sch.Fields, path); err != nil {
+			return nil, err
+		}
+	}
+
 	return json.Marshal(initDoc)
 }
 
@@ -427,7 +528,12 @@ func initDocTraverseFields(field *schema.Field, doc map[string]any, fieldName st
 	case typeNumber:
 		doc[fieldName] = 0.0000001
 	case typeObject:
-		vo := map[string]any{}
+		// Retain nested values, so as nested primary key fields are preserved
+		vo, ok := doc[fieldName].(map[string]any)
+		if !ok {
+			vo = map[string]any{}
+		}
+
 		for name := range field.Fields {
 			if err := initDocTraverseFields(field.Fields[name], vo, name); err != nil {
 				return err
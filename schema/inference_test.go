@@ -17,6 +17,7 @@ package schema
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 	"unsafe"
 
@@ -447,6 +448,47 @@ func TestSchemaTags(t *testing.T) {
 				PrimaryKey: []string{"uuid_field", "uuid_field1"},
 			},
 		},
+		{
+			name: "pk_autogenerate_nested",
+			in: [][]byte{
+				[]byte(`{ "meta" : { "uuid" : "1ed6ff32-4c0f-4553-9cd3-a2ea3d58e9d1", "id": 1 },
+"uuid" : "1ed6ff32-4c0f-4553-9cd3-a2ea3d58e9d1" }`),
+			},
+			primaryKey:   []string{"meta.id"},
+			autoGenerate: []string{"meta.uuid"},
+			exp: &schema.Schema{
+				Name: "pk_autogenerate_nested",
+				Fields: map[string]*schema.Field{
+					"meta": {Type: schema.NewMultiType(typeObject), Fields: map[string]*schema.Field{
+						"uuid": {Type: schema.NewMultiType(typeString), Format: "uuid", AutoGenerate: true},
+						"id":   {Type: schema.NewMultiType(typeInteger)},
+					}},
+					"uuid": {Type: schema.NewMultiType(typeString), Format: "uuid"},
+				},
+				PrimaryKey: []string{"meta.id"},
+			},
+		},
+		{
+			name:         "pk_autogenerate_missing",
+			in:           [][]byte{[]byte(`{ "name" : "a", "meta" : { "id": 1 } }`)},
+			primaryKey:   []string{"id"},
+			autoGenerate: []string{"id", "meta.uuid", "ext.uuid"},
+			exp: &schema.Schema{
+				Name: "pk_autogenerate_missing",
+				Fields: map[string]*schema.Field{
+					"id":   {Type: schema.NewMultiType(typeString), Format: "uuid", AutoGenerate: true},
+					"name": {Type: schema.NewMultiType(typeString)},
+					"meta": {Type: schema.NewMultiType(typeObject), Fields: map[string]*schema.Field{
+						"id":   {Type: schema.NewMultiType(typeInteger)},
+						"uuid": {Type: schema.NewMultiType(typeString), Format: "uuid", AutoGenerate: true},
+					}},
+					"ext": {Type: schema.NewMultiType(typeObject), Fields: map[string]*schema.Field{
+						"uuid": {Type: schema.NewMultiType(typeString), Format: "uuid", AutoGenerate: true},
+					}},
+				},
+				PrimaryKey: []string{"id"},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -459,3 +501,155 @@ func TestSchemaTags(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaTagsNegative(t *testing.T) {
+	cases := []struct {
+		name         string
+		primaryKey   []string
+		autoGenerate []string
+		err          error
+	}{
+		{name: "pk_object", primaryKey: []string{"meta"}, err: fmt.Errorf("%w: meta", ErrPathObject)},
+		{name: "pk_array", primaryKey: []string{"arr"}, err: fmt.Errorf("%w: arr", ErrPathArray)},
+		{name: "pk_through_array", primaryKey: []string{"arr.id"}, err: fmt.Errorf("%w: arr.id", ErrPathArray)},
+		{name: "autogen_object", autoGenerate: []string{"meta"}, err: fmt.Errorf("%w: meta", ErrPathObject)},
+		{name: "autogen_array", autoGenerate: []string{"arr"}, err: fmt.Errorf("%w: arr", ErrPathArray)},
+		{name: "pk_unknown", primaryKey: []string{"meta.idd"}, err: fmt.Errorf("%w: meta.idd", ErrPathNotFound)},
+		{name: "pk_through_primitive", primaryKey: []string{"meta.id.x"}, err: fmt.Errorf("%w: meta.id.x", ErrPathNotFound)},
+		{
+			name: "autogen_through_primitive", autoGenerate: []string{"meta.id.x"},
+			err: fmt.Errorf("%w: meta.id.x", ErrPathNotFound),
+		},
+	}
+
+	in := []json.RawMessage{[]byte(`{ "meta" : { "id": 1 }, "arr" : [ { "id" : 1 } ] }`)}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var sch schema.Schema
+			err := Infer(&sch, c.name, in, c.primaryKey, c.autoGenerate, 0)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestGenerateInitDocNestedKey(t *testing.T) {
+	var sch schema.Schema
+
+	in := []json.RawMessage{
+		[]byte(`{ "meta" : { "id": "key1", "num": 1.1 }, "str" : "str_value" }`),
+		[]byte(`{ "other" : { "id": 2 } }`),
+	}
+
+	err := Infer(&sch, "init_doc", in, []string{"meta.id", "other.id"}, nil, 0)
+	require.NoError(t, err)
+
+	doc, err := GenerateInitDoc(&sch, in[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{ "meta" : { "id": "key1", "num": 0.0000001 }, "other" : { "id": 1 }, "str" : "str_value" }`,
+		string(doc))
+}
+
+func TestVectorInference(t *testing.T) {
+	cases := []struct {
+		name string
+		exp  *schema.Field
+		in   [][]byte
+	}{
+		{
+			name: "vector",
+			in: [][]byte{
+				[]byte(`{ "vec" : [ 1, 2.5, 3 ] }`),
+				[]byte(`{ "vec" : [ 0.1, 0.2, 0.3 ] }`),
+			},
+			exp: &schema.Field{
+				Type: schema.NewMultiType(typeArray), Format: formatVector, Dimensions: 3,
+				Items: &schema.Field{Type: schema.NewMultiType(typeNumber)},
+			},
+		},
+		{
+			name: "integer_vector",
+			in: [][]byte{
+				[]byte(`{ "vec" : [ 1, 2, 3 ] }`),
+			},
+			exp: &schema.Field{
+				Type: schema.NewMultiType(typeArray), Format: formatVector, Dimensions: 3,
+				Items: &schema.Field{Type: schema.NewMultiType(typeNumber)},
+			},
+		},
+		{
+			name: "below_threshold",
+			in: [][]byte{
+				[]byte(`{ "vec" : [ 0.1, 0.2 ] }`),
+			},
+			exp: &schema.Field{
+				Type:  schema.NewMultiType(typeArray),
+				Items: &schema.Field{Type: schema.NewMultiType(typeNumber)},
+			},
+		},
+		{
+			name: "inconsistent_dimensions",
+			in: [][]byte{
+				[]byte(`{ "vec" : [ 0.1, 0.2, 0.3 ] }`),
+				[]byte(`{ "vec" : [ 0.1, 0.2, 0.3, 0.4 ] }`),
+				[]byte(`{ "vec" : [ 0.1, 0.2, 0.3 ] }`),
+			},
+			exp: &schema.Field{
+				Type:  schema.NewMultiType(typeArray),
+				Items: &schema.Field{Type: schema.NewMultiType(typeNumber)},
+			},
+		},
+		{
+			name: "not_numbers",
+			in: [][]byte{
+				[]byte(`{ "vec" : [ "a", "b", "c" ] }`),
+			},
+			exp: &schema.Field{
+				Type:  schema.NewMultiType(typeArray),
+				Items: &schema.Field{Type: schema.NewMultiType(typeString)},
+			},
+		},
+	}
+
+	DetectVectors = true
+	VectorMinDimensions = 3
+
+	defer func() {
+		DetectVectors = false
+		VectorMinDimensions = 16
+	}()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var sch schema.Schema
+			ptr := unsafe.Pointer(&c.in)
+			err := Infer(&sch, c.name, *(*[]json.RawMessage)(ptr), nil, nil, 0)
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, sch.Fields["vec"])
+		})
+	}
+}
+
+func TestVectorInferenceBatches(t *testing.T) {
+	DetectVectors = true
+	VectorMinDimensions = 3
+
+	defer func() {
+		DetectVectors = false
+		VectorMinDimensions = 16
+	}()
+
+	var sch schema.Schema
+
+	err := Infer(&sch, "batches", []json.RawMessage{[]byte(`{ "meta": { "vec" : [ 1, 2, 3 ] } }`)}, nil, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, formatVector, sch.Fields["meta"].Fields["vec"].Format)
+
+	err = Infer(&sch, "batches", []json.RawMessage{[]byte(`{ "meta": { "vec" : [ 1, 2, 3 ] } }`)}, nil, nil, 0)
+	require.NoError(t, err)
+
+	// the vector is applied to the collection by the first batch already
+	err = Infer(&sch, "batches", []json.RawMessage{[]byte(`{ "meta": { "vec" : [ 1, 2 ] } }`)}, nil, nil, 0)
+	require.ErrorIs(t, err, ErrVectorDimensions)
+	assert.Contains(t, err.Error(), "meta.vec has 3 dimensions")
+}
@@ -0,0 +1,178 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// Lint rules.
+const (
+	RuleFieldNaming       = "field-naming"
+	RuleDescription       = "description"
+	RuleBoundedPrimaryKey = "bounded-primary-key"
+	RuleMaxDepth          = "max-depth"
+	RuleNoNestedArrays    = "no-nested-arrays"
+	RuleFilterIndex       = "filter-index"
+	RuleSortIndex         = "sort-index"
+)
+
+// Field naming conventions.
+const (
+	NamingSnakeCase = "snake_case"
+	NamingCamelCase = "camelCase"
+)
+
+var (
+	ErrUnknownNaming = fmt.Errorf("unknown naming convention. supported are: snake_case, camelCase")
+
+	namingRegexp = map[string]*regexp.Regexp{
+		NamingSnakeCase: regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+		NamingCamelCase: regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	}
+)
+
+// Issue is a single violation of the lint rule.
+type Issue struct {
+	Collection string `json:"collection"`
+	Field      string `json:"field,omitempty"`
+	Rule       string `json:"rule"`
+	Message    string `json:"message"`
+}
+
+func (i *Issue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+type linter struct {
+	rules  *config.Lint
+	coll   string
+	issues []*Issue
+}
+
+func (l *linter) enabled(rule string) bool {
+	return !util.Contains(l.rules.Disabled, rule)
+}
+
+func (l *linter) report(field string, rule string, format string, args ...any) {
+	if l.enabled(rule) {
+		l.issues = append(l.issues, &Issue{
+			Collection: l.coll, Field: field, Rule: rule, Message: fmt.Sprintf(format, args...),
+		})
+	}
+}
+
+func (l *linter) lintField(path string, name string, f *schema.Field, depth int) {
+	if re := namingRegexp[l.rules.Naming]; re != nil && !re.MatchString(name) {
+		l.report(path, RuleFieldNaming, "name should be in %s", l.rules.Naming)
+	}
+
+	if l.rules.Descriptions && f.Desc == "" {
+		l.report(path, RuleDescription, "description is missing")
+	}
+
+	if l.rules.MaxDepth > 0 && depth > l.rules.MaxDepth {
+		l.report(path, RuleMaxDepth, "nesting depth %d exceeds maximum %d", depth, l.rules.MaxDepth)
+	}
+
+	if f.Type.First() == typeArray && f.Items != nil && f.Items.Type.First() == typeArray {
+		l.report(path, RuleNoNestedArrays, "arrays of arrays are not allowed")
+	}
+
+	switch {
+	case f.Type.First() == typeObject:
+		l.lintFields(path, f.Fields, depth+1)
+	case f.Type.First() == typeArray:
+		if items := f.Items; items != nil && items.Type.First() == typeObject {
+			l.lintFields(path+ArrayItems, items.Fields, depth+1)
+		}
+	}
+}
+
+func (l *linter) lintFields(prefix string, fields map[string]*schema.Field, depth int) {
+	for _, name := range sortedNames(fields) {
+		l.lintField(JoinPath(prefix, name), name, fields[name], depth)
+	}
+}
+
+// lintKeys checks primary key and the fields configured as filter and sort fields.
+func (l *linter) lintKeys(sch *schema.Schema) {
+	for _, path := range sch.PrimaryKey {
+		f, err := LookupField(sch.Fields, path)
+		if err == nil && f != nil && f.Type.First() == typeString && !f.AutoGenerate &&
+			(f.Format == "" || f.Format == formatByte) && f.MaxLength == 0 {
+			l.report(path, RuleBoundedPrimaryKey, "primary key string field should have maxLength")
+		}
+	}
+
+	for _, path := range l.rules.FilterFields {
+		// Primary key fields are always indexed
+		if util.Contains(sch.PrimaryKey, path) {
+			continue
+		}
+
+		if f, err := LookupField(sch.Fields, path); err == nil && f != nil && !f.Index {
+			l.report(path, RuleFilterIndex, "field is used in filters and should be indexed")
+		}
+	}
+
+	for _, path := range l.rules.SortFields {
+		if f, err := LookupField(sch.Fields, path); err == nil && f != nil && !f.Sort {
+			l.report(path, RuleSortIndex, "field is used for sorting and should be sortable")
+		}
+	}
+}
+
+// Lint checks the collection schemas against the rules.
+// Returns issues ordered by collection.
+func Lint(schemas map[string]*schema.Schema, rules *config.Lint) ([]*Issue, error) {
+	if rules.Naming != "" && namingRegexp[rules.Naming] == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNaming, rules.Naming)
+	}
+
+	names := make([]string, 0, len(schemas))
+	for k := range schemas {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	l := &linter{rules: rules}
+
+	for _, name := range names {
+		sch := schemas[name]
+
+		l.coll = name
+
+		if rules.Descriptions && sch.Desc == "" {
+			l.report("", RuleDescription, "collection description is missing")
+		}
+
+		l.lintKeys(sch)
+		l.lintFields("", sch.Fields, 1)
+	}
+
+	return l.issues, nil
+}
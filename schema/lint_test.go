@@ -0,0 +1,123 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//nolint:funlen
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestLint(t *testing.T) {
+	sch := `{ "title": "c1", "properties": {
+		"id": { "type": "string" },
+		"userName": { "type": "string", "description": "user name" },
+		"meta": { "type": "object", "properties": {
+			"inner": { "type": "object", "properties": { "tag": { "type": "string", "sort": true } } }
+		} },
+		"matrix": { "type": "array", "items": { "type": "array", "items": { "type": "number" } } },
+		"created": { "type": "string", "format": "date-time", "index": true }
+	}, "primary_key": ["id"] }`
+
+	cases := []struct {
+		name  string
+		rules config.Lint
+		exp   []*Issue
+	}{
+		{
+			name: "default",
+			exp: []*Issue{
+				{
+					Collection: "c1", Field: "id", Rule: RuleBoundedPrimaryKey,
+					Message: "primary key string field should have maxLength",
+				},
+				{Collection: "c1", Field: "matrix", Rule: RuleNoNestedArrays, Message: "arrays of arrays are not allowed"},
+			},
+		},
+		{
+			name: "disabled",
+			rules: config.Lint{
+				Disabled: []string{RuleBoundedPrimaryKey, RuleNoNestedArrays},
+			},
+		},
+		{
+			name: "naming_depth",
+			rules: config.Lint{
+				Naming:   NamingSnakeCase,
+				MaxDepth: 2,
+				Disabled: []string{RuleBoundedPrimaryKey, RuleNoNestedArrays},
+			},
+			exp: []*Issue{
+				{Collection: "c1", Field: "meta.inner.tag", Rule: RuleMaxDepth, Message: "nesting depth 3 exceeds maximum 2"},
+				{Collection: "c1", Field: "userName", Rule: RuleFieldNaming, Message: "name should be in snake_case"},
+			},
+		},
+		{
+			name: "indexes",
+			rules: config.Lint{
+				Naming:       NamingCamelCase,
+				FilterFields: []string{"id", "created", "userName"},
+				SortFields:   []string{"meta.inner.tag", "created"},
+				Disabled:     []string{RuleBoundedPrimaryKey, RuleNoNestedArrays},
+			},
+			exp: []*Issue{
+				{
+					Collection: "c1", Field: "userName", Rule: RuleFilterIndex,
+					Message: "field is used in filters and should be indexed",
+				},
+				{
+					Collection: "c1", Field: "created", Rule: RuleSortIndex,
+					Message: "field is used for sorting and should be sortable",
+				},
+			},
+		},
+		{
+			name: "descriptions",
+			rules: config.Lint{
+				Descriptions: true,
+				Disabled:     []string{RuleBoundedPrimaryKey, RuleNoNestedArrays},
+			},
+			exp: []*Issue{
+				{Collection: "c1", Rule: RuleDescription, Message: "collection description is missing"},
+				{Collection: "c1", Field: "created", Rule: RuleDescription, Message: "description is missing"},
+				{Collection: "c1", Field: "id", Rule: RuleDescription, Message: "description is missing"},
+				{Collection: "c1", Field: "matrix", Rule: RuleDescription, Message: "description is missing"},
+				{Collection: "c1", Field: "meta", Rule: RuleDescription, Message: "description is missing"},
+				{Collection: "c1", Field: "meta.inner", Rule: RuleDescription, Message: "description is missing"},
+				{Collection: "c1", Field: "meta.inner.tag", Rule: RuleDescription, Message: "description is missing"},
+			},
+		},
+	}
+
+	var s schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(sch), &s))
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			issues, err := Lint(map[string]*schema.Schema{"c1": &s}, &c.rules)
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, issues)
+		})
+	}
+
+	_, err := Lint(nil, &config.Lint{Naming: "kebab"})
+	require.ErrorIs(t, err, ErrUnknownNaming)
+}
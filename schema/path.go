@@ -0,0 +1,230 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// PathSeparator separates field names in the nested field path, like: "meta.uuid".
+const PathSeparator = "."
+
+var (
+	ErrPathArray    = fmt.Errorf("field path resolves to an array")
+	ErrPathObject   = fmt.Errorf("field path resolves to an object")
+	ErrPathNotFound = fmt.Errorf("field path is not in the schema")
+)
+
+// JoinPath appends the field name to the dotted path of the parent object.
+func JoinPath(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + PathSeparator + name
+}
+
+// LookupField resolves dotted field path in the schema fields.
+// Returns ErrPathNotFound if the field doesn't exist.
+// Only primitive fields are allowed at the end of the path and arrays are not allowed in the middle.
+func LookupField(fields map[string]*schema.Field, path string) (*schema.Field, error) {
+	names := strings.Split(path, PathSeparator)
+
+	for i, n := range names {
+		f := fields[n]
+		if f == nil {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+
+		switch f.Type.First() {
+		case typeArray:
+			return nil, fmt.Errorf("%w: %s", ErrPathArray, path)
+		case typeObject:
+			if i == len(names)-1 {
+				return nil, fmt.Errorf("%w: %s", ErrPathObject, path)
+			}
+
+			fields = f.Fields
+		default:
+			if i != len(names)-1 {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+
+			return f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+}
+
+func validatePaths(fields map[string]*schema.Field, paths []string) error {
+	for _, p := range paths {
+		if _, err := LookupField(fields, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addAutoGenerated adds the autogenerated field, which is missing in the documents, to the schema.
+// The field is generated by the server as uuid, the same as the suggested autogenerated primary key.
+// Paths conflicting with the inferred fields are left for validatePaths to report.
+func addAutoGenerated(fields map[string]*schema.Field, path string) {
+	names := strings.Split(path, PathSeparator)
+
+	for _, n := range names[:len(names)-1] {
+		f := fields[n]
+		if f == nil {
+			f = &schema.Field{Type: schema.NewMultiType(typeObject), Fields: make(map[string]*schema.Field)}
+			fields[n] = f
+		}
+
+		if f.Type.First() != typeObject || f.Fields == nil {
+			return
+		}
+
+		fields = f.Fields
+	}
+
+	if fields[names[len(names)-1]] == nil {
+		fields[names[len(names)-1]] = &schema.Field{
+			Type: schema.NewMultiType(typeString), Format: formatUUID, AutoGenerate: true,
+		}
+	}
+}
+
+func initKeyValue(f *schema.Field) any {
+	switch {
+	case f.Type.First() == typeInteger || f.Type.First() == typeNumber:
+		return 1
+	case f.Format == formatUUID:
+		return uuid.New().String()
+	case f.Format == formatDateTime:
+		return time.Now().Format(time.RFC3339Nano)
+	case f.Format == formatByte:
+		return base64.StdEncoding.EncodeToString([]byte("init"))
+	case f.Type.First() == typeBoolean:
+		return true
+	default:
+		return "init"
+	}
+}
+
+// initDocSetKey makes sure that the key field is present in the init document.
+func initDocSetKey(doc map[string]any, fields map[string]*schema.Field, path string) error {
+	f, err := LookupField(fields, path)
+	if err != nil {
+		return err
+	}
+
+	if f.AutoGenerate {
+		return nil
+	}
+
+	names := strings.Split(path, PathSeparator)
+
+	for _, n := range names[:len(names)-1] {
+		m, ok := doc[n].(map[string]any)
+		if !ok {
+			m = make(map[string]any)
+			doc[n] = m
+		}
+
+		doc = m
+	}
+
+	if doc[names[len(names)-1]] == nil {
+		doc[names[len(names)-1]] = initKeyValue(f)
+	}
+
+	return nil
+}
+
+// LookupValue returns the value of the dotted field path in the document and whether the field exists.
+func LookupValue(doc map[string]any, path string) (any, bool) {
+	var v any = doc
+
+	for _, name := range strings.Split(path, PathSeparator) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = m[name]; !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// NumberValue returns the value of the decoded JSON number.
+func NumberValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+// CompareValues orders the field values: nulls first, numbers numerically,
+// date-times chronologically and other values by their string representation.
+func CompareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	fa, aok := NumberValue(a)
+	fb, bok := NumberValue(b)
+
+	if aok && bok {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+
+		return 0
+	}
+
+	sa, sb := fmt.Sprint(a), fmt.Sprint(b)
+
+	ta, erra := time.Parse(time.RFC3339Nano, sa)
+	tb, errb := time.Parse(time.RFC3339Nano, sb)
+
+	if erra == nil && errb == nil {
+		return ta.Compare(tb)
+	}
+
+	return strings.Compare(sa, sb)
+}
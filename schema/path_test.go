@@ -0,0 +1,65 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupValue(t *testing.T) {
+	doc := map[string]any{"id": "1", "user": map[string]any{"id": json.Number("2"), "name": nil}}
+
+	tests := []struct {
+		path string
+		exp  any
+		ok   bool
+	}{
+		{"id", "1", true},
+		{"user.id", json.Number("2"), true},
+		{"user.name", nil, true},
+		{"user.age", nil, false},
+		{"id.x", nil, false},
+		{"unknown", nil, false},
+	}
+
+	for _, tt := range tests {
+		v, ok := LookupValue(doc, tt.path)
+		assert.Equal(t, tt.exp, v, tt.path)
+		assert.Equal(t, tt.ok, ok, tt.path)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a   any
+		b   any
+		exp int
+	}{
+		{nil, nil, 0},
+		{nil, "a", -1},
+		{json.Number("1"), nil, 1},
+		{json.Number("9"), json.Number("10"), -1},
+		{json.Number("10"), 10.0, 0},
+		{"2023-01-01T10:00:00+02:00", "2023-01-01T09:00:00Z", -1},
+		{"b", "a", 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.exp, CompareValues(tt.a, tt.b), "%v %v", tt.a, tt.b)
+	}
+}
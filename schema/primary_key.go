@@ -0,0 +1,208 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const autoGeneratedKeyName = "id"
+
+// PrimaryKeyCandidate is a field which can be used as collection's primary key.
+type PrimaryKeyCandidate struct {
+	Name         string
+	Type         string
+	Format       string
+	AutoGenerate bool // field doesn't exist in the documents and should be generated by the server
+}
+
+type keyStats struct {
+	typ    string
+	format string
+	values map[string]struct{}
+	bad    bool
+}
+
+// rank orders candidates by preference: uuid, integer, then other strings.
+func (c *PrimaryKeyCandidate) rank() int {
+	switch {
+	case c.Format == formatUUID:
+		return 0
+	case c.Type == typeInteger:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (c *PrimaryKeyCandidate) String() string {
+	s := c.Type
+	if c.Format != "" {
+		s += ":" + c.Format
+	}
+
+	if c.AutoGenerate {
+		s += ", autogenerated"
+	}
+
+	return fmt.Sprintf("%s (%s)", c.Name, s)
+}
+
+// Apply sets the candidate as the primary key of the schema.
+// Autogenerated candidates are added to the schema fields.
+func (c *PrimaryKeyCandidate) Apply(sch *schema.Schema) {
+	if sch.Fields == nil {
+		sch.Fields = make(map[string]*schema.Field)
+	}
+
+	f := sch.Fields[c.Name]
+	if f == nil {
+		f = &schema.Field{Type: schema.NewMultiType(c.Type), Format: c.Format}
+		sch.Fields[c.Name] = f
+	}
+
+	if c.AutoGenerate {
+		f.AutoGenerate = true
+	}
+
+	sch.PrimaryKey = []string{c.Name}
+}
+
+func updateKeyStats(name string, st *keyStats, v any) {
+	if v == nil {
+		st.bad = true
+		return
+	}
+
+	t, format, err := translateType(v, nil)
+	if err != nil || (t != typeString && t != typeInteger) {
+		st.bad = true
+		return
+	}
+
+	if st.typ != "" {
+		if t, format, err = extendedType(name, st.typ, st.format, t, format); err != nil {
+			st.bad = true
+			return
+		}
+	}
+
+	st.typ, st.format = t, format
+
+	key := fmt.Sprint(v)
+	if _, ok := st.values[key]; ok {
+		st.bad = true
+		return
+	}
+
+	st.values[key] = struct{}{}
+}
+
+// autoGeneratedKeyCandidate returns the name of the autogenerated key field,
+// which doesn't clash with the fields of the documents.
+func autoGeneratedKeyCandidate(seen map[string]bool) string {
+	if !seen[autoGeneratedKeyName] {
+		return autoGeneratedKeyName
+	}
+
+	name := "_" + autoGeneratedKeyName
+	for i := 1; seen[name]; i++ {
+		name = fmt.Sprintf("_%s%d", autoGeneratedKeyName, i)
+	}
+
+	return name
+}
+
+// SuggestPrimaryKey returns primary key candidates detected in the sample of documents,
+// ordered from the most to the least preferred.
+// Candidate fields are present in every document, never null and unique within the sample.
+// If there is no such field, autogenerated "id" field is proposed,
+// or "_id", "_id1", ... if the documents already have the field with the name.
+func SuggestPrimaryKey(docs []json.RawMessage) ([]*PrimaryKeyCandidate, error) {
+	stats := make(map[string]*keyStats)
+	seen := make(map[string]bool)
+
+	for i, d := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewBuffer(d))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return nil, err
+		}
+
+		for name := range m {
+			seen[name] = true
+
+			if i == 0 {
+				stats[name] = &keyStats{values: make(map[string]struct{})}
+			}
+		}
+
+		for name, st := range stats {
+			if st.bad {
+				continue
+			}
+
+			v, ok := m[name]
+			if !ok {
+				st.bad = true
+				continue
+			}
+
+			updateKeyStats(name, st, v)
+		}
+	}
+
+	res := make([]*PrimaryKeyCandidate, 0)
+
+	for name, st := range stats {
+		if !st.bad {
+			res = append(res, &PrimaryKeyCandidate{Name: name, Type: st.typ, Format: st.format})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].rank() != res[j].rank() {
+			return res[i].rank() < res[j].rank()
+		}
+
+		if res[i].Name == autoGeneratedKeyName || res[j].Name == autoGeneratedKeyName {
+			return res[i].Name == autoGeneratedKeyName
+		}
+
+		return res[i].Name < res[j].Name
+	})
+
+	if len(res) == 0 && len(docs) > 0 {
+		res = append(res, &PrimaryKeyCandidate{
+			Name:         autoGeneratedKeyCandidate(seen),
+			Type:         typeString,
+			Format:       formatUUID,
+			AutoGenerate: true,
+		})
+	}
+
+	log.Debug().Interface("candidates", res).Msg("primary key candidates")
+
+	return res, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//nolint:golint,funlen
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestSuggestPrimaryKey(t *testing.T) {
+	cases := []struct {
+		name string
+		in   [][]byte
+		exp  []*PrimaryKeyCandidate
+	}{
+		{
+			name: "ordering",
+			in: [][]byte{
+				[]byte(`{ "str": "a", "int": 1, "uuid": "1ed6ff32-4c0f-4553-9cd3-a2ea3d58e9d1", "id": 10 }`),
+				[]byte(`{ "str": "b", "int": 2, "uuid": "2ed6ff32-4c0f-4553-9cd3-a2ea3d58e9d1", "id": 20 }`),
+			},
+			exp: []*PrimaryKeyCandidate{
+				{Name: "uuid", Type: typeString, Format: formatUUID},
+				{Name: "id", Type: typeInteger},
+				{Name: "int", Type: typeInteger},
+				{Name: "str", Type: typeString},
+			},
+		},
+		{
+			name: "not_qualified",
+			in: [][]byte{
+				[]byte(`{ "missing": 1, "null": 1, "dup": 1, "float": 1.1, "bool": true, "obj": {}, "key": 1 }`),
+				[]byte(`{ "null": null, "dup": 1, "float": 1.2, "bool": false, "obj": {}, "key": 2 }`),
+			},
+			exp: []*PrimaryKeyCandidate{
+				{Name: "key", Type: typeInteger},
+			},
+		},
+		{
+			name: "broaden_uuid",
+			in: [][]byte{
+				[]byte(`{ "key": "1ed6ff32-4c0f-4553-9cd3-a2ea3d58e9d1" }`),
+				[]byte(`{ "key": "not_uuid" }`),
+			},
+			exp: []*PrimaryKeyCandidate{
+				{Name: "key", Type: typeString},
+			},
+		},
+		{
+			name: "autogenerate",
+			in: [][]byte{
+				[]byte(`{ "dup": 1 }`),
+				[]byte(`{ "dup": 1 }`),
+			},
+			exp: []*PrimaryKeyCandidate{
+				{Name: "id", Type: typeString, Format: formatUUID, AutoGenerate: true},
+			},
+		},
+		{
+			name: "autogenerate_id_exists",
+			in: [][]byte{
+				[]byte(`{ "id": 1 }`),
+				[]byte(`{ "id": 1 }`),
+			},
+			exp: []*PrimaryKeyCandidate{
+				{Name: "_id", Type: typeString, Format: formatUUID, AutoGenerate: true},
+			},
+		},
+		{
+			name: "autogenerate_id_and__id_exist",
+			in: [][]byte{
+				[]byte(`{ "id": 1, "_id": 1 }`),
+				[]byte(`{ "id": 1, "_id": 1, "_id1": null }`),
+			},
+			exp: []*PrimaryKeyCandidate{
+				{Name: "_id2", Type: typeString, Format: formatUUID, AutoGenerate: true},
+			},
+		},
+		{
+			name: "no_documents",
+			in:   [][]byte{},
+			exp:  []*PrimaryKeyCandidate{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ptr := unsafe.Pointer(&c.in)
+			res, err := SuggestPrimaryKey(*(*[]json.RawMessage)(ptr))
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, res)
+		})
+	}
+}
+
+func TestPrimaryKeyCandidateApply(t *testing.T) {
+	sch := schema.Schema{Fields: map[string]*schema.Field{
+		"key": {Type: schema.NewMultiType(typeInteger)},
+	}}
+
+	(&PrimaryKeyCandidate{Name: "key", Type: typeInteger}).Apply(&sch)
+	assert.Equal(t, []string{"key"}, sch.PrimaryKey)
+	assert.False(t, sch.Fields["key"].AutoGenerate)
+
+	(&PrimaryKeyCandidate{Name: "id", Type: typeString, Format: formatUUID, AutoGenerate: true}).Apply(&sch)
+	assert.Equal(t, []string{"id"}, sch.PrimaryKey)
+	assert.Equal(t, &schema.Field{Type: schema.NewMultiType(typeString), Format: formatUUID, AutoGenerate: true},
+		sch.Fields["id"])
+}
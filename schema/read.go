@@ -0,0 +1,59 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// ReadSchemas reads collection schemas from the reader.
+// The input can contain a single schema, an array of schemas or a stream of them.
+func ReadSchemas(r io.Reader) ([]*schema.Schema, error) {
+	var res []*schema.Schema
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	for dec.More() {
+		var raw json.RawMessage
+
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		if raw[0] == '[' {
+			var list []*schema.Schema
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, err
+			}
+
+			res = append(res, list...)
+
+			continue
+		}
+
+		var sch schema.Schema
+		if err := json.Unmarshal(raw, &sch); err != nil {
+			return nil, err
+		}
+
+		res = append(res, &sch)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSchemas(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		exp   []string
+		err   bool
+	}{
+		{"single", `{"title":"c1"}`, []string{"c1"}, false},
+		{"array", `[{"title":"c1"},{"title":"c2"}]`, []string{"c1", "c2"}, false},
+		{"stream", "{\"title\":\"c1\"}\n[{\"title\":\"c2\"}]\n{\"title\":\"c3\"}", []string{"c1", "c2", "c3"}, false},
+		{"empty", "", nil, false},
+		{"invalid", `{"title":`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ReadSchemas(strings.NewReader(tt.input))
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var names []string
+			for _, sch := range res {
+				names = append(names, sch.Name)
+			}
+
+			assert.Equal(t, tt.exp, names)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var ErrInvalidDocument = fmt.Errorf("document doesn't match the schema")
+
+func invalidDocument(path string, format string, args ...any) error {
+	return fmt.Errorf("%w: %s: %s", ErrInvalidDocument, path, fmt.Sprintf(format, args...))
+}
+
+// Validate checks that the document matches the collection schema.
+// It checks types and formats of the fields, required and primary key fields,
+// and rejects the fields which are not in the schema, unless the object allows additional properties.
+// Null values are accepted for the fields which are not required.
+func Validate(sch *schema.Schema, doc json.RawMessage) error {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+
+	if m == nil {
+		return fmt.Errorf("%w: expected object", ErrInvalidDocument)
+	}
+
+	required := append([]string{}, sch.Required...)
+
+	for _, k := range sch.PrimaryKey {
+		if f, _ := LookupField(sch.Fields, k); f == nil || !f.AutoGenerate {
+			required = append(required, k)
+		}
+	}
+
+	for _, path := range required {
+		if lookupValue(m, path) == nil {
+			return invalidDocument(path, "required field is missing")
+		}
+	}
+
+	return validateObject("", sch.Fields, nil, false, m)
+}
+
+func lookupValue(doc map[string]any, path string) any {
+	var v any = doc
+
+	for _, n := range strings.Split(path, PathSeparator) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		v = m[n]
+	}
+
+	return v
+}
+
+func validateObject(path string, fields map[string]*schema.Field, required []string, additional bool,
+	m map[string]any,
+) error {
+	for _, r := range required {
+		if m[r] == nil {
+			return invalidDocument(JoinPath(path, r), "required field is missing")
+		}
+	}
+
+	for k, v := range m {
+		f := fields[k]
+		if f == nil {
+			if additional {
+				continue
+			}
+
+			return invalidDocument(JoinPath(path, k), "field is not in the schema")
+		}
+
+		if err := validateValue(JoinPath(path, k), f, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//nolint:cyclop
+func validateValue(path string, f *schema.Field, v any) error {
+	if v == nil {
+		return nil
+	}
+
+	switch tp := f.Type.First(); tp {
+	case typeString:
+		s, ok := v.(string)
+		if !ok {
+			return invalidDocument(path, "expected %s", tp)
+		}
+
+		return validateString(path, f, s)
+	case typeInteger:
+		if n, ok := v.(json.Number); !ok {
+			return invalidDocument(path, "expected %s", tp)
+		} else if _, err := n.Int64(); err != nil {
+			return invalidDocument(path, "expected %s, got %s", tp, n)
+		}
+	case typeNumber:
+		if _, ok := v.(json.Number); !ok {
+			return invalidDocument(path, "expected %s", tp)
+		}
+	case typeBoolean:
+		if _, ok := v.(bool); !ok {
+			return invalidDocument(path, "expected %s", tp)
+		}
+	case typeObject:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return invalidDocument(path, "expected %s", tp)
+		}
+
+		// object without properties is unstructured
+		return validateObject(path, f.Fields, f.Required, f.AdditionalProperties || len(f.Fields) == 0, m)
+	case typeArray:
+		a, ok := v.([]any)
+		if !ok {
+			return invalidDocument(path, "expected %s", tp)
+		}
+
+		return validateArray(path, f, a)
+	}
+
+	return nil
+}
+
+func validateString(path string, f *schema.Field, s string) error {
+	var err error
+
+	switch f.Format {
+	case formatUUID:
+		_, err = uuid.Parse(s)
+	case formatDateTime:
+		_, err = time.Parse(time.RFC3339Nano, s)
+	case formatByte:
+		_, err = base64.StdEncoding.DecodeString(s)
+	}
+
+	if err != nil {
+		return invalidDocument(path, "invalid %s: %s", f.Format, s)
+	}
+
+	if f.MaxLength > 0 && utf8.RuneCountInString(s) > f.MaxLength {
+		return invalidDocument(path, "longer than %d characters", f.MaxLength)
+	}
+
+	return nil
+}
+
+func validateArray(path string, f *schema.Field, a []any) error {
+	if f.MaxItems > 0 && len(a) > f.MaxItems {
+		return invalidDocument(path, "more than %d items", f.MaxItems)
+	}
+
+	if f.Format == formatVector && f.Dimensions > 0 && len(a) != f.Dimensions {
+		return invalidDocument(path, "expected %d dimensions, got %d", f.Dimensions, len(a))
+	}
+
+	if f.Items == nil {
+		return nil
+	}
+
+	for i, v := range a {
+		if err := validateValue(fmt.Sprintf("%s[%d]", path, i), f.Items, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
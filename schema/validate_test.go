@@ -0,0 +1,91 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestValidate(t *testing.T) {
+	var sch schema.Schema
+
+	err := json.Unmarshal([]byte(`{
+		"title": "users",
+		"properties": {
+			"id": {"type": "integer"},
+			"uid": {"type": "string", "format": "uuid", "autoGenerate": true},
+			"name": {"type": "string", "maxLength": 5},
+			"balance": {"type": "number"},
+			"active": {"type": "boolean"},
+			"created": {"type": "string", "format": "date-time"},
+			"avatar": {"type": "string", "format": "byte"},
+			"address": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]},
+			"meta": {"type": "object"},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+			"vec": {"type": "array", "format": "vector", "dimensions": 2}
+		},
+		"primary_key": ["id", "uid"],
+		"required": ["name"]
+	}`), &sch)
+	require.NoError(t, err)
+
+	tests := []struct {
+		doc string
+		err string
+	}{
+		{`{"id": 1, "name": "Bob"}`, ""},
+		{
+			`{"id": 1, "name": "Alice", "balance": 1.5, "active": true, "created": "2023-01-02T03:04:05Z",
+			"avatar": "aGVsbG8=", "address": {"city": "SF"}, "meta": {"any": [1]}, "tags": ["a", "b"], "vec": [1, 2]}`,
+			"",
+		},
+		{`{"id": 1, "name": "Bob", "balance": null}`, ""},
+		{`{"name": "Bob"}`, "id: required field is missing"},
+		{`{"id": 1}`, "name: required field is missing"},
+		{`{"id": 1.5, "name": "Bob"}`, "id: expected integer, got 1.5"},
+		{`{"id": "1", "name": "Bob"}`, "id: expected integer"},
+		{`{"id": 1, "name": "Alice Smith"}`, "name: longer than 5 characters"},
+		{`{"id": 1, "name": "Bob", "uid": "123"}`, "uid: invalid uuid: 123"},
+		{`{"id": 1, "name": "Bob", "created": "2023-01-02"}`, "created: invalid date-time: 2023-01-02"},
+		{`{"id": 1, "name": "Bob", "active": 1}`, "active: expected boolean"},
+		{`{"id": 1, "name": "Bob", "address": {}}`, "address.city: required field is missing"},
+		{`{"id": 1, "name": "Bob", "address": {"city": "SF", "zip": "1"}}`, "address.zip: field is not in the schema"},
+		{`{"id": 1, "name": "Bob", "unknown": 1}`, "unknown: field is not in the schema"},
+		{`{"id": 1, "name": "Bob", "tags": ["a", 1]}`, "tags[1]: expected string"},
+		{`{"id": 1, "name": "Bob", "tags": ["a", "b", "c"]}`, "tags: more than 2 items"},
+		{`{"id": 1, "name": "Bob", "vec": [1]}`, "vec: expected 2 dimensions, got 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc, func(t *testing.T) {
+			err := Validate(&sch, json.RawMessage(tt.doc))
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrInvalidDocument)
+			assert.Equal(t, "document doesn't match the schema: "+tt.err, err.Error())
+		})
+	}
+
+	require.ErrorIs(t, Validate(&sch, json.RawMessage(`null`)), ErrInvalidDocument)
+	require.Error(t, Validate(&sch, json.RawMessage(`[1]`)))
+}
@@ -0,0 +1,39 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchutil
+
+import (
+	"github.com/tigrisdata/tigris-client-go/driver"
+	"github.com/tigrisdata/tigris-client-go/search"
+)
+
+// Pages requests the page of the request and, if all is set, the following pages,
+// until page returns an error or reports that there are no more pages.
+func Pages(req *driver.SearchRequest, all bool, page func(req *driver.SearchRequest) (bool, error)) error {
+	for {
+		more, err := page(req)
+		if err != nil || !more || !all {
+			return err
+		}
+
+		req.Page++
+	}
+}
+
+// More returns true if there are more pages after the page of the result.
+// Page without hits is the last one, even if the total number of pages is not reached.
+func More(page int32, r *search.Result[any]) bool {
+	return len(r.Hits) > 0 && page < r.Meta.TotalPages
+}
@@ -0,0 +1,87 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	"github.com/tigrisdata/tigris-client-go/search"
+)
+
+var errPage = fmt.Errorf("page error")
+
+func TestPages(t *testing.T) {
+	tests := []struct {
+		name  string
+		all   bool
+		pages []bool // more returned by the pages
+		fail  int32  // page which returns the error
+		exp   []int32
+		err   error
+	}{
+		{"single page", false, []bool{true, true}, 0, []int32{2}, nil},
+		{"all pages", true, []bool{true, true, false}, 0, []int32{2, 3, 4}, nil},
+		{"no more pages", true, []bool{false}, 0, []int32{2}, nil},
+		{"stopped by callback", true, []bool{true, false, true}, 0, []int32{2, 3}, nil},
+		{"error", true, []bool{true, true, true}, 3, []int32{2, 3}, errPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []int32
+
+			err := Pages(&driver.SearchRequest{Page: 2}, tt.all, func(req *driver.SearchRequest) (bool, error) {
+				requested = append(requested, req.Page)
+
+				if req.Page == tt.fail {
+					return true, errPage
+				}
+
+				return tt.pages[len(requested)-1], nil
+			})
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.exp, requested)
+		})
+	}
+}
+
+func TestMore(t *testing.T) {
+	hits := []search.Hit[any]{{}}
+
+	tests := []struct {
+		name string
+		page int32
+		hits []search.Hit[any]
+		of   int32
+		exp  bool
+	}{
+		{"more pages", 1, hits, 3, true},
+		{"last page", 3, hits, 3, false},
+		{"past last page", 4, hits, 3, false},
+		{"no hits", 1, nil, 3, false},
+		{"no results", 1, nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &search.Result[any]{Hits: tt.hits, Meta: search.Meta{TotalPages: tt.of}}
+			assert.Equal(t, tt.exp, More(tt.page, r))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchutil
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/tigrisdata/tigris-cli/render"
+	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+	"github.com/tigrisdata/tigris-client-go/search"
+)
+
+// Render writes the search result for the terminal:
+// facets as histograms on the first page and the hits as the table with highlighted query terms.
+// The score of the vector search hits is the vector distance.
+func Render(w io.Writer, req *driver.SearchRequest, hits []*api.SearchHit, r *search.Result[any], first bool) {
+	if first && len(r.Facets) > 0 {
+		render.Facets(w, r.Facets)
+		_, _ = fmt.Fprintln(w)
+	}
+
+	rows := make([]render.Hit, 0, len(r.Hits))
+
+	for i, h := range r.Hits {
+		var doc any
+		if h.Document != nil {
+			doc = *h.Document
+		}
+
+		match := hits[i].GetMetadata().GetMatch()
+
+		score := match.GetScore()
+		if len(req.Vector) > 0 {
+			score = strconv.FormatFloat(match.GetVectorDistance(), 'g', 6, 64)
+		}
+
+		rows = append(rows, render.Hit{Score: score, Document: doc})
+	}
+
+	var fields []string
+
+	for _, f := range append(append([]string{}, req.SearchFields...), req.IncludeFields...) {
+		if !util.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+
+	render.Hits(w, rows, fields, req.SearchFields, render.Terms(req.Q))
+
+	_, _ = fmt.Fprintf(w, "\npage %d of %d, %d found\n", r.Meta.Page.Current, r.Meta.TotalPages, r.Meta.Found)
+}
@@ -0,0 +1,70 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchutil
+
+import (
+	"encoding/json"
+
+	"github.com/tigrisdata/tigris-cli/vector"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+// Options are the search flags of the search commands.
+type Options struct {
+	Query         string
+	SearchFields  []string
+	Filter        string
+	Facet         string
+	Sort          []string
+	IncludeFields []string
+	ExcludeFields []string
+	Page          int32
+	PageSize      int32
+
+	Vectors    []string
+	VectorFile string
+	Neighbors  int32
+}
+
+// Request returns the search request of the options.
+func (o *Options) Request() (*driver.SearchRequest, error) {
+	var sortArr driver.SortOrder
+	for _, v := range o.Sort {
+		sortArr = append(sortArr, json.RawMessage(v))
+	}
+
+	req := &driver.SearchRequest{
+		Q:             o.Query,
+		SearchFields:  o.SearchFields,
+		Filter:        driver.Filter(o.Filter),
+		Facet:         driver.Facet(o.Facet),
+		Sort:          sortArr,
+		IncludeFields: o.IncludeFields,
+		ExcludeFields: o.ExcludeFields,
+		Page:          o.Page,
+		PageSize:      o.PageSize,
+	}
+
+	v, err := vector.Parse(o.Vectors, o.VectorFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = v.Apply(req, o.Neighbors); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package searchutil contains the helpers shared by the search commands.
+package searchutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var ErrDocumentsFailed = fmt.Errorf("documents failed")
+
+// StatusError returns the error listing the documents which failed.
+func StatusError(statuses []*driver.DocStatus) error {
+	var failed []string
+
+	for _, s := range statuses {
+		if s.GetError() != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", s.GetId(), s.GetError().GetMessage()))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d of %d\n%s", ErrDocumentsFailed, len(failed), len(statuses),
+		strings.Join(failed, "\n"))
+}
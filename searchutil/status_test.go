@@ -0,0 +1,38 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+func TestStatusError(t *testing.T) {
+	require.NoError(t, StatusError(nil))
+	require.NoError(t, StatusError([]*driver.DocStatus{{Id: "1"}, {Id: "2"}}))
+
+	err := StatusError([]*driver.DocStatus{
+		{Id: "1"},
+		{Id: "2", Error: &api.Error{Message: "invalid field"}},
+		{Id: "3"},
+		{Id: "4", Error: &api.Error{Message: "too large"}},
+	})
+	require.ErrorIs(t, err, ErrDocumentsFailed)
+	assert.Equal(t, "documents failed: 2 of 4\n2: invalid field\n4: too large", err.Error())
+}
@@ -0,0 +1,116 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// wordSeparators end the completed word, besides whitespace,
+// so the field names are completed inside JSON and filter expressions.
+const wordSeparators = "{}[](),:'\"`=<>!~"
+
+// Completion completes the word before the cursor position.
+// Commands are completed in the first word of the statement and the other words are completed from words,
+// which are the collection and field names.
+// Returns the line and cursor position after the completion and the sorted words matching the prefix.
+func Completion(line string, pos int, commands []string, words []string) (string, int, []string) {
+	start := pos
+	for start > 0 && !isSeparator(rune(line[start-1])) {
+		start--
+	}
+
+	prefix := line[start:pos]
+
+	first := strings.TrimSpace(line[:start]) == ""
+
+	candidates := words
+	if first {
+		candidates = commands
+	}
+
+	var matches []string
+
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+
+	if len(matches) == 0 {
+		return line, pos, nil
+	}
+
+	sort.Strings(matches)
+
+	common := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	if len(matches) == 1 && first {
+		common += " "
+	}
+
+	return line[:start] + common + line[pos:], start + len(common), matches
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(wordSeparators, r)
+}
+
+// Fields returns the sorted names of the fields of the collection JSON schema.
+// Nested fields are named with dots, like "address.city".
+func Fields(schema []byte) ([]string, error) {
+	var s jsonSchema
+
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, err
+	}
+
+	var res []string
+
+	s.fields("", &res)
+
+	sort.Strings(res)
+
+	return res, nil
+}
+
+type jsonSchema struct {
+	Properties map[string]*jsonSchema `json:"properties"`
+	Items      *jsonSchema            `json:"items"`
+}
+
+func (s *jsonSchema) fields(prefix string, res *[]string) {
+	for name, p := range s.Properties {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		*res = append(*res, name)
+
+		for p.Items != nil {
+			p = p.Items
+		}
+
+		p.fields(name, res)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp []string
+	}{
+		{"", nil},
+		{"  read   users ", []string{"read", "users"}},
+		{`read users '{"id": 1}'`, []string{"read", "users", `{"id": 1}`}},
+		{`read users "{\"id\": 1}"`, []string{"read", "users", `{"id": 1}`}},
+		{
+			`insert users {"name": "Alice Smith", "tags": ["a b"]}`,
+			[]string{"insert", "users", `{"name": "Alice Smith", "tags": ["a b"]}`},
+		},
+		{
+			"update users {\"id\": 1}\n{\n  \"$set\": {\"a\": \"}\"}\n}",
+			[]string{"update", "users", `{"id": 1}`, "{\n  \"$set\": {\"a\": \"}\"}\n}"},
+		},
+		{`read users --where "name = 'Bob'"`, []string{"read", "users", "--where", "name = 'Bob'"}},
+		{`a\ b c\` + "\n" + `d`, []string{"a b", "cd"}},
+		{`--where=a'b c'`, []string{"--where=ab c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			args, err := Split(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, args)
+			assert.True(t, Complete(tt.in))
+		})
+	}
+}
+
+func TestSplitUnterminated(t *testing.T) {
+	for _, in := range []string{
+		`read users '{"id": 1}`,
+		`read users "abc`,
+		`insert users {"id": 1`,
+		`insert users [{"id": 1}, {"name": "}"`,
+		`read users \`,
+	} {
+		t.Run(in, func(t *testing.T) {
+			_, err := Split(in)
+			require.ErrorIs(t, err, ErrUnterminated)
+			assert.False(t, Complete(in))
+		})
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	commands := []string{"read", "replace", "insert"}
+	words := []string{"users", "name", "address.city", "address.zip"}
+
+	tests := []struct {
+		line    string
+		pos     int
+		exp     string
+		expPos  int
+		matches []string
+	}{
+		{"rea", 3, "read ", 5, []string{"read"}},
+		{"re", 2, "re", 2, []string{"read", "replace"}},
+		{"read us", 7, "read users", 10, []string{"users"}},
+		{`read users {"na`, 15, `read users {"name`, 17, []string{"name"}},
+		{`read users {"addr": 1}`, 17, `read users {"address.": 1}`, 21, []string{"address.city", "address.zip"}},
+		{"read users --where 'address.c", 29, "read users --where 'address.city", 32, []string{"address.city"}},
+		{"read xyz", 8, "read xyz", 8, nil},
+		{"us", 2, "us", 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			line, pos, matches := Completion(tt.line, tt.pos, commands, words)
+			assert.Equal(t, tt.exp, line)
+			assert.Equal(t, tt.expPos, pos)
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	schema := `{
+		"title": "users",
+		"properties": {
+			"id": {"type": "integer"},
+			"address": {"type": "object", "properties": {"city": {"type": "string"}}},
+			"orders": {"type": "array", "items": {"type": "object", "properties": {"total": {"type": "number"}}}},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		}
+	}`
+
+	fields, err := Fields([]byte(schema))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"address", "address.city", "id", "orders", "orders.total", "tags"}, fields)
+
+	_, err = Fields([]byte(`{`))
+	require.Error(t, err)
+}
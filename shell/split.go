@@ -0,0 +1,160 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shell implements parsing and completion of the interactive shell input.
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrUnterminated = fmt.Errorf("unterminated input")
+
+// Split splits the statement into the command arguments, like POSIX shell does:
+// words are separated by whitespace, 'single' and "double" quotes group the words,
+// backslash escapes the next character and backslash at the end of line continues the statement.
+// Unquoted JSON objects and arrays are kept as a single argument, so the documents
+// can be typed without quoting: insert users {"name": "Alice"}.
+// Returns ErrUnterminated if the statement is incomplete.
+func Split(s string) ([]string, error) {
+	src := []rune(s)
+
+	var (
+		res  []string
+		word strings.Builder
+		in   bool
+	)
+
+	for i := 0; i < len(src); i++ {
+		r := src[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			if in {
+				res = append(res, word.String())
+				word.Reset()
+				in = false
+			}
+
+			continue
+		case r == '\\':
+			if i+1 == len(src) {
+				return nil, ErrUnterminated
+			}
+
+			i++
+			if src[i] == '\n' {
+				continue
+			}
+
+			word.WriteRune(src[i])
+		case r == '\'':
+			end := indexRune(src, i+1, '\'')
+			if end < 0 {
+				return nil, ErrUnterminated
+			}
+
+			word.WriteString(string(src[i+1 : end]))
+			i = end
+		case r == '"':
+			end, err := doubleQuoted(src, i, &word)
+			if err != nil {
+				return nil, err
+			}
+
+			i = end
+		case (r == '{' || r == '[') && !in:
+			end := jsonEnd(src, i)
+			if end < 0 {
+				return nil, ErrUnterminated
+			}
+
+			word.WriteString(string(src[i : end+1]))
+			i = end
+		default:
+			word.WriteRune(r)
+		}
+
+		in = true
+	}
+
+	if in {
+		res = append(res, word.String())
+	}
+
+	return res, nil
+}
+
+// Complete returns false if the statement continues on the next line.
+func Complete(s string) bool {
+	_, err := Split(s)
+
+	return !errors.Is(err, ErrUnterminated)
+}
+
+func indexRune(src []rune, from int, r rune) int {
+	for i := from; i < len(src); i++ {
+		if src[i] == r {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// doubleQuoted writes the content of the double-quoted string starting at pos
+// and returns the position of the closing quote.
+func doubleQuoted(src []rune, pos int, w *strings.Builder) (int, error) {
+	for i := pos + 1; i < len(src); i++ {
+		switch r := src[i]; {
+		case r == '\\' && i+1 < len(src) && (src[i+1] == '"' || src[i+1] == '\\'):
+			i++
+			w.WriteRune(src[i])
+		case r == '"':
+			return i, nil
+		default:
+			w.WriteRune(r)
+		}
+	}
+
+	return 0, ErrUnterminated
+}
+
+// jsonEnd returns the position of the bracket closing the JSON object or array starting at pos,
+// or -1 if it is not closed.
+func jsonEnd(src []rune, pos int) int {
+	depth := 0
+
+	for i := pos; i < len(src); i++ {
+		switch src[i] {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth--; depth == 0 {
+				return i
+			}
+		case '"':
+			for i++; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,219 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/tigrisdata/tigris-cli/migrate"
+	"github.com/tigrisdata/tigris-cli/schema"
+	cschema "github.com/tigrisdata/tigris-client-go/schema"
+)
+
+type ResourceKind string
+
+const (
+	KindBranch     ResourceKind = "branch"
+	KindCollection ResourceKind = "collection"
+	KindIndex      ResourceKind = "index"
+	KindAppKey     ResourceKind = "app_key"
+)
+
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// DefaultBranch is never pruned.
+const DefaultBranch = "main"
+
+// Change is a single action required to bring the project to the desired state.
+type Change struct {
+	Kind    ResourceKind     `json:"kind"`
+	Name    string           `json:"name"`
+	Action  Action           `json:"action"`
+	Details []*schema.Change `json:"details,omitempty"`
+
+	// Desired schema of the created or updated collection or index.
+	Schema json.RawMessage `json:"-"`
+	// Desired app key for created and updated keys, existing key for deleted.
+	AppKey *AppKey `json:"-"`
+}
+
+func (c *Change) String() string {
+	sign := "+"
+
+	switch c.Action {
+	case Update:
+		sign = "~"
+	case Delete:
+		sign = "-"
+	}
+
+	return fmt.Sprintf("%s %s %s", sign, c.Kind, c.Name)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func unmarshalSchema(raw json.RawMessage) (*cschema.Schema, error) {
+	var sch cschema.Schema
+
+	if err := json.Unmarshal(raw, &sch); err != nil {
+		return nil, err
+	}
+
+	return &sch, nil
+}
+
+// planSchemas compares desired and live schemas field by field.
+func planSchemas(kind ResourceKind, desired map[string]json.RawMessage, live map[string]json.RawMessage,
+) ([]*Change, error) {
+	var res []*Change
+
+	for _, name := range sortedKeys(desired) {
+		l, ok := live[name]
+		if !ok {
+			res = append(res, &Change{Kind: kind, Name: name, Action: Create, Schema: desired[name]})
+			continue
+		}
+
+		n, err := unmarshalSchema(desired[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s", err, kind, name)
+		}
+
+		o, err := unmarshalSchema(l)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s", err, kind, name)
+		}
+
+		if details := schema.Diff(o, n); len(details) > 0 {
+			res = append(res, &Change{Kind: kind, Name: name, Action: Update, Schema: desired[name], Details: details})
+		}
+	}
+
+	return res, nil
+}
+
+func planPrune[T any](kind ResourceKind, desired map[string]T, live map[string]T, protected string) []*Change {
+	var res []*Change
+
+	for _, name := range sortedKeys(live) {
+		if _, ok := desired[name]; !ok && name != protected {
+			res = append(res, &Change{Kind: kind, Name: name, Action: Delete})
+		}
+	}
+
+	return res
+}
+
+func planAppKeys(desired map[string]*AppKey, live map[string]*AppKey) []*Change {
+	var res []*Change
+
+	for _, name := range sortedKeys(desired) {
+		d := desired[name]
+
+		l, ok := live[name]
+		if !ok {
+			res = append(res, &Change{Kind: KindAppKey, Name: name, Action: Create, AppKey: d})
+			continue
+		}
+
+		if l.Description != d.Description {
+			res = append(res, &Change{
+				Kind: KindAppKey, Name: name, Action: Update,
+				AppKey: &AppKey{ID: l.ID, Name: name, Description: d.Description},
+			})
+		}
+	}
+
+	return res
+}
+
+// currentAppKey returns the name of the live app key with the given id.
+func currentAppKey(keys map[string]*AppKey, id string) string {
+	if id == "" {
+		return ""
+	}
+
+	for name, k := range keys {
+		if k.ID == id {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Plan returns changes required to bring live project to the desired state.
+// Resources which are not declared are deleted only if prune is set.
+// The default branch, the migrations collection and the app key with currentKeyID,
+// which the CLI is authenticated with, are never deleted.
+//
+// Changes are ordered so that resources are created before their dependants
+// and deleted in the reverse order.
+func Plan(desired *Project, live *Project, prune bool, currentKeyID string) ([]*Change, error) {
+	var res []*Change
+
+	for _, name := range sortedKeys(desired.Branches) {
+		if !live.Branches[name] {
+			res = append(res, &Change{Kind: KindBranch, Name: name, Action: Create})
+		}
+	}
+
+	colls, err := planSchemas(KindCollection, desired.Collections, live.Collections)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := planSchemas(KindIndex, desired.Indexes, live.Indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	res = append(res, colls...)
+	res = append(res, indexes...)
+	res = append(res, planAppKeys(desired.AppKeys, live.AppKeys)...)
+
+	if !prune {
+		return res, nil
+	}
+
+	keys := planPrune(KindAppKey, desired.AppKeys, live.AppKeys, currentAppKey(live.AppKeys, currentKeyID))
+	for _, v := range keys {
+		v.AppKey = live.AppKeys[v.Name]
+	}
+
+	res = append(res, keys...)
+	res = append(res, planPrune(KindIndex, desired.Indexes, live.Indexes, "")...)
+	res = append(res, planPrune(KindCollection, desired.Collections, live.Collections, migrate.Collection)...)
+	res = append(res, planPrune(KindBranch, desired.Branches, live.Branches, DefaultBranch)...)
+
+	return res, nil
+}
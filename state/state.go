@@ -0,0 +1,267 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package state implements declarative description of the project resources.
+//
+// The desired state is described by the set of YAML or JSON files in the directory.
+// Every file can declare any of the resources:
+//
+//	collections:
+//	  - title: users
+//	    properties:
+//	      id: { type: integer }
+//	    primary_key: [ id ]
+//	indexes:
+//	  - title: products
+//	    properties:
+//	      name: { type: string }
+//	branches: [ staging ]
+//	app_keys:
+//	  - name: ci
+//	    description: Key used by CI
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	ErrNameMissing      = fmt.Errorf("resource name is missing")
+	ErrDuplicate        = fmt.Errorf("duplicate resource declaration")
+	ErrUnsupportedValue = fmt.Errorf("unsupported value type")
+)
+
+// AppKey is the declared application key.
+// ID is only known for the keys existing in the project.
+type AppKey struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// File is the format of the single state file.
+type File struct {
+	Collections []json.RawMessage `json:"collections,omitempty"`
+	Indexes     []json.RawMessage `json:"indexes,omitempty"`
+	Branches    []string          `json:"branches,omitempty"`
+	AppKeys     []*AppKey         `json:"app_keys,omitempty"`
+}
+
+// Project is the set of project resources keyed by name.
+type Project struct {
+	Collections map[string]json.RawMessage
+	Indexes     map[string]json.RawMessage
+	Branches    map[string]bool
+	AppKeys     map[string]*AppKey
+}
+
+func NewProject() *Project {
+	return &Project{
+		Collections: make(map[string]json.RawMessage),
+		Indexes:     make(map[string]json.RawMessage),
+		Branches:    make(map[string]bool),
+		AppKeys:     make(map[string]*AppKey),
+	}
+}
+
+// SchemaName returns the title of the collection or index schema.
+func SchemaName(raw json.RawMessage) (string, error) {
+	var sch struct {
+		Name string `json:"title"`
+	}
+
+	if err := json.Unmarshal(raw, &sch); err != nil {
+		return "", err
+	}
+
+	if sch.Name == "" {
+		return "", ErrNameMissing
+	}
+
+	return sch.Name, nil
+}
+
+func addSchemas(res map[string]json.RawMessage, kind ResourceKind, schemas []json.RawMessage) error {
+	for _, v := range schemas {
+		name, err := SchemaName(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", kind, err)
+		}
+
+		if _, ok := res[name]; ok {
+			return fmt.Errorf("%w: %s %s", ErrDuplicate, kind, name)
+		}
+
+		res[name] = v
+	}
+
+	return nil
+}
+
+// Add merges resources declared in the file into the project.
+func (p *Project) Add(f *File) error {
+	if err := addSchemas(p.Collections, KindCollection, f.Collections); err != nil {
+		return err
+	}
+
+	if err := addSchemas(p.Indexes, KindIndex, f.Indexes); err != nil {
+		return err
+	}
+
+	for _, v := range f.Branches {
+		if p.Branches[v] {
+			return fmt.Errorf("%w: %s %s", ErrDuplicate, KindBranch, v)
+		}
+
+		p.Branches[v] = true
+	}
+
+	for _, v := range f.AppKeys {
+		if v.Name == "" {
+			return fmt.Errorf("%s: %w", KindAppKey, ErrNameMissing)
+		}
+
+		if _, ok := p.AppKeys[v.Name]; ok {
+			return fmt.Errorf("%w: %s %s", ErrDuplicate, KindAppKey, v.Name)
+		}
+
+		p.AppKeys[v.Name] = v
+	}
+
+	return nil
+}
+
+// yamlToJSON converts YAML maps, which can have non-string keys, to JSON compatible values.
+func yamlToJSON(v any) (any, error) {
+	switch t := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(t))
+
+		for k, val := range t {
+			ks, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: map key %v", ErrUnsupportedValue, k)
+			}
+
+			conv, err := yamlToJSON(val)
+			if err != nil {
+				return nil, err
+			}
+
+			m[ks] = conv
+		}
+
+		return m, nil
+	case []any:
+		for i, val := range t {
+			conv, err := yamlToJSON(val)
+			if err != nil {
+				return nil, err
+			}
+
+			t[i] = conv
+		}
+	}
+
+	return v, nil
+}
+
+// ParseFile parses state file in YAML or JSON format.
+func ParseFile(name string, body []byte) (*File, error) {
+	if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+		var v any
+
+		if err := yaml.Unmarshal(body, &v); err != nil {
+			return nil, err
+		}
+
+		v, err := yamlToJSON(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if body, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var f File
+
+	if err := json.Unmarshal(body, &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func isStateFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+
+	return false
+}
+
+// Load reads the desired project state from the files in the directory and its subdirectories.
+func Load(dir string) (*Project, error) {
+	var names []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && isStateFile(path) {
+			names = append(names, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	p := NewProject()
+
+	for _, name := range names {
+		body, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := ParseFile(name, body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, name)
+		}
+
+		if err = p.Add(f); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, name)
+		}
+
+		log.Debug().Str("file", name).Msg("loaded state file")
+	}
+
+	return p, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//nolint:funlen
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/migrate"
+	"github.com/tigrisdata/tigris-cli/schema"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "collections"), 0o700))
+
+	files := map[string]string{
+		"project.yaml": `
+branches: [ staging ]
+app_keys:
+  - name: ci
+    description: CI key
+indexes:
+  - title: products
+    properties:
+      name: { type: string }
+`,
+		"collections/users.json": `{ "collections": [
+			{ "title": "users", "properties": { "id": { "type": "integer" } }, "primary_key": ["id"] }
+		] }`,
+		"README.md": `ignored`,
+	}
+
+	for name, body := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600))
+	}
+
+	p, err := Load(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"staging": true}, p.Branches)
+	assert.Equal(t, map[string]*AppKey{"ci": {Name: "ci", Description: "CI key"}}, p.AppKeys)
+	require.Contains(t, p.Collections, "users")
+	require.Contains(t, p.Indexes, "products")
+	assert.JSONEq(t, `{"title":"products","properties":{"name":{"type":"string"}}}`, string(p.Indexes["products"]))
+}
+
+func TestProjectAddNegative(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		err  error
+	}{
+		{"no_title", `{ "collections": [ { "properties": {} } ] }`, ErrNameMissing},
+		{"dup_coll", `{ "collections": [ { "title": "c1" }, { "title": "c1" } ] }`, ErrDuplicate},
+		{"dup_index", `{ "indexes": [ { "title": "i1" }, { "title": "i1" } ] }`, ErrDuplicate},
+		{"dup_branch", `{ "branches": [ "b1", "b1" ] }`, ErrDuplicate},
+		{"no_key_name", `{ "app_keys": [ { "description": "d1" } ] }`, ErrNameMissing},
+		{"dup_key", `{ "app_keys": [ { "name": "k1" }, { "name": "k1" } ] }`, ErrDuplicate},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := ParseFile("state.json", []byte(c.file))
+			require.NoError(t, err)
+
+			assert.ErrorIs(t, NewProject().Add(f), c.err)
+		})
+	}
+
+	_, err := ParseFile("state.yaml", []byte("collections:\n  - 1: 2\n"))
+	assert.ErrorIs(t, err, ErrUnsupportedValue)
+}
+
+func TestPlan(t *testing.T) {
+	sch := func(s string) json.RawMessage { return json.RawMessage(s) }
+
+	desired := NewProject()
+	desired.Branches["staging"] = true
+	desired.Collections["c1"] = sch(`{ "title": "c1", "properties": { "f1": { "type": "integer" } } }`)
+	desired.Collections["c2"] = sch(`{ "title": "c2", "properties": { "f1": { "type": "string" } } }`)
+	desired.Collections["c3"] = sch(`{ "title": "c3", "properties": { "f1": { "type": "string" } } }`)
+	desired.Indexes["i1"] = sch(`{ "title": "i1", "properties": { "f1": { "type": "string" } } }`)
+	desired.AppKeys["k1"] = &AppKey{Name: "k1", Description: "new"}
+	desired.AppKeys["k2"] = &AppKey{Name: "k2"}
+
+	live := NewProject()
+	live.Branches["main"] = true
+	live.Branches["old"] = true
+	live.Collections["c1"] = sch(`{ "title": "c1", "properties": { "f1": { "type": "integer" } } }`)
+	live.Collections["c2"] = sch(`{ "title": "c2", "properties": {
+		"f1": { "type": "string" }, "f2": { "type": "string" }
+	} }`)
+	live.Collections["old"] = sch(`{ "title": "old" }`)
+	live.Collections[migrate.Collection] = sch(`{ "title": "` + migrate.Collection + `" }`)
+	live.Indexes["old"] = sch(`{ "title": "old" }`)
+	live.AppKeys["k1"] = &AppKey{ID: "id1", Name: "k1", Description: "old"}
+	live.AppKeys["old"] = &AppKey{ID: "id2", Name: "old"}
+
+	exp := []*Change{
+		{Kind: KindBranch, Name: "staging", Action: Create},
+		{
+			Kind: KindCollection, Name: "c2", Action: Update, Schema: desired.Collections["c2"],
+			Details: []*schema.Change{{
+				Collection: "c2", Field: "f2", Kind: schema.FieldRemoved, Old: "string", Severity: schema.Breaking,
+			}},
+		},
+		{Kind: KindCollection, Name: "c3", Action: Create, Schema: desired.Collections["c3"]},
+		{Kind: KindIndex, Name: "i1", Action: Create, Schema: desired.Indexes["i1"]},
+		{Kind: KindAppKey, Name: "k1", Action: Update, AppKey: &AppKey{ID: "id1", Name: "k1", Description: "new"}},
+		{Kind: KindAppKey, Name: "k2", Action: Create, AppKey: desired.AppKeys["k2"]},
+	}
+
+	res, err := Plan(desired, live, false, "")
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+
+	exp = append(exp,
+		&Change{Kind: KindAppKey, Name: "old", Action: Delete, AppKey: live.AppKeys["old"]},
+		&Change{Kind: KindIndex, Name: "old", Action: Delete},
+		&Change{Kind: KindCollection, Name: "old", Action: Delete},
+		&Change{Kind: KindBranch, Name: "old", Action: Delete},
+	)
+
+	res, err = Plan(desired, live, true, "")
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+
+	// the key the CLI is authenticated with is not pruned
+	res, err = Plan(desired, live, true, "id2")
+	require.NoError(t, err)
+	assert.Equal(t, append(append([]*Change{}, exp[:len(exp)-4]...), exp[len(exp)-3:]...), res)
+
+	res, err = Plan(live, live, true, "")
+	require.NoError(t, err)
+	assert.Empty(t, res)
+}
@@ -0,0 +1,408 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	OutputJSON     = "json"
+	OutputNDJSON   = "ndjson"
+	OutputYAML     = "yaml"
+	OutputTable    = "table"
+	OutputCSV      = "csv"
+	OutputTemplate = "go-template="
+
+	// valueColumn is the table and CSV column name of the documents which are not JSON objects.
+	valueColumn = "value"
+)
+
+var (
+	// OutputFormat is set by the global --output flag.
+	OutputFormat string
+
+	ErrUnknownOutput = fmt.Errorf("unknown output format. supported: json, ndjson, yaml, table, csv, go-template=...")
+)
+
+// Output writes documents in the format requested by the --output flag.
+// JSON, table and CSV outputs are buffered until Flush,
+// because they need all the documents to produce array brackets and columns.
+type Output struct {
+	w      io.Writer
+	format string
+	tmpl   *template.Template
+	single bool
+	cols   []string
+
+	raw  []json.RawMessage
+	rows []map[string]any
+}
+
+// NewOutput returns the writer of the list of documents.
+// The defaultFormat is used when --output flag is not set.
+func NewOutput(w io.Writer, defaultFormat string) (*Output, error) {
+	format := OutputFormat
+	if format == "" {
+		format = defaultFormat
+	}
+
+	o := &Output{w: w, format: format}
+
+	switch {
+	case format == OutputJSON, format == OutputNDJSON, format == OutputYAML,
+		format == OutputTable, format == OutputCSV:
+	case strings.HasPrefix(format, OutputTemplate):
+		t, err := template.New("output").Parse(strings.TrimPrefix(format, OutputTemplate))
+		if err != nil {
+			return nil, err
+		}
+
+		o.tmpl = t
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOutput, format)
+	}
+
+	return o, nil
+}
+
+// PrintOutput writes single document to stdout.
+// Unlike the list written by NewOutput, JSON output is not wrapped into array.
+func PrintOutput(defaultFormat string, doc any) error {
+	o, err := NewOutput(os.Stdout, defaultFormat)
+	if err != nil {
+		return err
+	}
+
+	o.single = true
+
+	if err = o.Write(doc); err != nil {
+		return err
+	}
+
+	return o.Flush()
+}
+
+// SetColumns sets the order of table and CSV columns.
+// By default, columns are the sorted union of the document fields.
+func (o *Output) SetColumns(cols []string) {
+	o.cols = cols
+}
+
+// Write outputs the document or buffers it until Flush.
+// The document is either json.RawMessage or a value which can be marshalled to JSON.
+func (o *Output) Write(doc any) error {
+	raw, ok := doc.(json.RawMessage)
+	if !ok {
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+
+		raw = b
+	}
+
+	switch o.format {
+	case OutputJSON:
+		o.raw = append(o.raw, raw)
+
+		return nil
+	case OutputNDJSON:
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			return err
+		}
+
+		_, err := fmt.Fprintf(o.w, "%s\n", buf.String())
+
+		return err
+	}
+
+	v, err := decodeValue(raw)
+	if err != nil {
+		return err
+	}
+
+	switch o.format {
+	case OutputYAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(o.w, "---\n%s", b)
+
+		return err
+	case OutputTable, OutputCSV:
+		o.rows = append(o.rows, FlattenDoc(v))
+
+		return nil
+	}
+
+	if err = o.tmpl.Execute(o.w, v); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(o.w)
+
+	return err
+}
+
+// Flush writes buffered documents.
+func (o *Output) Flush() error {
+	switch o.format {
+	case OutputJSON:
+		return o.flushJSON()
+	case OutputTable:
+		return o.flushTable()
+	case OutputCSV:
+		return o.flushCSV()
+	}
+
+	return nil
+}
+
+func (o *Output) flushJSON() error {
+	var buf bytes.Buffer
+
+	if o.single && len(o.raw) == 1 {
+		if err := json.Indent(&buf, o.raw[0], "", "  "); err != nil {
+			return err
+		}
+	} else {
+		if o.raw == nil {
+			o.raw = []json.RawMessage{}
+		}
+
+		b, err := json.MarshalIndent(o.raw, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		buf.Write(b)
+	}
+
+	o.raw = nil
+
+	_, err := fmt.Fprintf(o.w, "%s\n", buf.String())
+
+	return err
+}
+
+func (o *Output) flushTable() error {
+	cols := o.columns()
+	if len(cols) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, strings.Join(cols, "\t"))
+
+	for _, row := range o.rows {
+		vals := make([]string, 0, len(cols))
+		for _, c := range cols {
+			vals = append(vals, FormatValue(row[c], ""))
+		}
+
+		_, _ = fmt.Fprintln(w, strings.Join(vals, "\t"))
+	}
+
+	o.rows = nil
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// trailing empty cells are padded by tabwriter
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line == "" {
+			continue
+		}
+
+		if _, err := fmt.Fprintln(o.w, strings.TrimRight(line, " \n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Output) flushCSV() error {
+	cols := o.columns()
+	if len(cols) == 0 {
+		return nil
+	}
+
+	w := csv.NewWriter(o.w)
+
+	if err := w.Write(cols); err != nil {
+		return err
+	}
+
+	for _, row := range o.rows {
+		vals := make([]string, 0, len(cols))
+		for _, c := range cols {
+			v, ok := row[c]
+			if !ok {
+				vals = append(vals, "")
+				continue
+			}
+
+			// null is recognized by CSV import
+			vals = append(vals, FormatValue(v, "null"))
+		}
+
+		if err := w.Write(vals); err != nil {
+			return err
+		}
+	}
+
+	o.rows = nil
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func decodeValue(raw json.RawMessage) (any, error) {
+	var v any
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// FlattenDoc converts nested objects to the single level object with dotted field names,
+// like {"address.city": "..."}, same as the CSV column names recognized by import.
+// Arrays are not flattened. Non-object values are returned in the "value" field.
+func FlattenDoc(doc any) map[string]any {
+	res := make(map[string]any)
+
+	m, ok := doc.(map[string]any)
+	if !ok {
+		res[valueColumn] = doc
+
+		return res
+	}
+
+	flatten(res, "", m)
+
+	return res
+}
+
+func flatten(res map[string]any, prefix string, m map[string]any) {
+	for k, v := range m {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			flatten(res, k, nested)
+		} else {
+			res[k] = v
+		}
+	}
+}
+
+func (o *Output) columns() []string {
+	if o.cols != nil {
+		return o.cols
+	}
+
+	return columns(o.rows)
+}
+
+// columns returns sorted union of the field names of the rows.
+func columns(rows []map[string]any) []string {
+	set := make(map[string]bool)
+
+	for _, row := range rows {
+		for k := range row {
+			set[k] = true
+		}
+	}
+
+	cols := make([]string, 0, len(set))
+	for k := range set {
+		cols = append(cols, k)
+	}
+
+	sort.Strings(cols)
+
+	return cols
+}
+
+// FormatValue formats the scalar value of the document field as a table or CSV cell.
+// Arrays and objects are formatted as JSON.
+func FormatValue(v any, null string) string {
+	switch v := v.(type) {
+	case nil:
+		return null
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprintf("%v", v)
+	}
+
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
+
+// PrintList writes names one per line, or, when --output flag is set,
+// as the documents with the single field, like {"name": "users"}.
+func PrintList(field string, names []string) error {
+	if OutputFormat == "" {
+		for _, v := range names {
+			Stdoutf("%s\n", v)
+		}
+
+		return nil
+	}
+
+	o, err := NewOutput(os.Stdout, OutputJSON)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range names {
+		if err = o.Write(map[string]string{field: v}); err != nil {
+			return err
+		}
+	}
+
+	return o.Flush()
+}
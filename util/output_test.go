@@ -0,0 +1,175 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutput(t *testing.T) {
+	docs := []any{
+		json.RawMessage(`{"id": 1, "name": "Alice", "address": {"city": "SF", "zip": null}, "tags": ["a", "b"]}`),
+		map[string]any{"id": 2, "name": "Bob, Jr.", "active": true},
+	}
+
+	tests := []struct {
+		format string
+		exp    string
+	}{
+		{
+			OutputJSON, `[
+  {
+    "id": 1,
+    "name": "Alice",
+    "address": {
+      "city": "SF",
+      "zip": null
+    },
+    "tags": [
+      "a",
+      "b"
+    ]
+  },
+  {
+    "active": true,
+    "id": 2,
+    "name": "Bob, Jr."
+  }
+]
+`,
+		},
+		{
+			OutputNDJSON, `{"id":1,"name":"Alice","address":{"city":"SF","zip":null},"tags":["a","b"]}
+{"active":true,"id":2,"name":"Bob, Jr."}
+`,
+		},
+		{
+			OutputYAML, `---
+address:
+  city: SF
+  zip: null
+id: 1
+name: Alice
+tags:
+- a
+- b
+---
+active: true
+id: 2
+name: Bob, Jr.
+`,
+		},
+		{
+			OutputTable, `active  address.city  address.zip  id  name      tags
+        SF                         1   Alice     ["a","b"]
+true                               2   Bob, Jr.
+`,
+		},
+		{
+			OutputCSV, `active,address.city,address.zip,id,name,tags
+,SF,null,1,Alice,"[""a"",""b""]"
+true,,,2,"Bob, Jr.",
+`,
+		},
+		{
+			OutputTemplate + `{{.id}}: {{.name}}`, `1: Alice
+2: Bob, Jr.
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			OutputFormat = tt.format
+			defer func() { OutputFormat = "" }()
+
+			var buf bytes.Buffer
+
+			o, err := NewOutput(&buf, OutputNDJSON)
+			require.NoError(t, err)
+
+			for _, d := range docs {
+				require.NoError(t, o.Write(d))
+			}
+
+			require.NoError(t, o.Flush())
+			assert.Equal(t, tt.exp, buf.String())
+		})
+	}
+}
+
+func TestOutputUnknown(t *testing.T) {
+	_, err := NewOutput(&bytes.Buffer{}, "xml")
+	require.ErrorIs(t, err, ErrUnknownOutput)
+
+	_, err = NewOutput(&bytes.Buffer{}, OutputTemplate+"{{.id")
+	require.Error(t, err)
+}
+
+func TestOutputEmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	o, err := NewOutput(&buf, OutputJSON)
+	require.NoError(t, err)
+
+	require.NoError(t, o.Flush())
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestOutputColumns(t *testing.T) {
+	var buf bytes.Buffer
+
+	o, err := NewOutput(&buf, OutputTable)
+	require.NoError(t, err)
+
+	o.SetColumns([]string{"status", "count"})
+
+	require.NoError(t, o.Write(map[string]any{"status": "new", "count": 2}))
+	require.NoError(t, o.Write(map[string]any{"status": "done", "count": 10}))
+	require.NoError(t, o.Flush())
+
+	assert.Equal(t, `status  count
+new     2
+done    10
+`, buf.String())
+}
+
+func TestFlattenDoc(t *testing.T) {
+	tests := []struct {
+		doc  any
+		want map[string]any
+	}{
+		{
+			doc: map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}, "d": map[string]any{}},
+			want: map[string]any{
+				"a.b.c": 1,
+				"d":     map[string]any{},
+			},
+		},
+		{
+			doc:  "users",
+			want: map[string]any{"value": "users"},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FlattenDoc(tt.doc))
+	}
+}
@@ -37,6 +37,10 @@ var (
 	DefaultTimeout = 5 * time.Second
 
 	Quiet bool
+
+	// Exit terminates the process on fatal errors.
+	// Interactive shell replaces it to continue with the next command.
+	Exit = os.Exit
 )
 
 func IsTTY(f *os.File) bool {
@@ -133,7 +137,7 @@ func Fatal(err error, msg string, args ...any) {
 
 	_ = Error(err, msg, args...)
 
-	os.Exit(1) //nolint:revive
+	Exit(1)
 }
 
 func InternalError(err error, msg string, args ...any) {
@@ -0,0 +1,147 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vector parses the vector similarity search parameters of the search commands.
+package vector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var (
+	ErrInvalidVector   = fmt.Errorf("vector should be in the form field=[number, ...]")
+	ErrEmptyVector     = fmt.Errorf("vector should have at least one dimension")
+	ErrDuplicateField  = fmt.Errorf("vector field is given more than once")
+	ErrInvalidNeighbor = fmt.Errorf("number of neighbors should be positive")
+)
+
+// Stdin is read when the vector file is "-".
+var Stdin io.Reader = os.Stdin
+
+// Vectors is the map of the vector fields to the search vectors.
+type Vectors map[string][]float64
+
+// Parse parses the vectors given as field=[number, ...] and the vector file.
+// The file is either field=path, with the JSON array of numbers in it,
+// or just the path, with the JSON object of the fields and arrays.
+// Path "-" reads standard input.
+func Parse(specs []string, file string) (Vectors, error) {
+	res := make(Vectors)
+
+	for _, s := range specs {
+		field, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidVector, s)
+		}
+
+		if err := res.add(field, []byte(value)); err != nil {
+			return nil, err
+		}
+	}
+
+	if file == "" {
+		return res, nil
+	}
+
+	field, path, ok := strings.Cut(file, "=")
+	if !ok {
+		field, path = "", file
+	}
+
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if field != "" {
+		return res, res.add(field, data)
+	}
+
+	var obj map[string]json.RawMessage
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidVector, path, err.Error())
+	}
+
+	for f, v := range obj {
+		if err = res.add(f, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(Stdin)
+	}
+
+	return os.ReadFile(path)
+}
+
+func (v Vectors) add(field string, value []byte) error {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidVector, value)
+	}
+
+	if _, ok := v[field]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateField, field)
+	}
+
+	var arr []float64
+	if err := json.Unmarshal(value, &arr); err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidVector, field, err.Error())
+	}
+
+	if len(arr) == 0 {
+		return fmt.Errorf("%w: %s", ErrEmptyVector, field)
+	}
+
+	v[field] = arr
+
+	return nil
+}
+
+// Apply sets the vectors of the search request.
+// The number of neighbors, if set, is the page size of the request,
+// so the pages of the nearest neighbors can be retrieved.
+func (v Vectors) Apply(req *driver.SearchRequest, neighbors int32) error {
+	if neighbors < 0 {
+		return ErrInvalidNeighbor
+	}
+
+	if len(v) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(map[string][]float64(v))
+	if err != nil {
+		return err
+	}
+
+	req.Vector = b
+
+	if neighbors > 0 {
+		req.PageSize = neighbors
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+
+	arrFile := filepath.Join(dir, "arr.json")
+	require.NoError(t, os.WriteFile(arrFile, []byte("[0.5, -1]\n"), 0o600))
+
+	objFile := filepath.Join(dir, "obj.json")
+	require.NoError(t, os.WriteFile(objFile, []byte(`{"a": [1], "b": [2, 3]}`), 0o600))
+
+	tests := []struct {
+		name  string
+		specs []string
+		file  string
+		exp   Vectors
+		err   error
+	}{
+		{"spec", []string{"emb=[1, 2.5, -3e-2]"}, "", Vectors{"emb": {1, 2.5, -0.03}}, nil},
+		{"specs", []string{"a=[1]", " b =[2]"}, "", Vectors{"a": {1}, "b": {2}}, nil},
+		{"none", nil, "", Vectors{}, nil},
+		{"field file", []string{"a=[1]"}, "emb=" + arrFile, Vectors{"a": {1}, "emb": {0.5, -1}}, nil},
+		{"object file", nil, objFile, Vectors{"a": {1}, "b": {2, 3}}, nil},
+		{"stdin", nil, "emb=-", Vectors{"emb": {4, 5}}, nil},
+		{"no field", []string{"[1]"}, "", nil, ErrInvalidVector},
+		{"empty field", []string{"=[1]"}, "", nil, ErrInvalidVector},
+		{"not numbers", []string{`a=["x"]`}, "", nil, ErrInvalidVector},
+		{"not array", []string{"a=1"}, "", nil, ErrInvalidVector},
+		{"empty", []string{"a=[]"}, "", nil, ErrEmptyVector},
+		{"duplicate", []string{"a=[1]"}, objFile, nil, ErrDuplicateField},
+		{"array file without field", nil, arrFile, nil, ErrInvalidVector},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Stdin = strings.NewReader("[4, 5]")
+			defer func() { Stdin = os.Stdin }()
+
+			v, err := Parse(tt.specs, tt.file)
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.exp, v)
+		})
+	}
+
+	_, err := Parse(nil, filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	req := &driver.SearchRequest{PageSize: 20}
+
+	require.NoError(t, Vectors{}.Apply(req, 5))
+	assert.Nil(t, req.Vector)
+	assert.Equal(t, int32(20), req.PageSize)
+
+	require.NoError(t, Vectors{"emb": {1, 2.5}}.Apply(req, 0))
+	assert.JSONEq(t, `{"emb": [1, 2.5]}`, string(req.Vector))
+	assert.Equal(t, int32(20), req.PageSize)
+
+	require.NoError(t, Vectors{"emb": {1}}.Apply(req, 5))
+	assert.JSONEq(t, `{"emb": [1]}`, string(req.Vector))
+	assert.Equal(t, int32(5), req.PageSize)
+
+	require.ErrorIs(t, Vectors{"emb": {1}}.Apply(req, -1), ErrInvalidNeighbor)
+}
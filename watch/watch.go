@@ -0,0 +1,166 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watch tracks new and changed documents of the collection,
+// which is polled by the monotonically increasing field, like updated_at or autogenerated key.
+//
+// Every poll reads the documents with the field greater than or equal to the last seen value,
+// so the documents having the same value are not missed. Such documents are deduplicated
+// by the primary key and the content, only the documents having the last value are remembered.
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tschema "github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var ErrNoField = fmt.Errorf("collection has no updatedAt, createdAt or autogenerated integer primary key field " +
+	"to follow, set it with --follow-field")
+
+// DetectField returns the field to follow: the field with updatedAt or createdAt attribute,
+// or autogenerated integer primary key.
+func DetectField(sch *schema.Schema) (string, error) {
+	for _, attr := range []func(f *schema.Field) bool{
+		func(f *schema.Field) bool { return f.UpdatedAt },
+		func(f *schema.Field) bool { return f.CreatedAt },
+	} {
+		var names []string
+
+		for name, f := range sch.Fields {
+			if attr(f) {
+				names = append(names, name)
+			}
+		}
+
+		// the first name for the stable choice
+		if len(names) > 0 {
+			res := names[0]
+			for _, n := range names[1:] {
+				if n < res {
+					res = n
+				}
+			}
+
+			return res, nil
+		}
+	}
+
+	if len(sch.PrimaryKey) == 1 {
+		if f := sch.Fields[sch.PrimaryKey[0]]; f != nil && f.AutoGenerate && f.Type.First() == "integer" {
+			return sch.PrimaryKey[0], nil
+		}
+	}
+
+	return "", ErrNoField
+}
+
+// Follower tracks the last seen value of the field and the documents having this value.
+type Follower struct {
+	field string
+	key   []string
+
+	last any
+	// seen are the documents having the last value, by primary key
+	seen map[string]string
+}
+
+func New(field string, primaryKey []string) *Follower {
+	return &Follower{field: field, key: primaryKey, seen: make(map[string]string)}
+}
+
+// Sort returns the read sort order, ascending by the followed field.
+// When descending is true, the order is reversed to read the last documents.
+func (f *Follower) Sort(descending bool) []byte {
+	order := "$asc"
+	if descending {
+		order = "$desc"
+	}
+
+	b, _ := json.Marshal([]map[string]string{{f.field: order}})
+
+	return b
+}
+
+// Filter returns the filter amended to read the documents starting from the last seen value.
+func (f *Follower) Filter(filter string) string {
+	if f.last == nil {
+		return filter
+	}
+
+	b, _ := json.Marshal(map[string]any{f.field: map[string]any{"$gte": f.last}})
+
+	if strings.TrimSpace(filter) == "" || strings.TrimSpace(filter) == "{}" {
+		return string(b)
+	}
+
+	return fmt.Sprintf(`{"$and":[%s,%s]}`, filter, b)
+}
+
+// Add records the document and returns true if it's new or changed since it has been seen.
+// Documents should be added in the ascending order of the field.
+func (f *Follower) Add(doc json.RawMessage) (bool, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return false, err
+	}
+
+	v := lookup(m, f.field)
+
+	switch c := tschema.CompareValues(v, f.last); {
+	case f.last == nil || c > 0:
+		f.last = v
+		f.seen = make(map[string]string)
+	case c < 0:
+		// stale document, which is before the last value
+		return false, nil
+	}
+
+	keys := make([]any, 0, len(f.key))
+	for _, k := range f.key {
+		keys = append(keys, lookup(m, k))
+	}
+
+	key, err := json.Marshal(keys)
+	if err != nil {
+		return false, err
+	}
+
+	var content bytes.Buffer
+	if err = json.Compact(&content, doc); err != nil {
+		return false, err
+	}
+
+	if f.seen[string(key)] == content.String() {
+		return false, nil
+	}
+
+	f.seen[string(key)] = content.String()
+
+	return true, nil
+}
+
+func lookup(doc map[string]any, path string) any {
+	v, _ := tschema.LookupValue(doc, path)
+
+	return v
+}
@@ -0,0 +1,152 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestDetectField(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		exp    string
+		err    error
+	}{
+		{
+			"updated_at",
+			`{"properties": {"id": {"type": "integer", "autoGenerate": true},
+				"created": {"type": "string", "format": "date-time", "createdAt": true},
+				"updated": {"type": "string", "format": "date-time", "updatedAt": true}}, "primary_key": ["id"]}`,
+			"updated", nil,
+		},
+		{
+			"created_at",
+			`{"properties": {"id": {"type": "integer", "autoGenerate": true},
+				"created": {"type": "string", "format": "date-time", "createdAt": true}}, "primary_key": ["id"]}`,
+			"created", nil,
+		},
+		{
+			"autogenerated key",
+			`{"properties": {"id": {"type": "integer", "autoGenerate": true}}, "primary_key": ["id"]}`,
+			"id", nil,
+		},
+		{
+			"uuid key",
+			`{"properties": {"id": {"type": "string", "format": "uuid", "autoGenerate": true}}, "primary_key": ["id"]}`,
+			"", ErrNoField,
+		},
+		{
+			"not autogenerated",
+			`{"properties": {"id": {"type": "integer"}}, "primary_key": ["id"]}`,
+			"", ErrNoField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sch schema.Schema
+			require.NoError(t, json.Unmarshal([]byte(tt.schema), &sch))
+
+			f, err := DetectField(&sch)
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.exp, f)
+		})
+	}
+}
+
+func TestFollower(t *testing.T) {
+	f := New("updated", []string{"id"})
+
+	assert.JSONEq(t, `[{"updated": "$asc"}]`, string(f.Sort(false)))
+	assert.JSONEq(t, `[{"updated": "$desc"}]`, string(f.Sort(true)))
+	assert.Equal(t, `{"a":1}`, f.Filter(`{"a":1}`))
+
+	polls := []struct {
+		docs   []string
+		exp    []bool
+		filter string
+	}{
+		{
+			[]string{
+				`{"id": 1, "updated": "2023-01-01T00:00:01Z"}`,
+				`{"id": 2, "updated": "2023-01-01T00:00:02Z"}`,
+				`{"id": 3, "updated": "2023-01-01T00:00:02Z"}`,
+			},
+			[]bool{true, true, true},
+			`{"updated":{"$gte":"2023-01-01T00:00:02Z"}}`,
+		},
+		{
+			// same documents at the last position are read again, id 3 has changed
+			[]string{
+				`{"id": 2, "updated": "2023-01-01T00:00:02Z"}`,
+				`{"id": 3, "updated": "2023-01-01T00:00:02Z", "name": "changed"}`,
+				`{"id": 4, "updated": "2023-01-01T00:00:02.5Z"}`,
+			},
+			[]bool{false, true, true},
+			`{"updated":{"$gte":"2023-01-01T00:00:02.5Z"}}`,
+		},
+		{
+			[]string{
+				`{"id": 1, "updated": "2023-01-01T00:00:01Z"}`,
+				`{"id": 4, "updated": "2023-01-01T00:00:02.5Z"}`,
+				`{"id": 1, "updated": "2023-01-01T00:00:03Z"}`,
+			},
+			[]bool{false, false, true},
+			`{"updated":{"$gte":"2023-01-01T00:00:03Z"}}`,
+		},
+	}
+
+	for _, p := range polls {
+		for i, d := range p.docs {
+			changed, err := f.Add(json.RawMessage(d))
+			require.NoError(t, err)
+			assert.Equal(t, p.exp[i], changed, d)
+		}
+
+		assert.Equal(t, p.filter, f.Filter(`{}`))
+	}
+
+	assert.Equal(t, `{"$and":[{"a":1},{"updated":{"$gte":"2023-01-01T00:00:03Z"}}]}`, f.Filter(`{"a":1}`))
+}
+
+func TestFollowerNumbers(t *testing.T) {
+	f := New("seq", []string{"a", "b"})
+
+	for _, tc := range []struct {
+		doc string
+		exp bool
+	}{
+		{`{"a": 1, "b": 1, "seq": 9}`, true},
+		{`{"a": 1, "b": 2, "seq": 10}`, true},
+		{`{"a": 1, "b": 2, "seq": 10}`, false},
+		{`{"a": 2, "b": 2, "seq": 10}`, true},
+		{`{"a": 1, "b": 1, "seq": 9}`, false},
+	} {
+		changed, err := f.Add(json.RawMessage(tc.doc))
+		require.NoError(t, err)
+		assert.Equal(t, tc.exp, changed, tc.doc)
+	}
+
+	assert.Equal(t, `{"seq":{"$gte":10}}`, f.Filter(""))
+
+	_, err := f.Add(json.RawMessage(`{`))
+	require.Error(t, err)
+}
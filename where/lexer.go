@@ -0,0 +1,139 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package where
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// twoCharOperators are the operators which are longer than one character.
+var twoCharOperators = []string{"==", "!=", "<>", "<=", ">="}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '.' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNumberRune(r rune) bool {
+	return r == '.' || r == 'e' || r == 'E' || r == '+' || r == '-' || unicode.IsDigit(r)
+}
+
+func tokenize(expr string) ([]*token, error) {
+	src := []rune(expr)
+
+	var res []*token
+
+	for i := 0; i < len(src); {
+		r := src[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+			continue
+		case r == '\'' || r == '"' || r == '`':
+			s, n, err := quoted(src, i)
+			if err != nil {
+				return nil, err
+			}
+
+			kind := tokenString
+			if r == '`' {
+				kind = tokenQuotedIdent
+			}
+
+			res = append(res, &token{kind: kind, text: s, pos: start})
+			i = n
+
+			continue
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(src) && unicode.IsDigit(src[i+1])):
+			i++
+			for i < len(src) && isNumberRune(src[i]) {
+				i++
+			}
+
+			res = append(res, &token{kind: tokenNumber, text: string(src[start:i]), pos: start})
+
+			continue
+		case isIdentRune(r):
+			i++
+			for i < len(src) && isIdentRune(src[i]) {
+				i++
+			}
+
+			res = append(res, &token{kind: tokenIdent, text: string(src[start:i]), pos: start})
+
+			continue
+		}
+
+		t := &token{kind: tokenPunct, text: string(r), pos: start}
+
+		for _, op := range twoCharOperators {
+			if strings.HasPrefix(string(src[i:]), op) {
+				t.text = op
+			}
+		}
+
+		i += len([]rune(t.text))
+
+		res = append(res, t)
+	}
+
+	return append(res, &token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// quoted returns the string quoted by the quote character at the position
+// and the position after the closing quote. The quote is escaped by doubling it or by backslash.
+func quoted(src []rune, pos int) (string, int, error) {
+	q := src[pos]
+
+	var b strings.Builder
+
+	for i := pos + 1; i < len(src); i++ {
+		switch r := src[i]; {
+		case r == '\\' && i+1 < len(src):
+			i++
+			b.WriteRune(src[i])
+		case r == q && i+1 < len(src) && src[i+1] == q:
+			i++
+			b.WriteRune(q)
+		case r == q:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: position %d: unterminated string", ErrSyntax, pos+1)
+}
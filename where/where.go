@@ -0,0 +1,394 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package where converts filter expressions, like
+//
+//	age > 23 and (city = 'SF' or vip = true)
+//
+// to Tigris filter JSON.
+//
+// Supported are comparison operators (=, !=, <>, <, <=, >, >=), and, or, not,
+// "field in (v1, v2)", "field not in (...)", regular expressions ("field ~ 'regex'" or "field regex 'regex'")
+// and date literals (date '2023-01-31', timestamp '2023-01-31 10:00:00').
+// Negation is pushed down to the comparisons, because Tigris filters have no $not operator.
+package where
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSyntax   = fmt.Errorf("filter syntax error")
+	ErrNegation = fmt.Errorf("regular expression match cannot be negated")
+	ErrDate     = fmt.Errorf("invalid date literal")
+)
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Parse parses the expression and returns the filter JSON.
+func Parse(expr string) ([]byte, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+
+	f, err := n.filter(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(f)
+}
+
+// node is the expression tree node, converted to the filter with negation applied if neg is true.
+type node interface {
+	filter(neg bool) (any, error)
+}
+
+type logicNode struct {
+	and  bool
+	args []node
+}
+
+func (n *logicNode) filter(neg bool) (any, error) {
+	// De Morgan's laws
+	and := n.and != neg
+
+	res := make([]any, 0, len(n.args))
+
+	for _, a := range n.args {
+		f, err := a.filter(neg)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, f)
+	}
+
+	if and {
+		return map[string]any{"$and": res}, nil
+	}
+
+	return map[string]any{"$or": res}, nil
+}
+
+type notNode struct {
+	arg node
+}
+
+func (n *notNode) filter(neg bool) (any, error) {
+	return n.arg.filter(!neg)
+}
+
+// negated maps the operators to the operators of the negated comparison.
+var negated = map[string]string{
+	"$eq":  "$ne",
+	"$ne":  "$eq",
+	"$gt":  "$lte",
+	"$gte": "$lt",
+	"$lt":  "$gte",
+	"$lte": "$gt",
+}
+
+type cmpNode struct {
+	field string
+	op    string
+	value any
+}
+
+func (n *cmpNode) filter(neg bool) (any, error) {
+	op := n.op
+
+	if neg {
+		if op = negated[n.op]; op == "" {
+			return nil, ErrNegation
+		}
+	}
+
+	if op == "$eq" {
+		return map[string]any{n.field: n.value}, nil
+	}
+
+	return map[string]any{n.field: map[string]any{op: n.value}}, nil
+}
+
+// inNode is the field in (v1, v2, ...) expression, which is converted to the disjunction of equalities.
+type inNode struct {
+	field  string
+	values []any
+}
+
+func (n *inNode) filter(neg bool) (any, error) {
+	l := &logicNode{}
+
+	for _, v := range n.values {
+		l.args = append(l.args, &cmpNode{field: n.field, op: "$eq", value: v})
+	}
+
+	if len(l.args) == 1 {
+		return l.args[0].filter(neg)
+	}
+
+	return l.filter(neg)
+}
+
+type parser struct {
+	tokens []*token
+	pos    int
+}
+
+func (p *parser) peek() *token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() *token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) errorf(t *token, format string, args ...any) error {
+	return fmt.Errorf("%w: position %d: %s", ErrSyntax, t.pos+1, fmt.Sprintf(format, args...))
+}
+
+// keyword checks that the next token is the keyword and consumes it.
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokenIdent && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) punct(text string) bool {
+	if t := p.peek(); t.kind == tokenPunct && t.text == text {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.punct(text) {
+		t := p.peek()
+		return p.errorf(t, "expected %q, got %q", text, t.text)
+	}
+
+	return nil
+}
+
+func (p *parser) or() (node, error) {
+	return p.logic(false, p.and)
+}
+
+func (p *parser) and() (node, error) {
+	return p.logic(true, p.not)
+}
+
+// logic parses the sequence of operands joined by the "and" or "or" keyword.
+func (p *parser) logic(and bool, operand func() (node, error)) (node, error) {
+	kw := "or"
+	if and {
+		kw = "and"
+	}
+
+	n, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &logicNode{and: and, args: []node{n}}
+
+	for p.keyword(kw) {
+		if n, err = operand(); err != nil {
+			return nil, err
+		}
+
+		l.args = append(l.args, n)
+	}
+
+	if len(l.args) == 1 {
+		return l.args[0], nil
+	}
+
+	return l, nil
+}
+
+func (p *parser) not() (node, error) {
+	if p.keyword("not") {
+		n, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notNode{arg: n}, nil
+	}
+
+	if p.punct("(") {
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		return n, p.expect(")")
+	}
+
+	return p.comparison()
+}
+
+var operators = map[string]string{
+	"=":  "$eq",
+	"==": "$eq",
+	"!=": "$ne",
+	"<>": "$ne",
+	">":  "$gt",
+	">=": "$gte",
+	"<":  "$lt",
+	"<=": "$lte",
+	"~":  "$regex",
+}
+
+func (p *parser) comparison() (node, error) {
+	t := p.next()
+	if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+		return nil, p.errorf(t, "expected field name, got %q", t.text)
+	}
+
+	field := t.text
+
+	switch {
+	case p.keyword("in"):
+		return p.in(field)
+	case p.keyword("not"):
+		if !p.keyword("in") {
+			return nil, p.errorf(p.peek(), "expected \"in\" after \"not\"")
+		}
+
+		n, err := p.in(field)
+
+		return &notNode{arg: n}, err
+	case p.keyword("regex"):
+		v, err := p.value()
+
+		return &cmpNode{field: field, op: "$regex", value: v}, err
+	}
+
+	t = p.next()
+
+	op, ok := operators[t.text]
+	if t.kind != tokenPunct || !ok {
+		return nil, p.errorf(t, "expected comparison operator, got %q", t.text)
+	}
+
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cmpNode{field: field, op: op, value: v}, nil
+}
+
+func (p *parser) in(field string) (node, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	n := &inNode{field: field}
+
+	for {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		n.values = append(n.values, v)
+
+		if !p.punct(",") {
+			break
+		}
+	}
+
+	return n, p.expect(")")
+}
+
+func (p *parser) value() (any, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+			return nil, p.errorf(t, "invalid number %q", t.text)
+		}
+
+		return json.Number(t.text), nil
+	case tokenString:
+		return t.text, nil
+	case tokenIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		case "date", "timestamp":
+			return p.date()
+		}
+	case tokenEOF, tokenQuotedIdent, tokenPunct:
+	}
+
+	return nil, p.errorf(t, "expected value, got %q", t.text)
+}
+
+// date parses the date literal and returns it in the RFC3339 format of Tigris date-time fields.
+func (p *parser) date() (any, error) {
+	t := p.next()
+	if t.kind != tokenString {
+		return nil, p.errorf(t, "expected date string, got %q", t.text)
+	}
+
+	for _, l := range dateLayouts {
+		if d, err := time.Parse(l, t.text); err == nil {
+			return d.Format(time.RFC3339Nano), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrDate, t.text)
+}
@@ -0,0 +1,97 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package where
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		exp  string
+	}{
+		{`id = 20`, `{"id": 20}`},
+		{`name == "Alice"`, `{"name": "Alice"}`},
+		{`address.city != 'O''Hare'`, `{"address.city": {"$ne": "O'Hare"}}`},
+		{`balance >= -1.5e3`, `{"balance": {"$gte": -1.5e3}}`},
+		{`vip = true and deleted = null`, `{"$and": [{"vip": true}, {"deleted": null}]}`},
+		{
+			`age > 23 and (city = 'SF' or vip = true)`,
+			`{"$and": [{"age": {"$gt": 23}}, {"$or": [{"city": "SF"}, {"vip": true}]}]}`,
+		},
+		{
+			`a = 1 or b = 2 and c = 3`,
+			`{"$or": [{"a": 1}, {"$and": [{"b": 2}, {"c": 3}]}]}`,
+		},
+		{`id in (2, 4)`, `{"$or": [{"id": 2}, {"id": 4}]}`},
+		{`id in (2)`, `{"id": 2}`},
+		{`status not in ('new', 'done')`, `{"$and": [{"status": {"$ne": "new"}}, {"status": {"$ne": "done"}}]}`},
+		{`name ~ '^Al'`, `{"name": {"$regex": "^Al"}}`},
+		{`name REGEX "^Al"`, `{"name": {"$regex": "^Al"}}`},
+		{
+			`NOT (age < 18 OR age >= 65)`,
+			`{"$and": [{"age": {"$gte": 18}}, {"age": {"$lt": 65}}]}`,
+		},
+		{`not not id = 1`, `{"id": 1}`},
+		{`created > date '2023-01-31'`, `{"created": {"$gt": "2023-01-31T00:00:00Z"}}`},
+		{
+			`created <= timestamp '2023-01-31 10:20:30'`,
+			`{"created": {"$lte": "2023-01-31T10:20:30Z"}}`,
+		},
+		{
+			"`first name` = 'Bob' and created < timestamp '2023-01-31T10:20:30.5+02:00'",
+			`{"$and": [{"first name": "Bob"}, {"created": {"$lt": "2023-01-31T10:20:30.5+02:00"}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.exp, string(f))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  error
+		msg  string
+	}{
+		{`id =`, ErrSyntax, "filter syntax error: position 5: expected value, got \"\""},
+		{`id = 1 and`, ErrSyntax, "filter syntax error: position 11: expected field name, got \"\""},
+		{`(id = 1`, ErrSyntax, "filter syntax error: position 8: expected \")\", got \"\""},
+		{`id 1`, ErrSyntax, "filter syntax error: position 4: expected comparison operator, got \"1\""},
+		{`id = 1 id = 2`, ErrSyntax, "filter syntax error: position 8: unexpected \"id\""},
+		{`name = 'Alice`, ErrSyntax, "filter syntax error: position 8: unterminated string"},
+		{`id = 1-2`, ErrSyntax, "filter syntax error: position 6: invalid number \"1-2\""},
+		{`id not (1)`, ErrSyntax, "filter syntax error: position 8: expected \"in\" after \"not\""},
+		{`not name ~ 'a'`, ErrNegation, "regular expression match cannot be negated"},
+		{`created > date 'yesterday'`, ErrDate, "invalid date literal: yesterday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.msg, err.Error())
+		})
+	}
+}